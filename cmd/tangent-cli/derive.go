@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/library"
+)
+
+// handleDeriveMicro proposes a micro variant of a regular character and
+// writes it as microstateregistry JSON
+func handleDeriveMicro(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Error: missing character name")
+		printAdminUsage()
+		os.Exit(1)
+	}
+	name := args[0]
+	var output string
+
+	for i := 1; i < len(args); i++ {
+		switch args[i] {
+		case "-o", "--output":
+			if i+1 >= len(args) {
+				fmt.Printf("Error: %s needs a value\n", args[i])
+				os.Exit(1)
+			}
+			output = args[i+1]
+			i++
+		default:
+			fmt.Printf("Error: unknown flag %s\n", args[i])
+			os.Exit(1)
+		}
+	}
+
+	char, err := library.Get(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	def, err := library.DeriveMicro(char)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote %dx%d micro proposal for %q (%d states) to %s\n", def.Width, def.Height, name, len(def.States), output)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wildreason/tangent/pkg/characters"
+	"github.com/wildreason/tangent/pkg/characters/asciicast"
+	"github.com/wildreason/tangent/pkg/characters/library"
+	"github.com/wildreason/tangent/pkg/characters/sprite"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// exportRequest holds the flags shared by the export formats
+type exportRequest struct {
+	character  string
+	state      string
+	size       library.Size
+	scale      int
+	theme      string
+	cellWidth  int
+	cellHeight int
+	background string
+	output     string
+
+	// Recording flags (cast only)
+	events  string
+	loops   int
+	seed    int64
+	title   string
+	profile string
+}
+
+// handleExport renders a character state to an image file, every state
+// to a sprite sheet, or states and event logs to an asciinema recording.
+//
+//	tangent-cli export svg|gif --character NAME --state STATE [--size SIZE] [--scale N]
+//	    [--theme NAME] [--cell WxH] [--background #hex] [-o file]
+//	tangent-cli export sheet --character NAME [--state STATE] ...
+//	tangent-cli export cast --character NAME --state S1,S2|--events log.jsonl [--loops N]
+//	    [--seed N] [--title T] [--color PROFILE] ...
+func handleExport(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Error: missing export format (svg, gif, sheet, cast)")
+		printUsage()
+		os.Exit(1)
+	}
+	format := args[0]
+	if format != "svg" && format != "gif" && format != "sheet" && format != "cast" {
+		fmt.Fprintf(os.Stderr, "Error: unknown export format '%s' (svg, gif, sheet, cast)\n", format)
+		os.Exit(1)
+	}
+	req := parseExportFlags(args[1:])
+	if format != "cast" && (req.events != "" || req.loops != 0 || req.seed != 0 || req.title != "" || req.profile != "") {
+		fmt.Println("Error: --events, --loops, --seed, --title and --color only apply to cast")
+		os.Exit(1)
+	}
+	if req.state == "" && format != "sheet" && req.events == "" {
+		fmt.Println("Error: --state is required")
+		os.Exit(1)
+	}
+
+	if format == "cast" {
+		// Recordings are played back in other terminals; default to the
+		// full palette rather than this terminal's
+		profile := termcolor.TrueColor
+		if req.profile != "" {
+			p, err := termcolor.ParseProfile(req.profile)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			profile = p
+		}
+		characters.SetColorProfile(profile)
+	}
+
+	agent := loadExportAgent(req)
+	// Raster formats scale pixels; SVG scales the character's cells
+	raster := sprite.RasterOptions{
+		CellWidth:  req.cellWidth,
+		CellHeight: req.cellHeight,
+		Scale:      req.scale,
+		Background: req.background,
+	}
+
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case "svg":
+		err = sprite.SVG(&buf, agent.Scaled(req.scale).GetCharacter(), req.state, sprite.SVGOptions{
+			CellWidth:  req.cellWidth,
+			CellHeight: req.cellHeight,
+			Background: req.background,
+		})
+	case "gif":
+		err = sprite.GIF(&buf, agent.GetCharacter(), req.state, raster)
+	case "sheet":
+		var states []string
+		if req.state != "" {
+			states = []string{req.state}
+		}
+		states, err = sprite.PNGSheet(&buf, agent.GetCharacter(), states, raster)
+		if err == nil {
+			fmt.Fprintf(os.Stderr, "Rows: %s\n", strings.Join(states, ", "))
+		}
+	case "cast":
+		err = writeCast(&buf, agent.Scaled(req.scale), req)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if req.output == "" {
+		os.Stdout.Write(buf.Bytes())
+		return
+	}
+	if err := os.WriteFile(req.output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	what := req.character
+	if req.state != "" {
+		what += " " + req.state
+	}
+	fmt.Printf("Wrote %s to %s\n", what, req.output)
+}
+
+// parseExportFlags parses the flags after the export format
+func parseExportFlags(args []string) exportRequest {
+	req := exportRequest{scale: 1}
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--micro" {
+			req.size = library.SizeMicro
+			continue
+		}
+		if i+1 >= len(args) {
+			fmt.Printf("Error: %s needs a value\n", args[i])
+			os.Exit(1)
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--character":
+			req.character = value
+		case "--state":
+			req.state = value
+		case "--size":
+			size, err := library.ParseSize(value)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			req.size = size
+		case "--scale":
+			req.scale = parsePositive("scale", value)
+		case "--theme":
+			req.theme = value
+		case "--cell":
+			req.cellWidth, req.cellHeight = parseDims("cell size", value)
+		case "--background":
+			req.background = value
+		case "-o", "--output":
+			req.output = value
+		case "--events":
+			req.events = value
+		case "--loops":
+			req.loops = parsePositive("loop count", value)
+		case "--seed":
+			seed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				fmt.Printf("Error: invalid seed %q\n", value)
+				os.Exit(1)
+			}
+			req.seed = seed
+		case "--title":
+			req.title = value
+		case "--color":
+			req.profile = value
+		default:
+			fmt.Printf("Error: unknown flag %s\n", args[i])
+			os.Exit(1)
+		}
+		i++
+	}
+
+	if req.character == "" {
+		fmt.Println("Error: --character is required")
+		os.Exit(1)
+	}
+	return req
+}
+
+// loadExportAgent loads the requested character in the requested theme
+func loadExportAgent(req exportRequest) *characters.AgentCharacter {
+	if req.theme != "" {
+		if err := characters.SetTheme(req.theme); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Available themes: %s\n", strings.Join(characters.ListThemes(), ", "))
+			os.Exit(1)
+		}
+	}
+
+	var agent *characters.AgentCharacter
+	var err error
+	if req.size != "" {
+		agent, err = characters.LibraryAgentSize(req.character, req.size)
+	} else {
+		agent, err = characters.LibraryAgent(req.character)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, state := range exportStates(req) {
+		if state != "base" && !agent.HasState(state) {
+			fmt.Fprintf(os.Stderr, "Error: state '%s' not found (available: %s)\n",
+				state, strings.Join(agent.ListStates(), ", "))
+			os.Exit(1)
+		}
+	}
+	return agent
+}
+
+// exportStates returns the states of --state, which takes a comma-separated
+// sequence for recordings
+func exportStates(req exportRequest) []string {
+	if req.state == "" {
+		return nil
+	}
+	return strings.Split(req.state, ",")
+}
+
+// writeCast records the --state sequence, or replays the --events log
+func writeCast(w io.Writer, agent *characters.AgentCharacter, req exportRequest) error {
+	opts := asciicast.Options{
+		Title:     req.title,
+		Loops:     req.loops,
+		Seed:      req.seed,
+		Timestamp: time.Now().Unix(),
+	}
+	if req.events == "" {
+		return asciicast.WriteStates(w, agent, exportStates(req), opts)
+	}
+
+	f, err := os.Open(req.events)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	events, err := asciicast.ParseEvents(f)
+	if err != nil {
+		return fmt.Errorf("%s: %w", req.events, err)
+	}
+	return asciicast.WriteEvents(w, agent, events, 0, opts)
+}
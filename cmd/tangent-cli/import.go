@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/infrastructure"
+	"github.com/wildreason/tangent/pkg/characters/sprite"
+	"github.com/wildreason/tangent/pkg/characters/stateregistry"
+)
+
+// decompileInput converts a pasted line of block art to pattern codes.
+// Typed codes pass through, also when mixed with block art. Lines of typed
+// codes are trimmed, but block art keeps its spaces: they are empty cells.
+func decompileInput(line string) string {
+	line = strings.TrimRight(line, "\r\n")
+	if utf8.RuneCountInString(line) == len(line) {
+		return strings.TrimSpace(line)
+	}
+	decompiler := infrastructure.NewPatternCompiler().(domain.PatternDecompiler)
+	pattern, err := decompiler.Decompile(line)
+	var decompileErr *domain.DecompileError
+	if errors.As(err, &decompileErr) {
+		for _, u := range decompileErr.Unmapped {
+			if u.Rune >= utf8.RuneSelf {
+				fmt.Printf("  ✗ Error: %v\n", err)
+				return line
+			}
+		}
+	}
+	return pattern
+}
+
+// handleImport converts a text file of block-art frames, or a PNG sprite
+// sheet, to state JSON. Text frames are separated by "---" lines, or by
+// blank lines when the file has none.
+func handleImport(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Error: missing block-art file or sprite sheet")
+		printUsage()
+		os.Exit(1)
+	}
+	path := args[0]
+	isPNG := strings.EqualFold(filepath.Ext(path), ".png")
+	state := stateregistry.StateDefinition{
+		Name: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+	}
+	var output string
+	var grid sprite.Grid
+	var opts sprite.Options
+
+	for i := 1; i < len(args); i++ {
+		if i+1 >= len(args) {
+			fmt.Printf("Error: %s needs a value\n", args[i])
+			os.Exit(1)
+		}
+		value := args[i+1]
+		if !isPNG && (args[i] == "--grid" || args[i] == "--cell" || args[i] == "--frames" ||
+			args[i] == "--tolerance" || args[i] == "--background") {
+			fmt.Printf("Error: %s only applies to PNG sprite sheets\n", args[i])
+			os.Exit(1)
+		}
+		switch args[i] {
+		case "--state":
+			state.Name = value
+		case "--fps":
+			state.FPS = parsePositive("fps", value)
+		case "--grid":
+			grid.Columns, grid.Rows = parseDims("grid", value)
+		case "--cell":
+			grid.CellWidth, grid.CellHeight = parseDims("cell size", value)
+		case "--frames":
+			grid.Frames = parsePositive("frame count", value)
+		case "--tolerance":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 255 {
+				fmt.Printf("Error: invalid tolerance %q (0-255)\n", value)
+				os.Exit(1)
+			}
+			opts.Tolerance = n
+		case "--background":
+			opts.Background = value
+		case "-o", "--output":
+			output = value
+		default:
+			fmt.Printf("Error: unknown flag %s\n", args[i])
+			os.Exit(1)
+		}
+		i++
+	}
+
+	if isPNG {
+		if grid.Columns == 0 {
+			grid.Columns, grid.Rows = 1, 1
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		sheet, err := sprite.Import(f, state.Name, grid, opts)
+		f.Close()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		sheet.State.FPS = state.FPS
+		state = sheet.State
+		fmt.Fprintf(os.Stderr, "Suggested color: %s\n", sheet.Color)
+	} else {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		frames, err := decompileFrames(string(data))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", path, err)
+			os.Exit(1)
+		}
+		state.Frames = frames
+	}
+
+	out, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if output == "" {
+		fmt.Println(string(out))
+		return
+	}
+	if err := os.WriteFile(output, append(out, '\n'), 0644); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote state %q (%d frames) to %s\n", state.Name, len(state.Frames), output)
+}
+
+// parsePositive parses a flag value that must be a positive integer
+func parsePositive(what, value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		fmt.Printf("Error: invalid %s %q\n", what, value)
+		os.Exit(1)
+	}
+	return n
+}
+
+// parseDims parses a "WxH" flag value
+func parseDims(what, value string) (int, int) {
+	w, h, ok := strings.Cut(strings.ToLower(value), "x")
+	if !ok {
+		fmt.Printf("Error: invalid %s %q (want WxH)\n", what, value)
+		os.Exit(1)
+	}
+	return parsePositive(what, w), parsePositive(what, h)
+}
+
+// decompileFrames splits block art into frames and converts them to
+// pattern codes. Short lines are padded to the widest line; every
+// unmappable rune is reported.
+func decompileFrames(text string) ([]stateregistry.StateFrame, error) {
+	text = strings.TrimRight(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	lines := strings.Split(text, "\n")
+	separator := func(line string) bool { return strings.TrimSpace(line) == "" }
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "---" {
+			separator = func(line string) bool { return strings.TrimSpace(line) == "---" }
+			break
+		}
+	}
+
+	var blocks [][]string
+	var current []string
+	for _, line := range lines {
+		if separator(line) {
+			if len(current) > 0 {
+				blocks = append(blocks, current)
+				current = nil
+			}
+			continue
+		}
+		current = append(current, strings.TrimRight(line, " \t"))
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, current)
+	}
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("no frames")
+	}
+
+	width := 0
+	for _, block := range blocks {
+		if len(block) != len(blocks[0]) {
+			return nil, fmt.Errorf("frames have %d and %d lines", len(blocks[0]), len(block))
+		}
+		for _, line := range block {
+			width = max(width, utf8.RuneCountInString(line))
+		}
+	}
+
+	decompiler := infrastructure.NewPatternCompiler().(domain.PatternDecompiler)
+	frames := make([]stateregistry.StateFrame, len(blocks))
+	var errs []error
+	for f, block := range blocks {
+		for l, line := range block {
+			line += strings.Repeat(" ", width-utf8.RuneCountInString(line))
+			pattern, err := decompiler.Decompile(line)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("frame %d line %d: %w", f+1, l+1, err))
+			}
+			frames[f].Lines = append(frames[f].Lines, pattern)
+		}
+	}
+	return frames, errors.Join(errs...)
+}
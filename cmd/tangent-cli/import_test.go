@@ -0,0 +1,72 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecompileFrames(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name: "dashed separators",
+			text: "▐█▌\n\n▄▄▄\n---\n▐▀▌\n \n▄▄▄\n",
+			want: [][]string{{"RFL", "___", "BBB"}, {"RTL", "___", "BBB"}},
+		},
+		{
+			name: "blank separators and padding",
+			text: "▐█\r\n\r\n▐█▌\r\n",
+			want: [][]string{{"RF_"}, {"RFL"}},
+		},
+		{name: "uneven heights", text: "█\n█\n---\n█\n", wantErr: "frames have 2 and 1 lines"},
+		{name: "unmappable", text: "█x\n\n▔█\n", wantErr: "frame 2 line 1"},
+		{name: "empty", text: "\n\n", wantErr: "no frames"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			frames, err := decompileFrames(tt.text)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("decompileFrames() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decompileFrames() error = %v", err)
+			}
+			var got [][]string
+			for _, frame := range frames {
+				got = append(got, frame.Lines)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decompileFrames() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecompileInput(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"FRL__", "FRL__"},
+		{"▐█▌  ", "RFL__"},
+		{"  ▐█▌\n", "__RFL"},
+		{" FRL \r\n", "FRL"},
+		{"F█▌", "FFL"},
+		{"▔█", "▔█"}, // Unmappable: left for the width check to reject
+	}
+
+	for _, tt := range tests {
+		if got := decompileInput(tt.input); got != tt.want {
+			t.Errorf("decompileInput(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/wildreason/tangent/pkg/characters"
+	"github.com/wildreason/tangent/pkg/characters/library"
+	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+	"github.com/wildreason/tangent/pkg/characters/stateregistry"
 )
 
 // handleList shows a simple list of available agents
@@ -37,7 +42,9 @@ func handleListAgent(name string) {
 	var targetState string
 	var overrideFPS int
 	var overrideLoops int
-	var useMicro bool
+	var size library.Size
+	var glyphSet string
+	scale := 1
 
 	// Parse flags from os.Args starting from index 3 (after "tangent browse <name>")
 	for i := 3; i < len(os.Args); i++ {
@@ -62,24 +69,52 @@ func handleListAgent(name string) {
 				i++
 			}
 		case "--micro":
-			useMicro = true
+			size = library.SizeMicro
+		case "--size":
+			if i+1 < len(os.Args) {
+				parsed, err := library.ParseSize(os.Args[i+1])
+				if err != nil {
+					fmt.Printf("Error: %v\n", err)
+					os.Exit(1)
+				}
+				size = parsed
+				i++
+			}
+		case "--glyphs":
+			if i+1 < len(os.Args) {
+				glyphSet = os.Args[i+1]
+				i++
+			}
+		case "--scale":
+			if i+1 < len(os.Args) {
+				n, err := strconv.Atoi(os.Args[i+1])
+				if err != nil || n < 1 {
+					fmt.Printf("Error: invalid scale %q\n", os.Args[i+1])
+					os.Exit(1)
+				}
+				scale = n
+				i++
+			}
 		}
 	}
 
-	// Load character (micro or standard)
+	// Load character (size variant or standard)
 	var agent *characters.AgentCharacter
 	var err error
-	if useMicro {
-		agent, err = characters.LibraryAgentMicro(name)
+	if size != "" {
+		agent, err = characters.LibraryAgentSize(name, size)
 		if err != nil {
-			fmt.Printf("Error: micro agent '%s' not found\n", name)
-			fmt.Println("Available micro agents:")
-			names := characters.ListMicroLibrary()
-			for _, n := range names {
+			fmt.Printf("Error: agent '%s' not found\n", name)
+			fmt.Println("Available agents:")
+			for _, n := range characters.ListLibrary() {
 				fmt.Printf("  • %s\n", n)
 			}
 			os.Exit(1)
 		}
+		if variant, _ := library.GetSizeVariant(size); agent.GetCharacter().Width != variant.Width || agent.GetCharacter().Height != variant.Height {
+			fmt.Printf("Note: no %s design for '%s', showing nearest size (available: %s)\n",
+				size, name, joinSizes(library.ListSizes(name)))
+		}
 	} else {
 		agent, err = characters.LibraryAgent(name)
 		if err != nil {
@@ -93,6 +128,15 @@ func handleListAgent(name string) {
 		}
 	}
 
+	if glyphSet != "" {
+		if err := agent.SetGlyphSet(glyphSet); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			fmt.Printf("Available glyph sets: %s\n", strings.Join(patterns.ListGlyphSets(), ", "))
+			os.Exit(1)
+		}
+	}
+
+	agent = agent.Scaled(scale)
 	char := agent.GetCharacter()
 
 	// Print agent header
@@ -150,10 +194,11 @@ func handleListAgent(name string) {
 				}
 			}
 
+			sources := stateSources(name, char.Width/scale, char.Height/scale)
 			for _, stateName := range stateNames {
 				state := char.States[stateName]
-				fmt.Printf("  • %s (%d frames, %d FPS, %d loops)\n",
-					stateName, len(state.Frames), state.AnimationFPS, state.AnimationLoops)
+				fmt.Printf("  • %s (%d frames, %d FPS, %d loops)%s\n",
+					stateName, len(state.Frames), state.AnimationFPS, state.AnimationLoops, sources[stateName])
 			}
 			fmt.Println()
 		}
@@ -191,3 +236,31 @@ func handleListAgent(name string) {
 
 	fmt.Println("✅ View complete!")
 }
+
+// joinSizes lists size names separated by commas
+func joinSizes(sizes []library.Size) string {
+	names := make([]string, len(sizes))
+	for i, size := range sizes {
+		names[i] = string(size)
+	}
+	return strings.Join(names, ", ")
+}
+
+// stateSources labels the states a character has its own design for
+func stateSources(name string, width, height int) map[string]string {
+	labels := make(map[string]string)
+	if micro, _ := library.GetSizeVariant(library.SizeMicro); width == micro.Width && height == micro.Height {
+		for _, state := range microstateregistry.ListStates() {
+			if microstateregistry.Overridden(name, state) {
+				labels[state] = " [own]"
+			}
+		}
+		return labels
+	}
+	for _, listing := range stateregistry.ListStates(name) {
+		if listing.Source != stateregistry.SourceInherited {
+			labels[listing.Name] = " [" + string(listing.Source) + "]"
+		}
+	}
+	return labels
+}
@@ -217,11 +217,11 @@ func characterBuilder(session *Session) {
 			if err := session.Save(); err != nil {
 				handleError("Failed to save session", err)
 			} else {
-				fmt.Println("✓ Progress saved. Goodbye!\n")
+				fmt.Print("✓ Progress saved. Goodbye!\n\n")
 			}
 			os.Exit(0)
 		default:
-			fmt.Println("✗ Invalid option\n")
+			fmt.Print("✗ Invalid option\n\n")
 		}
 	}
 }
@@ -282,7 +282,7 @@ func addFrame(session *Session) {
 	frameName = strings.TrimSpace(frameName)
 
 	if frameName == "" {
-		fmt.Println("✗ State name cannot be empty\n")
+		fmt.Print("✗ State name cannot be empty\n\n")
 		return
 	}
 
@@ -313,8 +313,10 @@ func addFrame(session *Session) {
 	for i := 0; i < session.Height; i++ {
 		for {
 			fmt.Printf("◢ Line %d/%d: ", i+1, session.Height)
-			line, _ := reader.ReadString('\n')
-			line = strings.TrimSpace(line)
+			raw, _ := reader.ReadString('\n')
+
+			// Pasted block art becomes pattern codes
+			line := decompileInput(raw)
 
 			// Apply mirroring
 			line = applyMirroring(line)
@@ -376,7 +378,7 @@ func addFrame(session *Session) {
 
 func duplicateFrame(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to duplicate\n")
+		fmt.Print("\n✗ No frames to duplicate\n\n")
 		return
 	}
 
@@ -398,7 +400,7 @@ func duplicateFrame(session *Session) {
 	}
 
 	if frameIdx == -1 {
-		fmt.Println("✗ Invalid frame\n")
+		fmt.Print("✗ Invalid frame\n\n")
 		return
 	}
 
@@ -410,7 +412,7 @@ func duplicateFrame(session *Session) {
 	newName = strings.TrimSpace(newName)
 
 	if newName == "" {
-		fmt.Println("✗ Frame name cannot be empty\n")
+		fmt.Print("✗ Frame name cannot be empty\n\n")
 		return
 	}
 
@@ -446,7 +448,7 @@ func duplicateFrame(session *Session) {
 
 func editFrame(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to edit\n")
+		fmt.Print("\n✗ No frames to edit\n\n")
 		return
 	}
 
@@ -468,7 +470,7 @@ func editFrame(session *Session) {
 	}
 
 	if frameIdx == -1 {
-		fmt.Println("✗ Invalid frame\n")
+		fmt.Print("✗ Invalid frame\n\n")
 		return
 	}
 
@@ -495,7 +497,7 @@ func editFrame(session *Session) {
 	}
 
 	if lineIdx == -1 {
-		fmt.Println("✗ Invalid line\n")
+		fmt.Print("✗ Invalid line\n\n")
 		return
 	}
 
@@ -518,13 +520,13 @@ func editFrame(session *Session) {
 	if strings.ToLower(strings.TrimSpace(confirm)) == "y" {
 		frame.Lines[lineIdx] = newLine
 		session.Save()
-		fmt.Println("✓ Line updated!\n")
+		fmt.Print("✓ Line updated!\n\n")
 	}
 }
 
 func previewCharacter(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to preview\n")
+		fmt.Print("\n✗ No frames to preview\n\n")
 		return
 	}
 
@@ -543,12 +545,12 @@ func previewCharacter(session *Session) {
 
 func animateCharacter(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to animate\n")
+		fmt.Print("\n✗ No frames to animate\n\n")
 		return
 	}
 
 	if len(session.Frames) == 1 {
-		fmt.Println("\n✗ Need at least 2 frames\n")
+		fmt.Print("\n✗ Need at least 2 frames\n\n")
 		return
 	}
 
@@ -566,7 +568,7 @@ func animateCharacter(session *Session) {
 	}
 
 	fmt.Printf("◢ Animating '%s' with %d frames at 5 FPS for 3 cycles\n", session.Name, len(session.Frames))
-	fmt.Println("◢ Press Ctrl+C to stop\n")
+	fmt.Print("◢ Press Ctrl+C to stop\n\n")
 
 	// Animate using AgentCharacter
 	agent := characters.NewAgentCharacter(character)
@@ -575,12 +577,12 @@ func animateCharacter(session *Session) {
 		return
 	}
 
-	fmt.Println("\n✓ Animation complete!\n")
+	fmt.Print("\n✓ Animation complete!\n\n")
 }
 
 func exportCode(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to export\n")
+		fmt.Print("\n✗ No frames to export\n\n")
 		return
 	}
 
@@ -615,7 +617,7 @@ func exportCode(session *Session) {
 
 func saveToFile(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to export\n")
+		fmt.Print("\n✗ No frames to export\n\n")
 		return
 	}
 
@@ -656,7 +658,7 @@ func saveToFile(session *Session) {
 	fmt.Print("◢ Confirm? (y/n): ")
 	confirm, _ := reader.ReadString('\n')
 	if strings.ToLower(strings.TrimSpace(confirm)) != "y" {
-		fmt.Println("✗ Cancelled\n")
+		fmt.Print("✗ Cancelled\n\n")
 		return
 	}
 
@@ -730,7 +732,7 @@ func capitalize(s string) string {
 
 func deleteFrame(session *Session) {
 	if len(session.Frames) == 0 {
-		fmt.Println("\n✗ No frames to delete\n")
+		fmt.Print("\n✗ No frames to delete\n\n")
 		return
 	}
 
@@ -752,7 +754,7 @@ func deleteFrame(session *Session) {
 	}
 
 	if frameIdx == -1 {
-		fmt.Println("✗ Invalid frame\n")
+		fmt.Print("✗ Invalid frame\n\n")
 		return
 	}
 
@@ -763,7 +765,7 @@ func deleteFrame(session *Session) {
 	if strings.ToLower(strings.TrimSpace(confirm)) == "y" {
 		session.Frames = append(session.Frames[:frameIdx], session.Frames[frameIdx+1:]...)
 		session.Save()
-		fmt.Println("✓ Frame deleted\n")
+		fmt.Print("✓ Frame deleted\n\n")
 	}
 }
 
@@ -863,6 +865,14 @@ func handleCLI() {
 
 	command := os.Args[1]
 
+	// User packs and themes from $XDG_CONFIG_HOME/tangent
+	if _, err := library.LoadUserPacks(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: user packs: %v\n", err)
+	}
+	if _, err := library.LoadUserThemes(); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: user themes: %v\n", err)
+	}
+
 	switch command {
 	case "create":
 		showBanner()
@@ -877,6 +887,10 @@ func handleCLI() {
 		}
 	case "view":
 		handleView(os.Args[2:])
+	case "import":
+		handleImport(os.Args[2:])
+	case "export":
+		handleExport(os.Args[2:])
 	case "admin":
 		handleAdminCLI()
 	case "version", "--version", "-v":
@@ -890,14 +904,22 @@ func handleCLI() {
 func printUsage() {
 	fmt.Println("tangent-cli - Internal development tool for Tangent")
 	fmt.Println()
-	fmt.Println("tangent-cli browse [name] [--state S] [--fps N] [--loops N] [--micro]")
+	fmt.Println("tangent-cli browse [name] [--state S] [--fps N] [--loops N] [--size SIZE] [--micro] [--scale N] [--glyphs SET]")
 	fmt.Println("tangent-cli create")
 	fmt.Println("tangent-cli edit [state] --micro")
+	fmt.Println("tangent-cli import <art.txt> [--state NAME] [--fps N] [-o state.json]")
+	fmt.Println("tangent-cli import <sheet.png> --grid CxR [--cell WxH] [--frames N] [--tolerance N] [--background #hex] [--state NAME] [--fps N] [-o state.json]")
+	fmt.Println("tangent-cli export svg|gif --character NAME --state STATE [--size SIZE] [--scale N] [--theme NAME] [--cell WxH] [--background #hex] [-o file]")
+	fmt.Println("tangent-cli export cast --character NAME (--state S1,S2,... [--loops N] | --events log.jsonl) [--size SIZE] [--scale N] [--theme NAME] [--seed N] [--title T] [--color PROFILE] [-o file.cast]")
+	fmt.Println("tangent-cli export sheet --character NAME [--state STATE] [--size SIZE] [--scale N] [--theme NAME] [--cell WxH] [--background #hex] [-o file.png]")
 	fmt.Println("tangent-cli admin <command>")
 	fmt.Println("tangent-cli version")
 	fmt.Println()
 	fmt.Println("Flags:")
 	fmt.Println("  --micro    Use micro (8x2) avatar variant")
+	fmt.Println("  --size     Size variant: nano, micro, regular, large (nearest size if missing)")
+	fmt.Println("  --scale    Scale up N times for large displays")
+	fmt.Println("  --glyphs   Glyph set: unicode, safe, ascii, braille")
 }
 
 func handleAdminCLI() {
@@ -940,6 +962,16 @@ func handleAdminCLI() {
 			os.Exit(1)
 		}
 		adminBatchRegister(os.Args[3], os.Args[4])
+	case "check-themes":
+		handleCheckThemes(os.Args[3:])
+	case "generate-theme":
+		handleGenerateTheme(os.Args[3:])
+	case "check-pack":
+		handleCheckPack(os.Args[3:])
+	case "seal-pack":
+		handleSealPack(os.Args[3:])
+	case "derive-micro":
+		handleDeriveMicro(os.Args[3:])
 	default:
 		fmt.Fprintf(os.Stderr, "Error: unknown admin command '%s'\n\n", subcommand)
 		printAdminUsage()
@@ -951,6 +983,11 @@ func printAdminUsage() {
 	fmt.Println("tangent-cli admin export <character>")
 	fmt.Println("tangent-cli admin register <json> [--force]")
 	fmt.Println("tangent-cli admin batch-register <template> <colors>")
+	fmt.Println("tangent-cli admin check-themes [theme...] [--min-contrast N] [--min-distance D]")
+	fmt.Println("tangent-cli admin generate-theme <name> [--hue H | --seed #hex,...] [--harmony even|analogous|triadic] [--affinity dark|light] [-o file]")
+	fmt.Println("tangent-cli admin check-pack <dir|archive.zip> [--key public.pem]... [--require-checksums]")
+	fmt.Println("tangent-cli admin seal-pack <dir> [--key private.pem]")
+	fmt.Println("tangent-cli admin derive-micro <character> [-o file]")
 }
 
 func adminRegister(jsonPath string, forceUpdate bool) {
@@ -1573,8 +1610,8 @@ func createBaseCharacter(session *Session) {
 			}
 
 			fmt.Printf("◢ Line %d/%d: ", i+1, session.Height)
-			line, _ := reader.ReadString('\n')
-			line = strings.TrimSpace(line)
+			raw, _ := reader.ReadString('\n')
+			line := strings.TrimSpace(raw)
 
 			// Toggle live preview
 			if line == "p" {
@@ -1587,6 +1624,9 @@ func createBaseCharacter(session *Session) {
 				continue
 			}
 
+			// Pasted block art becomes pattern codes
+			line = decompileInput(raw)
+
 			// Apply mirroring
 			line = applyMirroring(line)
 
@@ -1632,13 +1672,13 @@ func createBaseCharacter(session *Session) {
 		Lines: lines,
 	}
 	session.Save()
-	fmt.Println("\n✓ Base character created! Now add animated states.\n")
+	fmt.Print("\n✓ Base character created! Now add animated states.\n\n")
 }
 
 // previewBaseCharacter shows the base character
 func previewBaseCharacter(session *Session) {
 	if len(session.BaseFrame.Lines) == 0 {
-		fmt.Println("\n✗ No base character created yet\n")
+		fmt.Print("\n✗ No base character created yet\n\n")
 		return
 	}
 
@@ -1658,7 +1698,7 @@ func addAgentStateWithBase(session *Session) {
 
 	// Check if base exists
 	if len(session.BaseFrame.Lines) == 0 {
-		fmt.Println("\n✗ Create base character first!\n")
+		fmt.Print("\n✗ Create base character first!\n\n")
 		return
 	}
 
@@ -1715,7 +1755,7 @@ func addAgentStateWithBase(session *Session) {
 	stateName = strings.TrimSpace(stateName)
 
 	if stateName == "" {
-		fmt.Println("✗ State name cannot be empty\n")
+		fmt.Print("✗ State name cannot be empty\n\n")
 		return
 	}
 
@@ -1774,9 +1814,9 @@ func addAgentStateWithBase(session *Session) {
 		if startFromBase == "y" {
 			// Copy base lines
 			copy(lines, session.BaseFrame.Lines)
-			fmt.Println("  ✓ Copied base. Edit lines as needed (press Enter to keep):\n")
+			fmt.Print("  ✓ Copied base. Edit lines as needed (press Enter to keep):\n\n")
 		} else {
-			fmt.Println("  Creating from scratch:\n")
+			fmt.Print("  Creating from scratch:\n\n")
 		}
 
 		fmt.Println(patterns.GetPatternHelp())
@@ -1801,8 +1841,8 @@ func addAgentStateWithBase(session *Session) {
 					fmt.Printf("◢ Line %d/%d: ", i+1, session.Height)
 				}
 
-				line, _ := reader.ReadString('\n')
-				line = strings.TrimSpace(line)
+				raw, _ := reader.ReadString('\n')
+				line := strings.TrimSpace(raw)
 
 				// Toggle live preview
 				if line == "p" {
@@ -1822,6 +1862,9 @@ func addAgentStateWithBase(session *Session) {
 					break
 				}
 
+				// Pasted block art becomes pattern codes
+				line = decompileInput(raw)
+
 				// Apply mirroring
 				line = applyMirroring(line)
 
@@ -1977,7 +2020,7 @@ func previewDualPane(session *Session, stateName string) {
 	fmt.Println("╚══════════════════════════════════════════════════════════════╝")
 	fmt.Println()
 	fmt.Println("  Left: Formation (what you're building now)")
-	fmt.Println("  Right: End-state (as it will animate)\n")
+	fmt.Print("  Right: End-state (as it will animate)\n\n")
 
 	// Hide cursor
 	fmt.Print("\x1b[?25l")
@@ -2021,7 +2064,7 @@ func previewDualPane(session *Session, stateName string) {
 		}
 		fmt.Printf("\r\x1b[2K  %-*s    %s\n", width, lc, rc)
 	}
-	fmt.Println("\n✓ Preview complete. Press Enter to return.\n")
+	fmt.Print("\n✓ Preview complete. Press Enter to return.\n\n")
 	bufio.NewReader(os.Stdin).ReadString('\n')
 }
 
@@ -2110,7 +2153,7 @@ func repeatString(s string, count int) string {
 // editAgentState edits an existing agent state
 func editAgentState(session *Session) {
 	if len(session.States) == 0 {
-		fmt.Println("\n✗ No states to edit\n")
+		fmt.Print("\n✗ No states to edit\n\n")
 		return
 	}
 
@@ -2135,7 +2178,7 @@ func editAgentState(session *Session) {
 	}
 
 	if stateIdx == -1 {
-		fmt.Println("✗ Invalid state\n")
+		fmt.Print("✗ Invalid state\n\n")
 		return
 	}
 
@@ -2170,19 +2213,19 @@ func editAgentState(session *Session) {
 			session.Save()
 			fmt.Printf("\n  ✓ Animation speed updated to %d FPS\n\n", fps)
 		} else {
-			fmt.Println("\n  ✗ Invalid FPS\n")
+			fmt.Print("\n  ✗ Invalid FPS\n\n")
 		}
 	case "5":
 		return
 	default:
-		fmt.Println("\n✗ Invalid option\n")
+		fmt.Print("\n✗ Invalid option\n\n")
 	}
 }
 
 // previewStateAnimation previews a single state's animation
 func previewStateAnimation(session *Session) {
 	if len(session.States) == 0 {
-		fmt.Println("\n✗ No states to preview\n")
+		fmt.Print("\n✗ No states to preview\n\n")
 		return
 	}
 
@@ -2207,14 +2250,14 @@ func previewStateAnimation(session *Session) {
 	}
 
 	if stateIdx == -1 {
-		fmt.Println("✗ Invalid state\n")
+		fmt.Print("✗ Invalid state\n\n")
 		return
 	}
 
 	state := session.States[stateIdx]
 
 	fmt.Printf("\n◢ Animating '%s' state with %d frames at %d FPS for 2 cycles\n", state.Name, len(state.Frames), state.AnimationFPS)
-	fmt.Println("◢ Press Ctrl+C to stop\n")
+	fmt.Print("◢ Press Ctrl+C to stop\n\n")
 
 	// Create temporary character for animation
 	tempChar := &domain.Character{
@@ -2238,13 +2281,13 @@ func previewStateAnimation(session *Session) {
 		return
 	}
 
-	fmt.Println("\n✓ Animation complete!\n")
+	fmt.Print("\n✓ Animation complete!\n\n")
 }
 
 // previewAllStates previews all states in sequence
 func previewAllStates(session *Session) {
 	if len(session.States) == 0 {
-		fmt.Println("\n✗ No states to preview\n")
+		fmt.Print("\n✗ No states to preview\n\n")
 		return
 	}
 
@@ -2298,5 +2341,5 @@ func previewAllStates(session *Session) {
 		fmt.Println()
 	}
 
-	fmt.Println("✓ All states previewed!\n")
+	fmt.Print("✓ All states previewed!\n\n")
 }
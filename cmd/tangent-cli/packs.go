@@ -0,0 +1,187 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/library"
+)
+
+// handleCheckPack loads a character pack and lists what it provides.
+// Exits with status 1 when the pack doesn't load.
+//
+//	tangent-cli admin check-pack <dir|archive.zip> [--key public.pem]... [--require-checksums]
+func handleCheckPack(args []string) {
+	var opts library.PackOptions
+	var target string
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--key":
+			if i+1 >= len(args) {
+				fmt.Println("Error: --key needs a value")
+				os.Exit(1)
+			}
+			key, err := readPublicKey(args[i+1])
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			opts.PublicKeys = append(opts.PublicKeys, key)
+			i++
+		case "--require-checksums":
+			opts.RequireChecksums = true
+		default:
+			target = args[i]
+		}
+	}
+	if target == "" {
+		fmt.Println("Error: missing pack directory or archive")
+		printAdminUsage()
+		os.Exit(1)
+	}
+
+	pack, err := library.OpenPackWithOptions(target, opts)
+	if err != nil {
+		var packErr *library.PackError
+		if errors.As(err, &packErr) {
+			fmt.Printf("✗ %s error: %v\n", packErr.Kind, err)
+		} else {
+			fmt.Printf("✗ %v\n", err)
+		}
+		os.Exit(1)
+	}
+
+	manifest := pack.Manifest
+	fmt.Printf("Pack: %s %s\n", manifest.Name, manifest.Version)
+	if manifest.Description != "" {
+		fmt.Printf("  %s\n", manifest.Description)
+	}
+	for _, name := range pack.Characters {
+		char, _ := library.Get(name)
+		states := make([]string, 0, len(char.States))
+		for state := range char.States {
+			states = append(states, state)
+		}
+		sort.Strings(states)
+
+		micro := ""
+		if _, err := library.GetMicro(name); err == nil {
+			micro = " + micro"
+		}
+		fmt.Printf("  • %s (%dx%d%s): %s\n", name, char.Width, char.Height, micro, strings.Join(states, ", "))
+	}
+	if len(pack.Themes) > 0 {
+		fmt.Printf("  Themes: %s\n", strings.Join(pack.Themes, ", "))
+	}
+	switch {
+	case len(opts.PublicKeys) > 0:
+		fmt.Println("  Signature and checksums verified")
+	case manifest.Files != nil:
+		fmt.Println("  Checksums verified")
+	}
+	fmt.Printf("✓ %d character%s loaded\n", len(pack.Characters), pluralize(len(pack.Characters)))
+}
+
+// handleSealPack writes the SHA-256 of every file of a pack directory into
+// its manifest and, with a key, signs the manifest into pack.sig.
+//
+//	tangent-cli admin seal-pack <dir> [--key private.pem]
+func handleSealPack(args []string) {
+	var dir string
+	var key ed25519.PrivateKey
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--key" {
+			if i+1 >= len(args) {
+				fmt.Println("Error: --key needs a value")
+				os.Exit(1)
+			}
+			var err error
+			if key, err = readPrivateKey(args[i+1]); err != nil {
+				fmt.Printf("Error: %v\n", err)
+				os.Exit(1)
+			}
+			i++
+			continue
+		}
+		dir = args[i]
+	}
+	if dir == "" {
+		fmt.Println("Error: missing pack directory")
+		printAdminUsage()
+		os.Exit(1)
+	}
+
+	manifest, sig, err := library.SealPack(os.DirFS(dir), key)
+	if err != nil {
+		fmt.Printf("✗ %v\n", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(filepath.Join(dir, library.PackManifestFile), manifest, 0644); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("✓ Wrote checksums to %s\n", filepath.Join(dir, library.PackManifestFile))
+	if sig != nil {
+		if err := os.WriteFile(filepath.Join(dir, library.PackSignatureFile), sig, 0644); err != nil {
+			fmt.Printf("Error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("✓ Wrote signature to %s\n", filepath.Join(dir, library.PackSignatureFile))
+	}
+}
+
+// readPublicKey reads an ed25519 public key in PEM (PKIX) form, as written
+// by "openssl pkey -pubout"
+func readPublicKey(name string) (ed25519.PublicKey, error) {
+	block, err := readPEM(name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	pub, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 public key", name)
+	}
+	return pub, nil
+}
+
+// readPrivateKey reads an ed25519 private key in PEM (PKCS #8) form, as
+// written by "openssl genpkey -algorithm ed25519"
+func readPrivateKey(name string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(name)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	priv, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s: not an ed25519 private key", name)
+	}
+	return priv, nil
+}
+
+// readPEM reads the first PEM block of a file
+func readPEM(name string) (*pem.Block, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s: no PEM data", name)
+	}
+	return block, nil
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/library"
+)
+
+// handleCheckThemes validates theme contrast and colorblind safety.
+// Exits with status 1 when any theme has issues.
+//
+//	tangent-cli admin check-themes [theme...] [--min-contrast N] [--min-distance D]
+func handleCheckThemes(args []string) {
+	var opts library.ValidationOptions
+	var names []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--min-contrast", "--min-distance":
+			if i+1 >= len(args) {
+				fmt.Printf("Error: %s needs a value\n", args[i])
+				os.Exit(1)
+			}
+			v, err := strconv.ParseFloat(args[i+1], 64)
+			if err != nil || v <= 0 {
+				fmt.Printf("Error: invalid %s value %q\n", args[i], args[i+1])
+				os.Exit(1)
+			}
+			if args[i] == "--min-contrast" {
+				opts.MinContrast = v
+			} else {
+				opts.MinDistance = v
+			}
+			i++
+		default:
+			names = append(names, args[i])
+		}
+	}
+
+	var reports []library.ThemeReport
+	if len(names) == 0 {
+		reports = library.ValidateThemes(opts)
+	} else {
+		for _, name := range names {
+			theme, err := library.GetTheme(name)
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				fmt.Println("Available themes:", strings.Join(library.ListThemes(), ", "))
+				os.Exit(1)
+			}
+			reports = append(reports, library.ValidateTheme(theme, opts))
+		}
+	}
+
+	failed := 0
+	for _, report := range reports {
+		printThemeReport(report)
+		if !report.OK() {
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d of %d theme%s with issues\n", failed, len(reports), pluralize(len(reports)))
+		os.Exit(1)
+	}
+	fmt.Printf("All %d theme%s passed\n", len(reports), pluralize(len(reports)))
+}
+
+// printThemeReport prints a contrast table and the issues of one theme
+func printThemeReport(report library.ThemeReport) {
+	theme, _ := library.GetTheme(report.Theme)
+	affinity := theme.Affinity
+	if affinity == "" {
+		affinity = "any"
+	}
+	fmt.Printf("Theme: %s (%s background)\n", report.Theme, affinity)
+
+	// Contrast table: one row per character, one column per background
+	var backgrounds, order []string
+	ratios := map[string]map[string]float64{}
+	for _, r := range report.Contrast {
+		if len(order) == 0 || order[0] == r.Character {
+			backgrounds = append(backgrounds, r.Background)
+		}
+		if ratios[r.Character] == nil {
+			ratios[r.Character] = map[string]float64{}
+			order = append(order, r.Character)
+		}
+		ratios[r.Character][r.Background] = r.Ratio
+	}
+
+	fmt.Printf("  %-8s %-8s", "agent", "color")
+	for _, bg := range backgrounds {
+		fmt.Printf(" %8s", bg)
+	}
+	fmt.Println()
+	for _, name := range order {
+		fmt.Printf("  %-8s %-8s", name, theme.Colors[name])
+		for _, bg := range backgrounds {
+			fmt.Printf(" %6.2f:1", ratios[name][bg])
+		}
+		fmt.Println()
+	}
+
+	if report.OK() {
+		fmt.Println("  ✓ no issues")
+	} else {
+		for _, issue := range report.Issues {
+			fmt.Printf("  ✗ %s\n", issue.Message)
+		}
+	}
+	fmt.Println()
+}
+
+// handleGenerateTheme generates a theme from a base hue or seed palette and
+// writes it as JSON to stdout or a file.
+//
+//	tangent-cli admin generate-theme <name> [--hue H] [--seed #RRGGBB,...]
+//	    [--harmony even|analogous|triadic] [--affinity dark|light]
+//	    [--description D] [-o FILE]
+func handleGenerateTheme(args []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		fmt.Println("Error: missing theme name")
+		printAdminUsage()
+		os.Exit(1)
+	}
+	opts := library.ThemeOptions{Name: args[0]}
+	var output string
+
+	for i := 1; i < len(args); i++ {
+		if i+1 >= len(args) {
+			fmt.Printf("Error: %s needs a value\n", args[i])
+			os.Exit(1)
+		}
+		value := args[i+1]
+		switch args[i] {
+		case "--hue":
+			hue, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				fmt.Printf("Error: invalid hue %q\n", value)
+				os.Exit(1)
+			}
+			opts.BaseHue = hue
+		case "--seed":
+			opts.Seed = strings.Split(value, ",")
+		case "--harmony":
+			opts.Harmony = value
+		case "--affinity":
+			opts.Affinity = value
+		case "--description":
+			opts.Description = value
+		case "-o", "--output":
+			output = value
+		default:
+			fmt.Printf("Error: unknown flag %s\n", args[i])
+			os.Exit(1)
+		}
+		i++
+	}
+
+	theme, err := library.GenerateTheme(opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	data, err := library.ThemeJSON(theme)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if output == "" {
+		fmt.Println(string(data))
+		return
+	}
+	if err := os.WriteFile(output, append(data, '\n'), 0644); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Wrote theme %q (%s, %d agents) to %s\n", theme.Name, theme.Affinity, len(theme.Colors), output)
+}
@@ -31,7 +31,7 @@ func handleView(args []string) {
 			fmt.Println("No sessions found. Create one with: tangent create")
 			return
 		}
-		fmt.Println("Available Sessions:\n")
+		fmt.Print("Available Sessions:\n\n")
 		for _, n := range names {
 			fmt.Println("  •", n)
 		}
@@ -113,5 +113,5 @@ func handleView(args []string) {
 		handleError("Animation failed", err)
 		return
 	}
-	fmt.Println("\n✓ View complete!\n")
+	fmt.Print("\n✓ View complete!\n\n")
 }
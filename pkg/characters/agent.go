@@ -8,8 +8,10 @@ import (
 	"time"
 
 	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/effects"
 	"github.com/wildreason/tangent/pkg/characters/infrastructure"
-	"github.com/wildreason/tangent/pkg/characters/micronoise"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
 )
 
 // colorize wraps text with ANSI RGB color codes
@@ -20,8 +22,10 @@ func colorize(text string, hexColor string) string {
 
 // AgentCharacter wraps a Character with state-based API methods for AI agents
 type AgentCharacter struct {
-	character *domain.Character
+	character  *domain.Character
 	frameCache *FrameCache // Pre-rendered colored frames for performance
+	glyphSet   string      // Glyph set used to compile patterns ("" = unicode)
+	scaled     map[int]*AgentCharacter
 }
 
 // NewAgentCharacter creates a new AgentCharacter wrapper
@@ -31,6 +35,36 @@ func NewAgentCharacter(character *domain.Character) *AgentCharacter {
 	}
 }
 
+// SetGlyphSet selects the glyph set used to render this agent, e.g. "ascii"
+// for terminals without block elements. See patterns.ListGlyphSets.
+// Invalidates any previously built frame cache.
+func (a *AgentCharacter) SetGlyphSet(name string) error {
+	if _, err := patterns.GetGlyphSet(name); err != nil {
+		return err
+	}
+	a.glyphSet = name
+	a.frameCache = nil
+	a.scaled = nil
+	return nil
+}
+
+// GlyphSet returns the glyph set name used to render this agent.
+func (a *AgentCharacter) GlyphSet() string {
+	if a.glyphSet == "" {
+		return patterns.GlyphSetUnicode
+	}
+	return a.glyphSet
+}
+
+// compiler returns a pattern compiler for the agent's glyph set
+func (a *AgentCharacter) compiler() domain.PatternCompiler {
+	codes, err := patterns.GetGlyphSet(a.glyphSet)
+	if err != nil {
+		return infrastructure.NewPatternCompiler()
+	}
+	return infrastructure.NewPatternCompilerWithCodes(codes)
+}
+
 // Plan shows the planning state animation
 func (a *AgentCharacter) Plan(writer io.Writer) error {
 	return a.ShowState(writer, "plan")
@@ -76,10 +110,12 @@ func (a *AgentCharacter) ShowState(writer io.Writer, stateName string) error {
 		return fmt.Errorf("state %q has no frames", stateName)
 	}
 
+	compiler := a.compiler()
+
 	// Animate the state's frames
 	for _, frame := range state.Frames {
 		for _, line := range frame.Lines {
-			fmt.Fprintln(writer, line)
+			fmt.Fprintln(writer, compiler.Compile(line))
 		}
 
 		// Brief pause between frames if multiple frames in state
@@ -162,11 +198,9 @@ func (a *AgentCharacter) ShowBase(writer io.Writer) error {
 	}
 
 	// Create pattern compiler for frame compilation
-	compiler := infrastructure.NewPatternCompiler()
+	compiler := a.compiler()
 
-	for _, line := range a.character.BaseFrame.Lines {
-		compiledLine := compiler.Compile(line)
-		coloredLine := colorize(compiledLine, a.character.Color)
+	for _, coloredLine := range renderFrame(compiler, a.character.BaseFrame, a.character) {
 		fmt.Fprintln(writer, coloredLine)
 	}
 
@@ -211,27 +245,36 @@ func (a *AgentCharacter) AnimateState(writer io.Writer, stateName string, fps in
 	defer fmt.Fprint(writer, "\x1b[?25h")
 
 	// Create pattern compiler for frame compilation
-	compiler := infrastructure.NewPatternCompiler()
+	compiler := a.compiler()
 
-	// Check if this is a micro avatar (8x2)
-	isMicro := a.character.Width == 8 && a.character.Height == 2
-	flickerConfig := micronoise.GetConfig(stateName)
+	// Render effects declared by the state (gradient, pulse, ...)
+	pipeline, err := effects.Build(state.Effects)
+	if err != nil {
+		return fmt.Errorf("state %q: %w", stateName, err)
+	}
+	base, _ := parseColor(a.character.Color)
 	frameCounter := 0
 
+	// Noise cells ($) change on their own clock, not per frame
+	noise := patterns.NewNoiseSource(time.Now().UnixNano())
+	noisePool := compileNoisePool(compiler, patterns.DefaultNoiseCodes)
+	if state.NoisePool != "" {
+		noisePool = compileNoisePool(compiler, state.NoisePool)
+	}
+
 	for loop := 0; loop < stateLoops; loop++ {
 		for _, frame := range state.Frames {
 			// Compile and colorize lines
-			lines := make([]string, len(frame.Lines))
-			for i, line := range frame.Lines {
-				compiledLine := compiler.Compile(line)
-				lines[i] = colorize(compiledLine, a.character.Color)
-			}
+			lines := noise.Apply(renderFrame(compiler, frame, a.character), noisePool, state.NoiseRate)
 
-			// Apply shifting gradient for "Wall Street rush" effect
-			if isMicro && flickerConfig != nil {
-				lines = micronoise.ApplyShiftingGradient(lines, a.character.Width, a.character.Height, frameCounter, flickerConfig)
-				frameCounter++
-			}
+			// Apply state effects
+			lines = pipeline.Apply(lines, effects.Context{
+				Frame:   frameCounter,
+				Frames:  len(state.Frames),
+				Elapsed: time.Duration(frameCounter) * frameDur,
+				Base:    base,
+			})
+			frameCounter++
 
 			// Clear and print each line
 			for _, line := range lines {
@@ -247,16 +290,16 @@ func (a *AgentCharacter) AnimateState(writer io.Writer, stateName string, fps in
 
 	// Print final frame cleanly
 	finalFrame := state.Frames[len(state.Frames)-1]
-	lines := make([]string, len(finalFrame.Lines))
-	for i, line := range finalFrame.Lines {
-		compiledLine := compiler.Compile(line)
-		lines[i] = colorize(compiledLine, a.character.Color)
-	}
+	lines := noise.Apply(renderFrame(compiler, finalFrame, a.character), noisePool, state.NoiseRate)
 
-	// Apply gradient to final frame
-	if isMicro && flickerConfig != nil {
-		lines = micronoise.ApplyShiftingGradient(lines, a.character.Width, a.character.Height, frameCounter, flickerConfig)
-	}
+	// Apply effects to final frame, which ends the last loop
+	last := max(frameCounter-1, 0)
+	lines = pipeline.Apply(lines, effects.Context{
+		Frame:   last,
+		Frames:  len(state.Frames),
+		Elapsed: time.Duration(last) * frameDur,
+		Base:    base,
+	})
 
 	for _, line := range lines {
 		fmt.Fprintln(writer, line)
@@ -273,6 +316,11 @@ type FrameCache struct {
 	stateFrames   map[string][][]string // state -> []frames (each frame is []lines)
 	characterName string
 	color         string
+	glyphSet      string
+	noisePools    map[string][]rune // state -> runes "$" cells pick from
+	noiseRates    map[string]int    // state -> noise changes per second
+	defaultNoise  []rune
+	effects       map[string]effects.Pipeline // state -> render effects
 }
 
 // GetFrameCache returns a pre-rendered frame cache for this character.
@@ -303,28 +351,34 @@ func (a *AgentCharacter) GetFrameCache() *FrameCache {
 	}
 
 	// Build cache on first access
-	compiler := infrastructure.NewPatternCompiler()
+	compiler := a.compiler()
 
 	// Pre-render base frame
-	baseLines := make([]string, len(a.character.BaseFrame.Lines))
-	for i, line := range a.character.BaseFrame.Lines {
-		compiled := compiler.Compile(line)
-		baseLines[i] = colorize(compiled, a.character.Color)
-	}
+	baseLines := renderFrame(compiler, a.character.BaseFrame, a.character)
 
 	// Pre-render all state frames
 	stateFrames := make(map[string][][]string)
+	noisePools := make(map[string][]rune)
+	noiseRates := make(map[string]int)
+	pipelines := make(map[string]effects.Pipeline)
 	for stateName, state := range a.character.States {
 		frames := make([][]string, len(state.Frames))
 		for frameIdx, frame := range state.Frames {
-			lines := make([]string, len(frame.Lines))
-			for lineIdx, line := range frame.Lines {
-				compiled := compiler.Compile(line)
-				lines[lineIdx] = colorize(compiled, a.character.Color)
-			}
-			frames[frameIdx] = lines
+			frames[frameIdx] = renderFrame(compiler, frame, a.character)
 		}
 		stateFrames[stateName] = frames
+
+		if state.NoisePool != "" {
+			noisePools[stateName] = compileNoisePool(compiler, state.NoisePool)
+		}
+		if state.NoiseRate > 0 {
+			noiseRates[stateName] = state.NoiseRate
+		}
+		// Library states are validated at load; custom states with
+		// invalid effects render without them
+		if pipeline, err := effects.Build(state.Effects); err == nil && len(pipeline) > 0 {
+			pipelines[stateName] = pipeline
+		}
 	}
 
 	a.frameCache = &FrameCache{
@@ -332,6 +386,11 @@ func (a *AgentCharacter) GetFrameCache() *FrameCache {
 		stateFrames:   stateFrames,
 		characterName: a.character.Name,
 		color:         a.character.Color,
+		glyphSet:      a.GlyphSet(),
+		noisePools:    noisePools,
+		noiseRates:    noiseRates,
+		defaultNoise:  compileNoisePool(compiler, patterns.DefaultNoiseCodes),
+		effects:       pipelines,
 	}
 
 	return a.frameCache
@@ -374,3 +433,66 @@ func (fc *FrameCache) GetCharacterName() string {
 func (fc *FrameCache) GetColor() string {
 	return fc.color
 }
+
+// GetGlyphSet returns the glyph set the frames were rendered with
+func (fc *FrameCache) GetGlyphSet() string {
+	return fc.glyphSet
+}
+
+// GetNoisePool returns the runes that noise cells ($) of a state pick from,
+// in the cache's glyph set. States without a pool get the default pool.
+func (fc *FrameCache) GetNoisePool(stateName string) []rune {
+	if pool, ok := fc.noisePools[stateName]; ok {
+		return pool
+	}
+	return fc.defaultNoise
+}
+
+// GetNoiseRate returns how many times per second a state's noise changes
+func (fc *FrameCache) GetNoiseRate(stateName string) int {
+	if rate, ok := fc.noiseRates[stateName]; ok {
+		return rate
+	}
+	return patterns.DefaultNoiseRate
+}
+
+// ApplyNoise replaces the noise placeholders in lines using the state's pool
+// and rate. Lines without noise cells are returned as-is.
+func (fc *FrameCache) ApplyNoise(lines []string, stateName string, src *patterns.NoiseSource) []string {
+	return src.Apply(lines, fc.GetNoisePool(stateName), fc.GetNoiseRate(stateName))
+}
+
+// HasEffects reports whether a state declares render effects
+func (fc *FrameCache) HasEffects(stateName string) bool {
+	return len(fc.effects[stateName]) > 0
+}
+
+// ApplyEffects runs the state's effect pipeline over rendered lines.
+// ctx.Frame is the number of frames shown since the state started; Frames
+// and Base are filled in from the cache when zero. States without effects
+// return lines as-is.
+func (fc *FrameCache) ApplyEffects(lines []string, stateName string, ctx effects.Context) []string {
+	pipeline := fc.effects[stateName]
+	if len(pipeline) == 0 {
+		return lines
+	}
+	if ctx.Frames == 0 {
+		ctx.Frames = len(fc.stateFrames[stateName])
+	}
+	if ctx.Base == (termcolor.RGB{}) {
+		ctx.Base, _ = parseColor(fc.color)
+	}
+	return pipeline.Apply(lines, ctx)
+}
+
+// compileNoisePool compiles noise pattern codes to runes, dropping the
+// placeholder itself so noise never renders as "$"
+func compileNoisePool(compiler domain.PatternCompiler, codes string) []rune {
+	var pool []rune
+	for _, r := range compiler.Compile(codes) {
+		if r != patterns.NoisePlaceholder {
+			pool = append(pool, r)
+		}
+	}
+	return pool
+}
@@ -0,0 +1,230 @@
+// Package asciicast records character animations as asciinema v2 .cast
+// files, for sharing avatar behavior without screen recordings.
+package asciicast
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/wildreason/tangent/pkg/characters"
+	"github.com/wildreason/tangent/pkg/characters/effects"
+	"github.com/wildreason/tangent/pkg/characters/library"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+)
+
+// Terminal sequences around the recording
+const (
+	hideCursor  = "\x1b[?25l"
+	showCursor  = "\x1b[?25h"
+	clearScreen = "\x1b[2J"
+	home        = "\x1b[H"
+)
+
+// Header is the first line of an asciicast v2 file
+type Header struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	Env       map[string]string `json:"env,omitempty"`
+}
+
+// Event switches the character to State at Time from the start of the
+// recording. "base" shows the base frame.
+type Event struct {
+	Time  time.Duration
+	State string
+}
+
+// Options configures a recording.
+type Options struct {
+	Title string
+
+	// Loops of each state WriteStates plays; 0 plays one
+	Loops int
+
+	// Seed for noise cells ($), so a recording can be reproduced
+	Seed int64
+
+	// Terminal size; 0 (or less than the character) uses the character's
+	Width  int
+	Height int
+
+	// Timestamp is the recording's Unix time; 0 leaves it out
+	Timestamp int64
+}
+
+// WriteStates records states one after another, each for Options.Loops
+// loops at its own frame rate.
+func WriteStates(w io.Writer, agent *characters.AgentCharacter, states []string, opts Options) error {
+	loops := time.Duration(max(opts.Loops, 1))
+	events := make([]Event, 0, len(states))
+	var at time.Duration
+	for _, name := range states {
+		events = append(events, Event{Time: at, State: name})
+		at += loops * loopDuration(agent, name)
+	}
+	return WriteEvents(w, agent, events, at, opts)
+}
+
+// WriteEvents replays an event log: each event switches state and the
+// state animates at its frame rate, with noise and effects as a live
+// render would show them, until the next event. The recording ends at end,
+// or one loop after the last event when end is 0. Colors use the active
+// color profile (see characters.SetColorProfile).
+func WriteEvents(w io.Writer, agent *characters.AgentCharacter, events []Event, end time.Duration, opts Options) error {
+	if len(events) == 0 {
+		return fmt.Errorf("no events to record")
+	}
+	char := agent.GetCharacter()
+	for i, event := range events {
+		if event.Time < 0 || (i > 0 && event.Time < events[i-1].Time) {
+			return fmt.Errorf("event %d: time %s is out of order", i+1, event.Time)
+		}
+		if _, ok := char.States[event.State]; !ok && event.State != "base" {
+			return fmt.Errorf("event %d: state %q not found for character %s", i+1, event.State, char.Name)
+		}
+	}
+	last := events[len(events)-1]
+	if end == 0 {
+		end = last.Time + loopDuration(agent, last.State)
+	}
+	if end < last.Time {
+		return fmt.Errorf("end %s is before the last event at %s", end, last.Time)
+	}
+
+	bw := bufio.NewWriter(w)
+	header := Header{
+		Version:   2,
+		Width:     max(opts.Width, char.Width),
+		Height:    max(opts.Height, char.Height),
+		Timestamp: opts.Timestamp,
+		Title:     opts.Title,
+		Env:       map[string]string{"TERM": "xterm-256color"},
+	}
+	if err := writeLine(bw, header); err != nil {
+		return err
+	}
+
+	cache := agent.GetFrameCache()
+	noise := patterns.NewNoiseSource(opts.Seed)
+	var clock time.Time
+	noise.SetClock(func() time.Time { return clock })
+
+	prefix := hideCursor + clearScreen
+	var previous string
+	for i, event := range events {
+		until := end
+		if i+1 < len(events) {
+			until = events[i+1].Time
+		}
+		period := time.Second / time.Duration(stateFPS(char.States[event.State].AnimationFPS))
+		frames := cache.GetStateFrames(event.State)
+
+		for k := 0; ; k++ {
+			at := event.Time + time.Duration(k)*period
+			// A state always shows its first frame, however short
+			if k > 0 && at >= until {
+				break
+			}
+			var lines []string
+			if event.State == "base" || len(frames) == 0 {
+				lines = cache.GetBaseFrame()
+			} else {
+				lines = frames[k%len(frames)]
+			}
+			clock = time.Unix(0, 0).Add(at)
+			lines = cache.ApplyNoise(lines, event.State, noise)
+			lines = cache.ApplyEffects(lines, event.State, effects.Context{
+				Frame:   k,
+				Elapsed: time.Duration(k) * period,
+			})
+
+			screen := strings.Join(lines, "\r\n")
+			if screen == previous {
+				continue
+			}
+			previous = screen
+			if err := writeOutput(bw, at, prefix+home+screen); err != nil {
+				return err
+			}
+			prefix = ""
+		}
+	}
+
+	if err := writeOutput(bw, end, showCursor); err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// loopDuration returns how long one loop of a state takes; unknown
+// states and "base" count as one frame
+func loopDuration(agent *characters.AgentCharacter, stateName string) time.Duration {
+	state := agent.GetCharacter().States[stateName]
+	frames := max(len(state.Frames), 1)
+	return time.Duration(frames) * time.Second / time.Duration(stateFPS(state.AnimationFPS))
+}
+
+func stateFPS(fps int) int {
+	if fps <= 0 {
+		return library.DefaultFPS
+	}
+	return fps
+}
+
+// writeOutput writes an output event: [time, "o", data]
+func writeOutput(w *bufio.Writer, at time.Duration, data string) error {
+	return writeLine(w, []any{json.Number(fmt.Sprintf("%.6f", at.Seconds())), "o", data})
+}
+
+func writeLine(w *bufio.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Write(data)
+	return w.WriteByte('\n')
+}
+
+// logEntry is one line of an event log
+type logEntry struct {
+	Time  *float64 `json:"time"`
+	State string   `json:"state"`
+}
+
+// ParseEvents reads an event log: one JSON object per line with the time
+// in seconds and the state to switch to. Blank lines are skipped.
+//
+//	{"time": 0, "state": "wait"}
+//	{"time": 1.5, "state": "think"}
+func ParseEvents(r io.Reader) ([]Event, error) {
+	var events []Event
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var entry logEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		if entry.Time == nil || entry.State == "" {
+			return nil, fmt.Errorf("line %d: needs \"time\" and \"state\"", n)
+		}
+		events = append(events, Event{
+			Time:  time.Duration(*entry.Time * float64(time.Second)),
+			State: entry.State,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
@@ -0,0 +1,200 @@
+package asciicast
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wildreason/tangent/pkg/characters"
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+func TestMain(m *testing.M) {
+	termcolor.SetProfile(termcolor.TrueColor)
+	m.Run()
+}
+
+func testAgent() *characters.AgentCharacter {
+	return characters.NewAgentCharacter(&domain.Character{
+		Name:      "bolt",
+		Color:     "#FF0000",
+		Width:     2,
+		Height:    1,
+		BaseFrame: domain.Frame{Name: "base", Lines: []string{"BB"}},
+		States: map[string]domain.State{
+			"wait": {
+				Name:         "wait",
+				AnimationFPS: 4,
+				Frames:       []domain.Frame{{Lines: []string{"FF"}}, {Lines: []string{"TT"}}},
+			},
+			"think": {
+				Name:         "think",
+				AnimationFPS: 2,
+				Frames:       []domain.Frame{{Lines: []string{"$F"}}},
+				Effects:      []domain.EffectSpec{{Type: "gradient"}},
+			},
+		},
+	})
+}
+
+// output is a parsed output event
+type output struct {
+	Time float64
+	Data string
+}
+
+// parseCast returns the header and output events of a recording
+func parseCast(t *testing.T, data []byte) (Header, []output) {
+	t.Helper()
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var header Header
+	var events []output
+	for scanner.Scan() {
+		if header.Version == 0 {
+			if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+				t.Fatalf("invalid header: %v", err)
+			}
+			continue
+		}
+		var event []any
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil || len(event) != 3 || event[1] != "o" {
+			t.Fatalf("invalid event %s", scanner.Bytes())
+		}
+		events = append(events, output{Time: event[0].(float64), Data: event[2].(string)})
+	}
+	return header, events
+}
+
+func times(events []output) []float64 {
+	var ts []float64
+	for _, e := range events {
+		ts = append(ts, e.Time)
+	}
+	return ts
+}
+
+func TestWriteStates(t *testing.T) {
+	var buf bytes.Buffer
+	opts := Options{Title: "bolt", Loops: 2, Width: 80}
+	if err := WriteStates(&buf, testAgent(), []string{"wait", "think"}, opts); err != nil {
+		t.Fatalf("WriteStates() error = %v", err)
+	}
+	header, events := parseCast(t, buf.Bytes())
+
+	want := Header{Version: 2, Width: 80, Height: 1, Title: "bolt", Env: map[string]string{"TERM": "xterm-256color"}}
+	if !reflect.DeepEqual(header, want) {
+		t.Errorf("header = %+v, want %+v", header, want)
+	}
+
+	// wait: two loops of two frames at 4 FPS; think: two loops of one
+	// frame at 2 FPS, each redrawn for the gradient; then the end marker
+	if got, want := times(events), []float64{0, 0.25, 0.5, 0.75, 1, 1.5, 2}; !reflect.DeepEqual(got, want) {
+		t.Errorf("event times = %v, want %v", got, want)
+	}
+	if !strings.HasPrefix(events[0].Data, hideCursor+clearScreen+home) {
+		t.Errorf("first event = %q, want cursor hidden and screen cleared", events[0].Data)
+	}
+	if !strings.Contains(events[1].Data, "▀▀") {
+		t.Errorf("second frame = %q, want ▀▀", events[1].Data)
+	}
+	// The gradient shifts the color between think frames
+	if events[4].Data == events[5].Data || !strings.Contains(events[4].Data, "\x1b[38;2;") {
+		t.Errorf("think frames = %q, %q, want shifting truecolor gradient", events[4].Data, events[5].Data)
+	}
+	if strings.Contains(events[4].Data, "◌") {
+		t.Errorf("noise cell not filled: %q", events[4].Data)
+	}
+	if events[6].Data != showCursor {
+		t.Errorf("last event = %q, want cursor shown", events[6].Data)
+	}
+}
+
+func TestWriteEvents(t *testing.T) {
+	events := []Event{
+		{Time: 0, State: "base"},
+		{Time: 300 * time.Millisecond, State: "wait"},
+	}
+	var buf bytes.Buffer
+	if err := WriteEvents(&buf, testAgent(), events, 600*time.Millisecond, Options{}); err != nil {
+		t.Fatalf("WriteEvents() error = %v", err)
+	}
+	_, out := parseCast(t, buf.Bytes())
+	if got, want := times(out), []float64{0, 0.3, 0.55, 0.6}; !reflect.DeepEqual(got, want) {
+		t.Errorf("event times = %v, want %v", got, want)
+	}
+	if !strings.Contains(out[0].Data, "▄▄") {
+		t.Errorf("base frame = %q, want ▄▄", out[0].Data)
+	}
+
+	// Without an end the last state plays one loop
+	buf.Reset()
+	if err := WriteEvents(&buf, testAgent(), events, 0, Options{}); err != nil {
+		t.Fatalf("WriteEvents() error = %v", err)
+	}
+	_, out = parseCast(t, buf.Bytes())
+	if got := out[len(out)-1].Time; got != 0.8 {
+		t.Errorf("end = %v, want 0.8", got)
+	}
+}
+
+func TestWriteEventsSeed(t *testing.T) {
+	record := func(seed int64) string {
+		var buf bytes.Buffer
+		if err := WriteStates(&buf, testAgent(), []string{"think"}, Options{Loops: 8, Seed: seed}); err != nil {
+			t.Fatalf("WriteStates() error = %v", err)
+		}
+		return buf.String()
+	}
+	if record(3) != record(3) {
+		t.Error("same seed produced different recordings")
+	}
+}
+
+func TestWriteEventsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []Event
+		end    time.Duration
+	}{
+		{"no events", nil, 0},
+		{"unknown state", []Event{{State: "dance"}}, 0},
+		{"out of order", []Event{{Time: time.Second, State: "wait"}, {State: "think"}}, 0},
+		{"end before last event", []Event{{Time: time.Second, State: "wait"}}, time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		if err := WriteEvents(&buf, testAgent(), tt.events, tt.end, Options{}); err == nil {
+			t.Errorf("%s: WriteEvents() error = nil, want error", tt.name)
+		}
+	}
+	if err := WriteStates(&bytes.Buffer{}, testAgent(), []string{"dance"}, Options{}); err == nil {
+		t.Error("WriteStates() of unknown state error = nil, want error")
+	}
+}
+
+func TestParseEvents(t *testing.T) {
+	log := `{"time": 0, "state": "wait"}
+
+{"time": 1.5, "state": "think"}
+`
+	events, err := ParseEvents(strings.NewReader(log))
+	if err != nil {
+		t.Fatalf("ParseEvents() error = %v", err)
+	}
+	want := []Event{{Time: 0, State: "wait"}, {Time: 1500 * time.Millisecond, State: "think"}}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("ParseEvents() = %+v, want %+v", events, want)
+	}
+
+	for _, bad := range []string{`{"state": "wait"}`, `{"time": 1}`, `not json`} {
+		if _, err := ParseEvents(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseEvents(%q) error = nil, want error", bad)
+		}
+	}
+}
@@ -7,7 +7,8 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wildreason/tangent/pkg/characters"
-	"github.com/wildreason/tangent/pkg/characters/micronoise"
+	"github.com/wildreason/tangent/pkg/characters/effects"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
 )
 
 // AnimatedCharacter is a Bubble Tea component that provides
@@ -34,8 +35,8 @@ type AnimatedCharacter struct {
 	playing       bool
 	width        int
 	height       int
-	isMicro      bool
-	noiseCounter int // Frame counter for gradient animation
+	effectFrame  int // Ticks since the state started, for effects
+	noise        *patterns.NoiseSource
 }
 
 // NewAnimatedCharacter creates a new Bubble Tea component from an AgentCharacter.
@@ -55,7 +56,6 @@ func NewAnimatedCharacter(agent *characters.AgentCharacter, tickInterval time.Du
 	}
 
 	char := agent.GetCharacter()
-	isMicro := char.Width == 8 && char.Height == 2
 
 	return &AnimatedCharacter{
 		agent:        agent,
@@ -66,7 +66,7 @@ func NewAnimatedCharacter(agent *characters.AgentCharacter, tickInterval time.Du
 		playing:      true,
 		width:        char.Width,
 		height:       char.Height,
-		isMicro:      isMicro,
+		noise:        patterns.NewNoiseSource(time.Now().UnixNano()),
 	}
 }
 
@@ -101,6 +101,7 @@ func (m *AnimatedCharacter) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if len(frames) > 0 {
 			m.currentFrame = (m.currentFrame + 1) % len(frames)
 		}
+		m.effectFrame++
 
 		return m, m.tick()
 
@@ -138,13 +139,14 @@ func (m *AnimatedCharacter) View() string {
 		}
 	}
 
-	// Apply shifting gradient for "Wall Street rush" effect
-	if m.isMicro {
-		if cfg := micronoise.GetConfig(m.currentState); cfg != nil {
-			lines = micronoise.ApplyShiftingGradient(lines, m.width, m.height, m.noiseCounter, cfg)
-		}
-		m.noiseCounter++
-	}
+	// Fill noise cells ($) from the state's pool
+	lines = m.cache.ApplyNoise(lines, m.currentState, m.noise)
+
+	// Apply the state's effects ("Wall Street rush" gradient, ...)
+	lines = m.cache.ApplyEffects(lines, m.currentState, effects.Context{
+		Frame:   m.effectFrame,
+		Elapsed: time.Duration(m.effectFrame) * m.tickInterval,
+	})
 
 	return strings.Join(lines, "\n")
 }
@@ -159,10 +161,21 @@ func (m *AnimatedCharacter) SetState(stateName string) error {
 
 	m.currentState = stateName
 	m.currentFrame = 0
+	m.effectFrame = 0
 
 	return nil
 }
 
+// SetGlyphSet re-renders the character with a different glyph set
+// (e.g. "ascii", "safe", "braille"). See patterns.ListGlyphSets.
+func (m *AnimatedCharacter) SetGlyphSet(name string) error {
+	if err := m.agent.SetGlyphSet(name); err != nil {
+		return err
+	}
+	m.cache = m.agent.GetFrameCache()
+	return nil
+}
+
 // GetState returns the current state name
 func (m *AnimatedCharacter) GetState() string {
 	return m.currentState
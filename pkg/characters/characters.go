@@ -5,10 +5,10 @@ import (
 	"strings"
 
 	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/effects"
 	"github.com/wildreason/tangent/pkg/characters/infrastructure"
 	"github.com/wildreason/tangent/pkg/characters/library"
-	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
-	"github.com/wildreason/tangent/pkg/characters/stateregistry"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
 )
 
 // Package characters provides a simple API for terminal character animation.
@@ -39,7 +39,16 @@ import (
 // Global theme state
 var currentTheme = "latte" // Default theme
 
-// SetTheme sets the global theme for all characters
+// MinThemeContrast is the contrast ratio AutoTheme guarantees between body
+// colors and the terminal background (the WCAG minimum for graphics).
+const MinThemeContrast = 3.0
+
+// themeBackground is the background AutoTheme adjusts body colors for
+// (nil when the theme was chosen by name)
+var themeBackground *termcolor.RGB
+
+// SetTheme sets the global theme for all characters.
+// Colors are used exactly as the theme defines them.
 func SetTheme(themeName string) error {
 	// Validate theme exists
 	_, err := library.GetTheme(themeName)
@@ -47,9 +56,88 @@ func SetTheme(themeName string) error {
 		return err
 	}
 	currentTheme = themeName
+	themeBackground = nil
 	return nil
 }
 
+// AutoTheme detects the terminal background (see
+// termcolor.DetectBackground) and selects the best theme for it with
+// AutoThemeFor. When the background can't be detected the current theme is
+// kept and an error is returned. Call it before starting a TUI.
+func AutoTheme() (string, error) {
+	bg, ok := termcolor.DetectBackground(termcolor.DefaultBackgroundTimeout)
+	if !ok {
+		return currentTheme, fmt.Errorf("terminal background not detected")
+	}
+	return AutoThemeFor(bg), nil
+}
+
+// AutoThemeFor selects the theme best suited to background bg and makes it
+// the current theme. Themes with a matching light/dark affinity are
+// preferred, and among them the one whose weakest body color contrasts
+// most with bg wins. Characters loaded afterwards have body colors darkened
+// or lightened to at least MinThemeContrast against bg.
+func AutoThemeFor(bg termcolor.RGB) string {
+	affinity := library.AffinityLight
+	if bg.IsDark() {
+		affinity = library.AffinityDark
+	}
+
+	var best string
+	bestScore := -1.0
+	for _, matchOnly := range []bool{true, false} {
+		for _, name := range library.ListThemes() {
+			theme, _ := library.GetTheme(name)
+			if matchOnly && theme.Affinity != affinity && theme.Affinity != "" {
+				continue
+			}
+			if score := themeContrast(theme, bg); score > bestScore {
+				best, bestScore = name, score
+			}
+		}
+		if best != "" {
+			break
+		}
+	}
+
+	if best != "" {
+		currentTheme = best
+	}
+	themeBackground = &bg
+	return currentTheme
+}
+
+// themeContrast returns the lowest contrast ratio of a theme's body colors
+// against bg
+func themeContrast(theme library.ThemeDefinition, bg termcolor.RGB) float64 {
+	lowest := 21.0
+	for _, hex := range theme.Colors {
+		if c, err := termcolor.ParseHex(hex); err == nil {
+			lowest = min(lowest, termcolor.ContrastRatio(c, bg))
+		}
+	}
+	return lowest
+}
+
+// fitBackground adjusts a body color (and the body slot of its palette) to
+// contrast with the background chosen by AutoTheme
+func fitBackground(color string, palette []string) (string, []string) {
+	if themeBackground == nil {
+		return color, palette
+	}
+	c, err := termcolor.ParseHex(color)
+	if err != nil {
+		return color, palette
+	}
+	color = termcolor.EnsureContrast(c, *themeBackground, MinThemeContrast).Hex()
+
+	fitted := append([]string(nil), palette...)
+	if len(fitted) > 0 {
+		fitted[0] = color
+	}
+	return color, fitted
+}
+
 // GetCurrentTheme returns the name of the currently active theme
 func GetCurrentTheme() string {
 	return currentTheme
@@ -110,7 +198,27 @@ func LibraryAgent(name string) (*AgentCharacter, error) {
 	if err != nil {
 		return nil, err
 	}
+	variant, _ := library.GetSizeVariant(library.SizeRegular)
+	return agentFromLibrary(name, libChar, variant)
+}
+
+// LibraryAgentSize retrieves a character at a size variant (nano 4x1,
+// micro 8x2, regular 11x4, large 22x8). When the character has no design
+// at that size the nearest available variant is used; check the
+// character's Width and Height for the one returned.
+func LibraryAgentSize(name string, size library.Size) (*AgentCharacter, error) {
+	libChar, variant, err := library.GetNearestSize(name, size)
+	if err != nil {
+		return nil, err
+	}
+	return agentFromLibrary(strings.TrimSuffix(name, variant.Suffix), libChar, variant)
+}
 
+// agentFromLibrary builds an agent from a library character. States take
+// their playback settings from the character, then from the variant's
+// shared state registry, then the variant's default frame rate. Colors
+// come from the current theme entry for baseName.
+func agentFromLibrary(baseName string, libChar library.LibraryCharacter, variant library.SizeVariant) (*AgentCharacter, error) {
 	// Convert library character to domain character
 	compiler := infrastructure.NewPatternCompiler()
 
@@ -127,6 +235,8 @@ func LibraryAgent(name string) (*AgentCharacter, error) {
 		frames[i] = domain.Frame{
 			Name:  frame.Name,
 			Lines: lines,
+			FG:    frame.FG,
+			BG:    frame.BG,
 		}
 	}
 
@@ -168,10 +278,16 @@ func LibraryAgent(name string) (*AgentCharacter, error) {
 
 	// Create states from grouped frames
 	for stateName, stateFramesList := range stateFrames {
-		// Get FPS from state registry if available, default to 5
-		fps := 5
-		if stateDef, ok := stateregistry.Get(stateName); ok && stateDef.FPS > 0 {
-			fps = stateDef.FPS
+		info, ok := libChar.States[stateName]
+		if !ok {
+			info, _ = variant.SharedState(stateName)
+		}
+		fps := variant.DefaultFPS
+		if info.FPS > 0 {
+			fps = info.FPS
+		}
+		if _, err := effects.Build(info.Effects); err != nil {
+			return nil, fmt.Errorf("state %q: %w", stateName, err)
 		}
 
 		states[stateName] = domain.State{
@@ -181,6 +297,9 @@ func LibraryAgent(name string) (*AgentCharacter, error) {
 			StateType:      "standard",
 			AnimationFPS:   fps,
 			AnimationLoops: 1,
+			NoisePool:      info.NoisePool,
+			NoiseRate:      info.NoiseRate,
+			Effects:        info.Effects,
 		}
 	}
 
@@ -189,21 +308,27 @@ func LibraryAgent(name string) (*AgentCharacter, error) {
 	if err != nil {
 		// Fallback to library color if theme not found
 		theme = library.ThemeDefinition{
-			Colors: map[string]string{name: libChar.Color},
+			Colors: map[string]string{baseName: libChar.Color},
 		}
 	}
 
-	color, err := theme.GetColor(name)
+	color, err := theme.GetColor(baseName)
 	if err != nil {
 		// Fallback to library color if character not in theme
 		color = libChar.Color
 	}
+	palette, err := theme.GetPalette(baseName)
+	if err != nil {
+		palette = []string{color}
+	}
+	color, palette = fitBackground(color, palette)
 
 	// Create domain character
 	domainChar := &domain.Character{
 		Name:        libChar.Name,
 		Personality: "", // No personality for library characters
 		Color:       color, // Use theme color
+		Palette:     palette,
 		Width:       libChar.Width,
 		Height:      libChar.Height,
 		BaseFrame:   baseFrame,
@@ -229,117 +354,14 @@ func LibraryInfo(name string) (string, error) {
 	return libChar.Description, nil
 }
 
-// LibraryAgentMicro retrieves a micro (10x2) character variant from the library
+// LibraryAgentMicro retrieves a micro (8x2) character variant from the library
 func LibraryAgentMicro(name string) (*AgentCharacter, error) {
 	libChar, err := library.GetMicro(name)
 	if err != nil {
 		return nil, err
 	}
-
-	// Convert library character to domain character
-	compiler := infrastructure.NewPatternCompiler()
-
-	// Convert frames
-	frames := make([]domain.Frame, len(libChar.Patterns))
-	for i, frame := range libChar.Patterns {
-		// Compile patterns to actual character lines
-		lines := make([]string, len(frame.Lines))
-		for j, pattern := range frame.Lines {
-			compiled := compiler.Compile(pattern)
-			lines[j] = compiled
-		}
-
-		frames[i] = domain.Frame{
-			Name:  frame.Name,
-			Lines: lines,
-		}
-	}
-
-	// Separate base frame from state frames
-	var baseFrame domain.Frame
-	states := make(map[string]domain.State)
-
-	// Find base frame (first frame named "base" or first frame if no "base")
-	baseFrameFound := false
-	for _, frame := range frames {
-		if frame.Name == "base" {
-			baseFrame = frame
-			baseFrameFound = true
-			break
-		}
-	}
-
-	// If no "base" frame found, use the first frame as base
-	if !baseFrameFound && len(frames) > 0 {
-		baseFrame = frames[0]
-	}
-
-	// Group remaining frames by state name
-	stateFrames := make(map[string][]domain.Frame)
-	for _, frame := range frames {
-		if frame.Name != "base" && frame.Name != baseFrame.Name {
-			// Extract state name from pattern name
-			stateName := frame.Name
-			if strings.Contains(frame.Name, "_") {
-				// Handle patterns like "plan_1", "think_2" -> "plan", "think"
-				parts := strings.Split(frame.Name, "_")
-				if len(parts) >= 2 {
-					stateName = parts[0]
-				}
-			}
-			stateFrames[stateName] = append(stateFrames[stateName], frame)
-		}
-	}
-
-	// Create states from grouped frames
-	for stateName, stateFramesList := range stateFrames {
-		// Get FPS from micro state registry if available, default to 20 for micro (rush effect)
-		fps := 20
-		if microState := microstateregistry.GetState(stateName); microState != nil && microState.FPS > 0 {
-			fps = microState.FPS
-		}
-
-		states[stateName] = domain.State{
-			Name:           stateName,
-			Description:    fmt.Sprintf("%s state", stateName),
-			Frames:         stateFramesList,
-			StateType:      "standard",
-			AnimationFPS:   fps,
-			AnimationLoops: 1,
-		}
-	}
-
-	// Get color from current theme
-	// Extract base name (remove -micro suffix)
-	baseName := strings.TrimSuffix(name, "-micro")
-	theme, err := library.GetTheme(currentTheme)
-	if err != nil {
-		// Fallback to library color if theme not found
-		theme = library.ThemeDefinition{
-			Colors: map[string]string{baseName: libChar.Color},
-		}
-	}
-
-	color, err := theme.GetColor(baseName)
-	if err != nil {
-		// Fallback to library color if character not in theme
-		color = libChar.Color
-	}
-
-	// Create domain character
-	domainChar := &domain.Character{
-		Name:        libChar.Name,
-		Personality: "",
-		Color:       color,
-		Width:       libChar.Width,
-		Height:      libChar.Height,
-		BaseFrame:   baseFrame,
-		States:      states,
-		Frames:      frames,
-	}
-
-	// Wrap in AgentCharacter for state-based API
-	return NewAgentCharacter(domainChar), nil
+	variant, _ := library.GetSizeVariant(library.SizeMicro)
+	return agentFromLibrary(strings.TrimSuffix(name, variant.Suffix), libChar, variant)
 }
 
 // ListMicroLibrary returns all available micro character names
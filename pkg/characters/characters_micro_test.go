@@ -3,6 +3,8 @@ package characters
 import (
 	"bytes"
 	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/library"
 )
 
 func TestLibraryAgentMicro_AllCharacters(t *testing.T) {
@@ -144,3 +146,28 @@ func TestLibraryAgentMicro_FrameCache(t *testing.T) {
 		}
 	}
 }
+
+func TestLibraryAgentSize(t *testing.T) {
+	tests := []struct {
+		size          library.Size
+		width, height int
+	}{
+		{library.SizeMicro, 8, 2},
+		{library.SizeRegular, 11, 4},
+		{library.SizeLarge, 11, 4}, // no large design, nearest is regular
+		{library.SizeNano, 8, 2},   // no nano design, nearest is micro
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.size), func(t *testing.T) {
+			agent, err := LibraryAgentSize("sam", tt.size)
+			if err != nil {
+				t.Fatalf("LibraryAgentSize(sam, %s) error = %v", tt.size, err)
+			}
+			char := agent.GetCharacter()
+			if char.Width != tt.width || char.Height != tt.height {
+				t.Errorf("LibraryAgentSize(sam, %s) = %dx%d, want %dx%d", tt.size, char.Width, char.Height, tt.width, tt.height)
+			}
+		})
+	}
+}
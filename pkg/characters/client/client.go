@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"github.com/wildreason/tangent/pkg/characters"
-	"github.com/wildreason/tangent/pkg/characters/micronoise"
+	"github.com/wildreason/tangent/pkg/characters/effects"
+	"github.com/wildreason/tangent/pkg/characters/library"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
 )
 
 // TangentClient is a framework-agnostic animation controller for tangent characters.
@@ -59,11 +61,8 @@ type TangentClient struct {
 	tickerDone chan struct{}
 	running    bool
 
-	// Micro avatar support
-	isMicro      bool
-	width        int
-	height       int
-	noiseCounter int // Frame counter for gradient animation
+	// Render-time noise for "$" cells (per-client RNG)
+	noise *patterns.NoiseSource
 
 	// Idle expressions
 	expressions          []string
@@ -91,11 +90,19 @@ func NewMicro(name string) (*TangentClient, error) {
 	return newClient(agent), nil
 }
 
+// NewSize creates a TangentClient for a character at a size variant, or
+// the nearest size the character is available in.
+func NewSize(name string, size library.Size) (*TangentClient, error) {
+	agent, err := characters.LibraryAgentSize(name, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s character %q: %w", size, name, err)
+	}
+
+	return newClient(agent), nil
+}
+
 func newClient(agent *characters.AgentCharacter) *TangentClient {
 	cache := agent.GetFrameCache()
-	char := agent.GetCharacter()
-	isMicro := char.Width == 8 && char.Height == 2
-
 	c := &TangentClient{
 		cache:        cache,
 		agent:        agent,
@@ -103,10 +110,8 @@ func newClient(agent *characters.AgentCharacter) *TangentClient {
 		defaultFPS:   5,
 		stateFPS:     make(map[string]int),
 		aliases:      make(map[string]string),
-		// Micro avatar fields
-		isMicro: isMicro,
-		width:   char.Width,
-		height:  char.Height,
+		// Render-time noise
+		noise: patterns.NewNoiseSource(time.Now().UnixNano()),
 		// Idle expressions
 		expressions: DefaultIdleExpressions,
 	}
@@ -315,23 +320,23 @@ func (c *TangentClient) resolveState(name string) string {
 
 // GetFrame returns the current animation frame as pre-colored lines.
 // This is safe to call from any goroutine.
-// For micro avatars (8x2), applies random color flicker effect.
+// Noise cells ($) are filled from the state's noise pool.
+// The state's effects (gradient, pulse, ...) are applied for any character size.
 func (c *TangentClient) GetFrame() []string {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
 	frames := c.cache.GetStateFrames(c.currentState)
 	if len(frames) == 0 {
-		return c.cache.GetBaseFrame()
+		return c.cache.ApplyNoise(c.cache.GetBaseFrame(), c.currentState, c.noise)
 	}
-	lines := frames[c.frameIndex%len(frames)]
+	lines := c.cache.ApplyNoise(frames[c.frameIndex%len(frames)], c.currentState, c.noise)
 
-	// Apply shifting gradient effect for micro avatars
-	if c.isMicro {
-		if cfg := micronoise.GetConfig(c.currentState); cfg != nil {
-			lines = micronoise.ApplyShiftingGradient(lines, c.width, c.height, c.noiseCounter, cfg)
-		}
-	}
+	// Apply the state's effects (shifting gradient, ...)
+	lines = c.cache.ApplyEffects(lines, c.currentState, effects.Context{
+		Frame:   c.frameCount,
+		Elapsed: time.Duration(c.frameCount) * time.Second / time.Duration(c.effectiveFPS()),
+	})
 
 	return lines
 }
@@ -354,11 +359,6 @@ func (c *TangentClient) Tick() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	// Increment noise counter for gradient animation
-	if c.isMicro {
-		c.noiseCounter++
-	}
-
 	frames := c.cache.GetStateFrames(c.currentState)
 	if len(frames) == 0 {
 		return
@@ -557,6 +557,35 @@ func (c *TangentClient) GetColor() string {
 	return c.cache.GetColor()
 }
 
+// SetGlyphSet re-renders the character with a different glyph set, e.g.
+// "ascii" or "safe" for terminals that render quadrant blocks badly.
+// See patterns.ListGlyphSets for available names.
+func (c *TangentClient) SetGlyphSet(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.agent.SetGlyphSet(name); err != nil {
+		return err
+	}
+	c.cache = c.agent.GetFrameCache()
+	return nil
+}
+
+// GetGlyphSet returns the glyph set used to render frames.
+func (c *TangentClient) GetGlyphSet() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.cache.GetGlyphSet()
+}
+
+// SetNoiseSeed reseeds the client's noise RNG. Clients with the same seed
+// fill noise cells ($) identically, which is useful for tests and recordings.
+func (c *TangentClient) SetNoiseSeed(seed int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.noise = patterns.NewNoiseSource(seed)
+}
+
 // GetDimensions returns the character's width and height.
 func (c *TangentClient) GetDimensions() (width, height int) {
 	c.mu.RLock()
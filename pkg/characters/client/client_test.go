@@ -1,9 +1,15 @@
 package client
 
 import (
+	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/wildreason/tangent/pkg/characters"
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
 )
 
 func TestNew(t *testing.T) {
@@ -530,3 +536,104 @@ func TestFallbackToResting(t *testing.T) {
 		t.Errorf("state = %q, want resting (fallback)", c.GetState())
 	}
 }
+
+func TestSetGlyphSet(t *testing.T) {
+	c, _ := NewMicro("sam")
+
+	if err := c.SetGlyphSet("safe"); err != nil {
+		t.Fatalf("SetGlyphSet(safe) error = %v", err)
+	}
+	if c.GetGlyphSet() != "safe" {
+		t.Errorf("GetGlyphSet() = %q, want safe", c.GetGlyphSet())
+	}
+
+	for _, line := range c.GetFrameRaw() {
+		for _, r := range line {
+			if r >= '▖' && r <= '▟' {
+				t.Errorf("safe glyph set frame contains quadrant %q", r)
+			}
+		}
+	}
+
+	if err := c.SetGlyphSet("nope"); err == nil {
+		t.Error("SetGlyphSet(nope) should fail")
+	}
+}
+
+func TestGetFrameNoise(t *testing.T) {
+	char := &domain.Character{
+		Name:      "noisy",
+		Color:     "#FFFFFF",
+		Width:     4,
+		Height:    1,
+		BaseFrame: domain.Frame{Name: "base", Lines: []string{"FFFF"}},
+		States: map[string]domain.State{
+			"resting": {Name: "resting", Frames: []domain.Frame{{Lines: []string{"F$$F"}}}},
+		},
+	}
+	c := newClient(characters.NewAgentCharacter(char))
+	c.SetNoiseSeed(99)
+
+	frame := []rune(c.GetFrameRaw()[0])
+	if len(frame) != 4 {
+		t.Fatalf("GetFrameRaw() = %q, want 4 cells", string(frame))
+	}
+	for _, r := range frame[1:3] {
+		if !strings.ContainsRune(string(patterns.NoisePool), r) {
+			t.Errorf("noise cell = %q, want a rune from the default noise pool", r)
+		}
+	}
+}
+
+func TestGetFrameEffectsFullSize(t *testing.T) {
+	c, err := New("sam")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	c.SetState("read")
+	if !c.cache.HasEffects(c.currentState) {
+		t.Fatalf("state %q has no effects", c.currentState)
+	}
+
+	raw := c.cache.GetStateFrames(c.currentState)[0]
+	frame := c.GetFrame()
+	if strings.Join(frame, "\n") == strings.Join(raw, "\n") {
+		t.Error("11x4 read frame should have the gradient effect applied")
+	}
+	for i := range frame {
+		if stripANSI(frame[i]) != stripANSI(raw[i]) {
+			t.Errorf("line %d glyphs changed: %q -> %q", i, stripANSI(raw[i]), stripANSI(frame[i]))
+		}
+	}
+}
+
+func TestGetFrameColorKeyframes(t *testing.T) {
+	orig := termcolor.CurrentProfile()
+	termcolor.SetProfile(termcolor.TrueColor)
+	defer termcolor.SetProfile(orig)
+
+	c, err := New("sam")
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	c.SetState("error")
+	c.SetStateFPS("error", 2)
+
+	// The error pulse peaks at 500ms: one tick at 2 FPS
+	redness := func() int {
+		fg, ok := termcolor.ExtractForeground(c.GetFrame()[1])
+		if !ok {
+			t.Fatal("error frame has no foreground color")
+		}
+		return int(fg.R) - int(fg.G)
+	}
+	start := redness()
+	c.Tick()
+	if peak := redness(); peak <= start {
+		t.Errorf("redness at 500ms = %d, want more than %d at 0ms", peak, start)
+	}
+	c.Tick()
+	if end := redness(); end != start {
+		t.Errorf("redness after a full pulse = %d, want %d", end, start)
+	}
+}
@@ -1,12 +1,12 @@
 package characters
 
 import (
-	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/wildreason/tangent/pkg/characters/domain"
 	"github.com/wildreason/tangent/pkg/characters/infrastructure"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
 )
 
 // ColorizeFrame compiles pattern codes and applies ANSI RGB colors.
@@ -27,22 +27,32 @@ import (
 //	}
 func ColorizeFrame(frame domain.Frame, hexColor string) []string {
 	compiler := infrastructure.NewPatternCompiler()
-	result := make([]string, len(frame.Lines))
+	return renderFrame(compiler, frame, &domain.Character{Color: hexColor})
+}
 
-	for i, line := range frame.Lines {
-		compiledLine := compiler.Compile(line)
-		if hexColor != "" {
-			compiledLine = ColorizeString(compiledLine, hexColor)
-		}
-		result[i] = compiledLine
+// ColorizeFrameWithPalette is ColorizeFrame for frames with FG/BG color
+// layers. palette[0] is the body color; layer digits select palette slots.
+//
+// Example:
+//
+//	frame := domain.Frame{
+//	    Lines: []string{"_RFFFL_", "_TTTTT_"},
+//	    BG:    []string{"_______", "__1_1__"},  // eyes below the top halves
+//	}
+//	lines := ColorizeFrameWithPalette(frame, []string{"#E78284", "#FFFFFF"})
+func ColorizeFrameWithPalette(frame domain.Frame, palette []string) []string {
+	compiler := infrastructure.NewPatternCompiler()
+	char := &domain.Character{Palette: palette}
+	if len(palette) > 0 {
+		char.Color = palette[0]
 	}
-
-	return result
+	return renderFrame(compiler, frame, char)
 }
 
-// ColorizeString wraps text with ANSI RGB color escape codes.
-// Format: \x1b[38;2;R;G;Bm{text}\x1b[0m
-// If hexColor is empty, returns text unchanged.
+// ColorizeString wraps text with ANSI color escape codes for the active
+// color profile (see SetColorProfile).
+// TrueColor format: \x1b[38;2;R;G;Bm{text}\x1b[0m
+// If hexColor is empty or the profile is NoColor, returns text unchanged.
 //
 // This is useful for applying colors to arbitrary strings or
 // for consumers who already have compiled frame lines.
@@ -57,7 +67,25 @@ func ColorizeString(text, hexColor string) string {
 		return text
 	}
 	r, g, b := HexToRGB(hexColor)
-	return fmt.Sprintf("\x1b[38;2;%d;%d;%dm%s\x1b[0m", r, g, b, text)
+	return termcolor.Default().Colorize(text, termcolor.RGB{R: uint8(r), G: uint8(g), B: uint8(b)})
+}
+
+// SetColorProfile overrides the terminal color profile used by ColorizeString,
+// FrameCache and the micronoise effects. By default the profile is detected
+// from NO_COLOR, COLORTERM and TERM on first use.
+//
+// Frame caches already built keep the profile they were rendered with.
+//
+// Example:
+//
+//	characters.SetColorProfile(termcolor.ANSI256)  // tmux without truecolor
+func SetColorProfile(p termcolor.Profile) {
+	termcolor.SetProfile(p)
+}
+
+// GetColorProfile returns the active terminal color profile.
+func GetColorProfile() termcolor.Profile {
+	return termcolor.CurrentProfile()
 }
 
 // HexToRGB converts hex color string to RGB values.
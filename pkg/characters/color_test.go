@@ -1,12 +1,21 @@
 package characters
 
 import (
+	"os"
 	"strings"
 	"testing"
 
 	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
 )
 
+// TestMain pins the truecolor profile so escape assertions don't depend on
+// the TERM/COLORTERM of whoever runs the tests.
+func TestMain(m *testing.M) {
+	SetColorProfile(termcolor.TrueColor)
+	os.Exit(m.Run())
+}
+
 func TestHexToRGB(t *testing.T) {
 	tests := []struct {
 		name string
@@ -83,6 +92,29 @@ func TestColorizeString(t *testing.T) {
 	}
 }
 
+func TestColorizeStringProfiles(t *testing.T) {
+	defer SetColorProfile(GetColorProfile())
+
+	tests := []struct {
+		profile termcolor.Profile
+		want    string
+	}{
+		{termcolor.TrueColor, "\x1b[38;2;255;0;0mx\x1b[0m"},
+		{termcolor.ANSI256, "\x1b[38;5;196mx\x1b[0m"},
+		{termcolor.ANSI16, "\x1b[91mx\x1b[0m"},
+		{termcolor.NoColor, "x"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile.String(), func(t *testing.T) {
+			SetColorProfile(tt.profile)
+			if got := ColorizeString("x", "#FF0000"); got != tt.want {
+				t.Errorf("ColorizeString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestColorizeFrame(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -209,3 +241,27 @@ func TestColorizeFrameIntegration(t *testing.T) {
 		}
 	}
 }
+
+func TestColorizeFrameWithPalette(t *testing.T) {
+	frame := domain.Frame{
+		Name:  "test",
+		Lines: []string{"TT"},
+		FG:    []string{"01"},
+		BG:    []string{"_2"},
+	}
+
+	result := ColorizeFrameWithPalette(frame, []string{"#FF0000", "#00FF00", "#0000FF"})
+	want := "\x1b[38;2;255;0;0m▀\x1b[38;2;0;255;0m\x1b[48;2;0;0;255m▀\x1b[0m"
+	if len(result) != 1 || result[0] != want {
+		t.Errorf("ColorizeFrameWithPalette() = %q, want %q", result, want)
+	}
+}
+
+func TestColorizeFrameWithPaletteNoLayers(t *testing.T) {
+	frame := domain.Frame{Name: "test", Lines: []string{"FF"}}
+
+	result := ColorizeFrameWithPalette(frame, []string{"#C0C0C0"})
+	if want := "\x1b[38;2;192;192;192m██\x1b[0m"; result[0] != want {
+		t.Errorf("frame without layers = %q, want whole line in slot 0 color %q", result[0], want)
+	}
+}
@@ -5,8 +5,9 @@ import "strings"
 // Character represents a terminal character with frames and agent states
 type Character struct {
 	Name        string
-	Personality string // Optional: "efficient", "friendly", "analytical", "creative"
-	Color       string // Hex color for the character (e.g., "#FF4500")
+	Personality string   // Optional: "efficient", "friendly", "analytical", "creative"
+	Color       string   // Hex color for the character (e.g., "#FF4500")
+	Palette     []string // Optional colors addressed by frame color layers; when empty, slot 0 is Color
 	Width       int
 	Height      int
 	BaseFrame   Frame            // Idle/immutable base character
@@ -18,16 +19,61 @@ type Character struct {
 type State struct {
 	Name           string
 	Description    string
-	Frames         []Frame // Multiple frames for animation
-	StateType      string  // "standard" or "custom"
-	AnimationFPS   int     // FPS for this state (default: 5)
-	AnimationLoops int     // Loop count for this state (default: 1)
+	Frames         []Frame      // Multiple frames for animation
+	StateType      string       // "standard" or "custom"
+	AnimationFPS   int          // FPS for this state (default: 5)
+	AnimationLoops int          // Loop count for this state (default: 1)
+	NoisePool      string       // Pattern codes noise cells ($) pick from ("" = default pool)
+	NoiseRate      int          // Noise changes per second, independent of FPS (0 = default)
+	Effects        []EffectSpec // Render effects applied in order (gradient, pulse, ...)
 }
 
 // Frame represents a single frame of animation
+//
+// FG and BG are optional color layers parallel to Lines: one rune per cell,
+// where a digit selects a palette slot (see Character.SlotColor) and any
+// other rune keeps the default (character color for FG, terminal
+// background for BG). A colored background behind ▀/▄ gives a cell two
+// independently colored pixels.
 type Frame struct {
 	Name  string
 	Lines []string
+	FG    []string // Optional foreground color layer
+	BG    []string // Optional background color layer
+}
+
+// Palette slot roles. Themes supply palettes in this order; a palette with
+// only a body color is the single-color case.
+const (
+	PaletteBody   = 0
+	PaletteEyes   = 1
+	PaletteAccent = 2
+)
+
+// HasColorLayers reports whether the frame carries per-cell colors
+func (f Frame) HasColorLayers() bool {
+	return len(f.FG) > 0 || len(f.BG) > 0
+}
+
+// ColorSlot returns the palette slot selected by a color layer rune,
+// or -1 if the rune selects the default color.
+func ColorSlot(r rune) int {
+	if r >= '0' && r <= '9' {
+		return int(r - '0')
+	}
+	return -1
+}
+
+// SlotColor returns the hex color of a palette slot.
+// Slot 0 falls back to Color when no palette is set; unknown slots return "".
+func (c *Character) SlotColor(slot int) string {
+	if slot >= 0 && slot < len(c.Palette) {
+		return c.Palette[slot]
+	}
+	if slot == 0 {
+		return c.Color
+	}
+	return ""
 }
 
 // CharacterSpec represents the specification for creating a character
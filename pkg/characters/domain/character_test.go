@@ -190,3 +190,39 @@ func TestCharacterSpec_AddFrameFromString_MixedWhitespace(t *testing.T) {
 		}
 	}
 }
+
+func TestCharacter_SlotColor(t *testing.T) {
+	char := &Character{Color: "#111111", Palette: []string{"#AAAAAA", "#BBBBBB"}}
+	plain := &Character{Color: "#111111"}
+
+	tests := []struct {
+		name string
+		char *Character
+		slot int
+		want string
+	}{
+		{"palette slot 0", char, 0, "#AAAAAA"},
+		{"palette slot 1", char, 1, "#BBBBBB"},
+		{"slot out of range", char, 5, ""},
+		{"default rune", char, ColorSlot('_'), ""},
+		{"no palette slot 0", plain, 0, "#111111"},
+		{"no palette slot 1", plain, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.char.SlotColor(tt.slot); got != tt.want {
+				t.Errorf("SlotColor(%d) = %q, want %q", tt.slot, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFrame_HasColorLayers(t *testing.T) {
+	if (Frame{Lines: []string{"FF"}}).HasColorLayers() {
+		t.Error("frame without layers reports color layers")
+	}
+	if !(Frame{Lines: []string{"FF"}, BG: []string{"11"}}).HasColorLayers() {
+		t.Error("frame with a BG layer reports no color layers")
+	}
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// EffectSpec declares one render effect of a state. In JSON the parameters
+// sit next to the type:
+//
+//	{"type": "gradient", "direction": "rtl", "speed": 2}
+//
+// Params holds every key except "type", decoded as JSON values
+// (float64, string, bool, []any).
+type EffectSpec struct {
+	Type   string
+	Params map[string]any
+}
+
+// MarshalJSON writes the spec as a flat object
+func (e EffectSpec) MarshalJSON() ([]byte, error) {
+	obj := make(map[string]any, len(e.Params)+1)
+	for k, v := range e.Params {
+		obj[k] = v
+	}
+	obj["type"] = e.Type
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON reads a flat object with a required "type" key
+func (e *EffectSpec) UnmarshalJSON(data []byte) error {
+	var obj map[string]any
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	typ, ok := obj["type"].(string)
+	if !ok || typ == "" {
+		return fmt.Errorf("effect is missing a \"type\"")
+	}
+	delete(obj, "type")
+	e.Type = typ
+	e.Params = obj
+	return nil
+}
+
+// Float returns a numeric parameter, or def if absent or not a number
+func (e EffectSpec) Float(name string, def float64) float64 {
+	if v, ok := e.Params[name].(float64); ok {
+		return v
+	}
+	if v, ok := e.Params[name].(int); ok {
+		return float64(v)
+	}
+	return def
+}
+
+// Int returns a numeric parameter truncated to int, or def if absent
+func (e EffectSpec) Int(name string, def int) int {
+	return int(e.Float(name, float64(def)))
+}
+
+// String returns a string parameter, or def if absent
+func (e EffectSpec) String(name, def string) string {
+	if v, ok := e.Params[name].(string); ok {
+		return v
+	}
+	return def
+}
+
+// Floats returns a list-of-numbers parameter, or def if absent or malformed
+func (e EffectSpec) Floats(name string, def []float64) []float64 {
+	switch v := e.Params[name].(type) {
+	case []float64:
+		return v
+	case []any:
+		out := make([]float64, len(v))
+		for i, item := range v {
+			f, ok := item.(float64)
+			if !ok {
+				return def
+			}
+			out[i] = f
+		}
+		return out
+	}
+	return def
+}
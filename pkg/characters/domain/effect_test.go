@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEffectSpecJSON(t *testing.T) {
+	var spec EffectSpec
+	if err := json.Unmarshal([]byte(`{"type":"gradient","direction":"rtl","speed":2,"levels":[0.5,1]}`), &spec); err != nil {
+		t.Fatalf("Unmarshal error = %v", err)
+	}
+
+	if spec.Type != "gradient" {
+		t.Errorf("Type = %q, want gradient", spec.Type)
+	}
+	if got := spec.String("direction", ""); got != "rtl" {
+		t.Errorf("String(direction) = %q, want rtl", got)
+	}
+	if got := spec.Int("speed", 1); got != 2 {
+		t.Errorf("Int(speed) = %d, want 2", got)
+	}
+	if got := spec.Floats("levels", nil); len(got) != 2 || got[1] != 1 {
+		t.Errorf("Floats(levels) = %v, want [0.5 1]", got)
+	}
+	if got := spec.Float("missing", 3.5); got != 3.5 {
+		t.Errorf("Float(missing) = %v, want default 3.5", got)
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("Marshal error = %v", err)
+	}
+	var back EffectSpec
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("round trip Unmarshal error = %v", err)
+	}
+	if back.Type != "gradient" || back.Int("speed", 0) != 2 {
+		t.Errorf("round trip = %+v", back)
+	}
+}
+
+func TestEffectSpecMissingType(t *testing.T) {
+	var spec EffectSpec
+	if err := json.Unmarshal([]byte(`{"speed":2}`), &spec); err == nil {
+		t.Error("Unmarshal without type should fail")
+	}
+}
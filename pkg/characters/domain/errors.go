@@ -2,6 +2,7 @@ package domain
 
 import (
 	"fmt"
+	"strings"
 )
 
 // Error types for better error handling
@@ -49,6 +50,27 @@ func (e *PatternCompilationError) Unwrap() error {
 	return e.Cause
 }
 
+// DecompileError reports the runes of a line that have no pattern code.
+// Positions are rune columns from 0.
+type DecompileError struct {
+	Line     string
+	Unmapped []UnmappedRune
+}
+
+// UnmappedRune is a rune without a pattern code and where it was found
+type UnmappedRune struct {
+	Position int
+	Rune     rune
+}
+
+func (e *DecompileError) Error() string {
+	parts := make([]string, len(e.Unmapped))
+	for i, u := range e.Unmapped {
+		parts[i] = fmt.Sprintf("%q at %d", u.Rune, u.Position)
+	}
+	return fmt.Sprintf("can't decompile '%s': no pattern code for %s", e.Line, strings.Join(parts, ", "))
+}
+
 type AnimationError struct {
 	CharacterName string
 	Operation     string
@@ -5,3 +5,9 @@ type PatternCompiler interface {
 	Compile(pattern string) string
 	Validate(pattern string) error
 }
+
+// PatternDecompiler defines the interface for converting rendered block
+// art back to pattern codes
+type PatternDecompiler interface {
+	Decompile(line string) (string, error)
+}
@@ -0,0 +1,236 @@
+package effects
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+func init() {
+	Register("gradient", newGradient)
+	Register("pulse", newPulse)
+	Register("breathe", newBreathe)
+	Register("rainbow", newRainbow)
+	Register("glitch", newGlitch)
+	Register("scanline", newScanline)
+	Register("fade", newFade)
+	Register("keyframes", newKeyframes)
+}
+
+// Pulse scales the whole frame's brightness along a cosine wave.
+//
+// Parameters: min, max (brightness multipliers, default 0.6 and 1.2),
+// period (frames per cycle, default 12).
+type Pulse struct {
+	Min, Max float64
+	Period   int
+}
+
+func newPulse(spec domain.EffectSpec) (Effect, error) {
+	return buildPulse(spec, 0.6, 1.2, 12)
+}
+
+// newBreathe is a slower, softer pulse
+func newBreathe(spec domain.EffectSpec) (Effect, error) {
+	return buildPulse(spec, 0.75, 1.05, 32)
+}
+
+func buildPulse(spec domain.EffectSpec, min, max float64, period int) (Effect, error) {
+	p := &Pulse{
+		Min:    spec.Float("min", min),
+		Max:    spec.Float("max", max),
+		Period: spec.Int("period", period),
+	}
+	if p.Period < 1 {
+		return nil, fmt.Errorf("period must be at least 1, got %d", p.Period)
+	}
+	return p, nil
+}
+
+// Apply implements Effect
+func (p *Pulse) Apply(grid Grid, ctx Context) {
+	phase := float64(mod(ctx.Frame, p.Period)) / float64(p.Period)
+	f := p.Min + (p.Max-p.Min)*(1-math.Cos(2*math.Pi*phase))/2
+	for _, row := range grid {
+		for col := range row {
+			scaleCell(&row[col], f)
+		}
+	}
+}
+
+// Rainbow cycles hue across columns and over time, keeping each cell's
+// lightness.
+//
+// Parameters: speed (degrees per frame, default 20), spread (degrees per
+// column, default 30), saturation (0 keeps the cell's own, default 0).
+type Rainbow struct {
+	Speed, Spread float64
+	Saturation    float64
+}
+
+func newRainbow(spec domain.EffectSpec) (Effect, error) {
+	return &Rainbow{
+		Speed:      spec.Float("speed", 20),
+		Spread:     spec.Float("spread", 30),
+		Saturation: spec.Float("saturation", 0),
+	}, nil
+}
+
+// Apply implements Effect
+func (r *Rainbow) Apply(grid Grid, ctx Context) {
+	for _, row := range grid {
+		for col := range row {
+			shift := float64(ctx.Frame)*r.Speed + float64(col)*r.Spread
+			row[col].FG = r.rotate(row[col].FG, shift)
+			if row[col].HasBG {
+				row[col].BG = r.rotate(row[col].BG, shift)
+			}
+		}
+	}
+}
+
+func (r *Rainbow) rotate(c termcolor.RGB, shift float64) termcolor.RGB {
+	h, s, l := c.HSL()
+	if r.Saturation > 0 {
+		s = r.Saturation
+	}
+	return termcolor.FromHSL(h+shift, s, l)
+}
+
+// Glitch occasionally shifts a row sideways and inverts its colors.
+// The same seed, frame and row always glitch the same way.
+//
+// Parameters: chance (per row per frame, default 0.15), shift (max cells,
+// default 1), seed (default 0).
+type Glitch struct {
+	Chance float64
+	Shift  int
+	Seed   int
+}
+
+func newGlitch(spec domain.EffectSpec) (Effect, error) {
+	g := &Glitch{
+		Chance: spec.Float("chance", 0.15),
+		Shift:  spec.Int("shift", 1),
+		Seed:   spec.Int("seed", 0),
+	}
+	if g.Chance < 0 || g.Chance > 1 {
+		return nil, fmt.Errorf("chance must be between 0 and 1, got %g", g.Chance)
+	}
+	return g, nil
+}
+
+// Apply implements Effect
+func (g *Glitch) Apply(grid Grid, ctx Context) {
+	for rowIdx, row := range grid {
+		h := hash(uint64(g.Seed), uint64(ctx.Frame), uint64(rowIdx))
+		if float64(h>>11)/float64(1<<53) >= g.Chance || len(row) == 0 {
+			continue
+		}
+
+		// Shift by 1..Shift cells, direction from the next hash bit
+		offset := 1
+		if g.Shift > 1 {
+			offset += int((h >> 3) % uint64(g.Shift))
+		}
+		if h&1 == 1 {
+			offset = -offset
+		}
+		shifted := make([]termcolor.Cell, len(row))
+		for col := range row {
+			cell := row[mod(col+offset, len(row))]
+			cell.FG = invert(cell.FG)
+			shifted[col] = cell
+		}
+		copy(row, shifted)
+	}
+}
+
+func invert(c termcolor.RGB) termcolor.RGB {
+	return termcolor.RGB{R: 255 - c.R, G: 255 - c.G, B: 255 - c.B}
+}
+
+// hash mixes its inputs with splitmix64
+func hash(values ...uint64) uint64 {
+	var h uint64
+	for _, v := range values {
+		h += v + 0x9E3779B97F4A7C15
+		h = (h ^ (h >> 30)) * 0xBF58476D1CE4E5B9
+		h = (h ^ (h >> 27)) * 0x94D049BB133111EB
+		h ^= h >> 31
+	}
+	return h
+}
+
+// Scanline brightens one row at a time, moving down the frame.
+//
+// Parameters: speed (frames per row, default 1), brightness (lit row
+// multiplier, default 1.4), dim (other rows, default 1.0).
+type Scanline struct {
+	Speed      int
+	Brightness float64
+	Dim        float64
+}
+
+func newScanline(spec domain.EffectSpec) (Effect, error) {
+	s := &Scanline{
+		Speed:      spec.Int("speed", 1),
+		Brightness: spec.Float("brightness", 1.4),
+		Dim:        spec.Float("dim", 1.0),
+	}
+	if s.Speed < 1 {
+		return nil, fmt.Errorf("speed must be at least 1, got %d", s.Speed)
+	}
+	return s, nil
+}
+
+// Apply implements Effect
+func (s *Scanline) Apply(grid Grid, ctx Context) {
+	lit := mod(ctx.Frame/s.Speed, len(grid))
+	for rowIdx, row := range grid {
+		f := s.Dim
+		if rowIdx == lit {
+			f = s.Brightness
+		}
+		for col := range row {
+			scaleCell(&row[col], f)
+		}
+	}
+}
+
+// Fade brings the frame in from black (or out to black) over the start of
+// the state.
+//
+// Parameters: mode ("in" or "out", default "in"), frames (duration,
+// default one loop of the state).
+type Fade struct {
+	Out    bool
+	Frames int
+}
+
+func newFade(spec domain.EffectSpec) (Effect, error) {
+	mode := spec.String("mode", "in")
+	if mode != "in" && mode != "out" {
+		return nil, fmt.Errorf("unknown mode %q (want \"in\" or \"out\")", mode)
+	}
+	return &Fade{Out: mode == "out", Frames: spec.Int("frames", 0)}, nil
+}
+
+// Apply implements Effect
+func (f *Fade) Apply(grid Grid, ctx Context) {
+	frames := f.Frames
+	if frames < 1 {
+		frames = max(ctx.Frames, 1)
+	}
+	t := math.Min(float64(ctx.Frame+1)/float64(frames), 1)
+	if f.Out {
+		t = 1 - t
+	}
+	for _, row := range grid {
+		for col := range row {
+			scaleCell(&row[col], t)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Package effects recolors rendered frames at display time: shifting
+// gradients, pulses, hue cycles, glitches, scanlines, fades and color
+// keyframes.
+//
+// Effects are declared per state in the state JSON files and chained in
+// order:
+//
+//	"effects": [
+//	  {"type": "gradient", "direction": "rtl", "speed": 2},
+//	  {"type": "fade", "mode": "in"}
+//	]
+package effects
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// Grid is a frame split into rows of colored cells. Effects modify it in place.
+type Grid [][]termcolor.Cell
+
+// Width returns the length of the longest row
+func (g Grid) Width() int {
+	width := 0
+	for _, row := range g {
+		width = max(width, len(row))
+	}
+	return width
+}
+
+// Context describes where in a state's animation a frame is shown.
+type Context struct {
+	Frame   int           // Frames shown since the state started
+	Frames  int           // Frames in one loop of the state
+	Elapsed time.Duration // Animation time since the state started
+	Base    termcolor.RGB // Color for cells rendered without one
+}
+
+// Effect recolors (or rearranges) the cells of a frame.
+type Effect interface {
+	Apply(g Grid, ctx Context)
+}
+
+// Factory builds an effect from its declaration, validating parameters.
+type Factory func(spec domain.EffectSpec) (Effect, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register adds or replaces the factory for an effect type.
+func Register(typ string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[typ] = factory
+}
+
+// New builds the effect declared by spec.
+func New(spec domain.EffectSpec) (Effect, error) {
+	registryMu.RLock()
+	factory, ok := registry[spec.Type]
+	registryMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown effect type %q", spec.Type)
+	}
+	effect, err := factory(spec)
+	if err != nil {
+		return nil, fmt.Errorf("effect %q: %w", spec.Type, err)
+	}
+	return effect, nil
+}
+
+// Types returns all registered effect types in sorted order.
+func Types() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	types := make([]string, 0, len(registry))
+	for typ := range registry {
+		types = append(types, typ)
+	}
+	sort.Strings(types)
+	return types
+}
+
+// Pipeline is a chain of effects applied in order.
+type Pipeline []Effect
+
+// Build creates the pipeline for a state's effect declarations.
+func Build(specs []domain.EffectSpec) (Pipeline, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	pipeline := make(Pipeline, 0, len(specs))
+	for _, spec := range specs {
+		effect, err := New(spec)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, effect)
+	}
+	return pipeline, nil
+}
+
+// Apply runs the pipeline over rendered lines and re-renders them with the
+// active color profile. An empty pipeline returns lines unchanged.
+func (p Pipeline) Apply(lines []string, ctx Context) []string {
+	if len(p) == 0 || len(lines) == 0 {
+		return lines
+	}
+
+	grid := make(Grid, len(lines))
+	for i, line := range lines {
+		cells := termcolor.ParseCells(line)
+		for j := range cells {
+			if !cells[j].HasFG {
+				cells[j].FG, cells[j].HasFG = ctx.Base, true
+			}
+		}
+		grid[i] = cells
+	}
+
+	for _, effect := range p {
+		effect.Apply(grid, ctx)
+	}
+
+	renderer := termcolor.Default()
+	result := make([]string, len(grid))
+	for i, row := range grid {
+		result[i] = renderer.RenderCells(row)
+	}
+	return result
+}
+
+// scaleCell multiplies a cell's colors by f
+func scaleCell(c *termcolor.Cell, f float64) {
+	c.FG = c.FG.Scale(f)
+	if c.HasBG {
+		c.BG = c.BG.Scale(f)
+	}
+}
+
+// mod is the always-positive remainder
+func mod(a, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return ((a % n) + n) % n
+}
@@ -0,0 +1,384 @@
+package effects
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+func TestMain(m *testing.M) {
+	termcolor.SetProfile(termcolor.TrueColor)
+	m.Run()
+}
+
+// grid builds a one-row grid of n white cells
+func grid(n int) Grid {
+	row := make([]termcolor.Cell, n)
+	for i := range row {
+		row[i] = termcolor.Cell{Rune: '█', FG: termcolor.RGB{R: 200, G: 200, B: 200}, HasFG: true}
+	}
+	return Grid{row}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name    string
+		specs   []domain.EffectSpec
+		wantErr bool
+	}{
+		{"empty", nil, false},
+		{"all builtins", []domain.EffectSpec{
+			{Type: "gradient"}, {Type: "pulse"}, {Type: "breathe"}, {Type: "rainbow"},
+			{Type: "glitch"}, {Type: "scanline"}, {Type: "fade"},
+		}, false},
+		{"unknown type", []domain.EffectSpec{{Type: "sparkle"}}, true},
+		{"bad direction", []domain.EffectSpec{{Type: "gradient", Params: map[string]any{"direction": "up-ish"}}}, true},
+		{"bad fade mode", []domain.EffectSpec{{Type: "fade", Params: map[string]any{"mode": "sideways"}}}, true},
+		{"bad color space", []domain.EffectSpec{{Type: "gradient", Params: map[string]any{"space": "cmyk"}}}, true},
+		{"bad lightness range", []domain.EffectSpec{{Type: "gradient", Params: map[string]any{"min_lightness": 0.8, "max_lightness": 0.2}}}, true},
+		{"bad glitch chance", []domain.EffectSpec{{Type: "glitch", Params: map[string]any{"chance": 2.0}}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Build(tt.specs)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Build() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGradientDirection(t *testing.T) {
+	levels := []float64{0.5, 1.0}
+
+	tests := []struct {
+		direction string
+		frame     int
+		want      []uint8 // red channel per column
+	}{
+		{DirectionRTL, 0, []uint8{100, 200, 100}},
+		{DirectionRTL, 1, []uint8{200, 100, 200}},
+		{DirectionLTR, 1, []uint8{200, 100, 200}},
+		{DirectionLTR, 2, []uint8{100, 200, 100}},
+	}
+
+	for _, tt := range tests {
+		g := &Gradient{Levels: levels, Direction: tt.direction, Speed: 1, Period: 2}
+		grid := grid(3)
+		g.Apply(grid, Context{Frame: tt.frame})
+		for col, want := range tt.want {
+			if got := grid[0][col].FG.R; got != want {
+				t.Errorf("%s frame %d col %d R = %d, want %d", tt.direction, tt.frame, col, got, want)
+			}
+		}
+	}
+}
+
+func TestGradientScalesToWidth(t *testing.T) {
+	g := &Gradient{Levels: []float64{0.5, 1.0}, Direction: DirectionRTL, Speed: 1}
+
+	tests := []struct {
+		width int
+		want  []uint8 // red channel per column at frame 0
+	}{
+		{3, []uint8{100, 150, 200}},
+		{5, []uint8{100, 125, 150, 175, 200}},
+	}
+
+	for _, tt := range tests {
+		grid := grid(tt.width)
+		g.Apply(grid, Context{})
+		for col, want := range tt.want {
+			if got := grid[0][col].FG.R; got != want {
+				t.Errorf("width %d col %d R = %d, want %d", tt.width, col, got, want)
+			}
+		}
+	}
+}
+
+func TestGradientMatchesMicroLevels(t *testing.T) {
+	// With 8 levels over 8 columns each column gets exactly one level
+	g := &Gradient{Levels: DefaultLevels, Direction: DirectionRTL, Speed: 1}
+	grid := grid(8)
+	g.Apply(grid, Context{Frame: 3})
+	for col := range grid[0] {
+		want := termcolor.RGB{R: 200, G: 200, B: 200}.Scale(DefaultLevels[(col+3)%8])
+		if got := grid[0][col].FG; got != want {
+			t.Errorf("col %d = %v, want %v", col, got, want)
+		}
+	}
+}
+
+func TestGradientDirections2D(t *testing.T) {
+	levels := []float64{0.5, 1.0}
+
+	// 3x3 grid; red channel per [row][col] at frame 0
+	tests := []struct {
+		direction string
+		want      [3][3]uint8
+	}{
+		{DirectionDown, [3][3]uint8{{200, 200, 200}, {150, 150, 150}, {100, 100, 100}}},
+		{DirectionUp, [3][3]uint8{{100, 100, 100}, {150, 150, 150}, {200, 200, 200}}},
+		{DirectionDiagonal, [3][3]uint8{{100, 125, 150}, {125, 150, 175}, {150, 175, 200}}},
+	}
+
+	for _, tt := range tests {
+		g := &Gradient{Levels: levels, Direction: tt.direction, Speed: 1}
+		grid := Grid{grid(3)[0], grid(3)[0], grid(3)[0]}
+		g.Apply(grid, Context{})
+		for r, row := range tt.want {
+			for c, want := range row {
+				if got := grid[r][c].FG.R; got != want {
+					t.Errorf("%s [%d][%d] R = %d, want %d", tt.direction, r, c, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGradientRadialSymmetric(t *testing.T) {
+	g := &Gradient{Levels: DefaultLevels, Direction: DirectionRadial, Speed: 1}
+	for frame := 0; frame < 4; frame++ {
+		grid := Grid{grid(5)[0], grid(5)[0], grid(5)[0]}
+		g.Apply(grid, Context{Frame: frame})
+		for r := range grid {
+			for c := range grid[r] {
+				if mirror := grid[2-r][4-c]; grid[r][c].FG != mirror.FG {
+					t.Errorf("frame %d: [%d][%d] = %v, mirror = %v", frame, r, c, grid[r][c].FG, mirror.FG)
+				}
+			}
+		}
+	}
+}
+
+func TestGradientPerceptualKeepsHue(t *testing.T) {
+	red := termcolor.RGB{R: 255}
+	for _, space := range []string{SpaceHSL, SpaceOKLCH} {
+		g := &Gradient{Levels: DefaultLevels, Direction: DirectionRTL, Speed: 1, Space: space, MinLightness: 0.3, MaxLightness: 0.9}
+		row := make([]termcolor.Cell, 8)
+		for i := range row {
+			row[i] = termcolor.Cell{Rune: '█', FG: red, HasFG: true}
+		}
+		g.Apply(Grid{row}, Context{Base: red})
+
+		// The brightest column must not clip to plain red and every column
+		// must stay red in hue
+		if row[7].FG == red {
+			t.Errorf("%s: brightest column = %v, want lighter than base", space, row[7].FG)
+		}
+		for col, cell := range row {
+			if h, _, _ := cell.FG.HSL(); h > 15 && h < 345 {
+				t.Errorf("%s: col %d = %v has hue %.0f, want red", space, col, cell.FG, h)
+			}
+		}
+		if l0, l7 := lightness(row[0].FG), lightness(row[7].FG); l0 >= l7 {
+			t.Errorf("%s: lightness col 0 = %.2f, col 7 = %.2f, want increasing", space, l0, l7)
+		}
+	}
+}
+
+func TestGradientPerceptualLightnessRange(t *testing.T) {
+	base := termcolor.RGB{R: 120, G: 140, B: 200}
+	g := &Gradient{Levels: DefaultLevels, Direction: DirectionRTL, Speed: 1, Space: SpaceOKLCH, MinLightness: 0.4, MaxLightness: 0.8}
+	row := make([]termcolor.Cell, 8)
+	for i := range row {
+		row[i] = termcolor.Cell{Rune: '█', FG: base, HasFG: true}
+	}
+	g.Apply(Grid{row}, Context{Base: base})
+	for col, want := range map[int]float64{0: 0.4, 7: 0.8} {
+		if got := lightness(row[col].FG); got < want-0.01 || got > want+0.01 {
+			t.Errorf("col %d L = %.3f, want %.1f", col, got, want)
+		}
+	}
+}
+
+// lightness returns the OKLCH lightness of c
+func lightness(c termcolor.RGB) float64 {
+	l, _, _ := c.OKLCH()
+	return l
+}
+
+func TestFade(t *testing.T) {
+	fadeIn := &Fade{Frames: 4}
+	g := grid(1)
+	fadeIn.Apply(g, Context{Frame: 0})
+	if got := g[0][0].FG.R; got != 50 {
+		t.Errorf("fade in frame 0 R = %d, want 50", got)
+	}
+
+	g = grid(1)
+	fadeIn.Apply(g, Context{Frame: 10})
+	if got := g[0][0].FG.R; got != 200 {
+		t.Errorf("fade in after duration R = %d, want 200", got)
+	}
+
+	fadeOut := &Fade{Out: true}
+	g = grid(1)
+	fadeOut.Apply(g, Context{Frame: 1, Frames: 2})
+	if got := g[0][0].FG.R; got != 0 {
+		t.Errorf("fade out at end of loop R = %d, want 0", got)
+	}
+}
+
+func TestPulse(t *testing.T) {
+	p := &Pulse{Min: 0.5, Max: 1.0, Period: 4}
+
+	g := grid(1)
+	p.Apply(g, Context{Frame: 0})
+	if got := g[0][0].FG.R; got != 100 {
+		t.Errorf("pulse trough R = %d, want 100", got)
+	}
+
+	g = grid(1)
+	p.Apply(g, Context{Frame: 2})
+	if got := g[0][0].FG.R; got != 200 {
+		t.Errorf("pulse peak R = %d, want 200", got)
+	}
+}
+
+func TestGlitchDeterministic(t *testing.T) {
+	g := &Glitch{Chance: 1, Shift: 1, Seed: 3}
+
+	a, b := grid(4), grid(4)
+	a[0][0].Rune, b[0][0].Rune = 'x', 'x'
+	g.Apply(a, Context{Frame: 5})
+	g.Apply(b, Context{Frame: 5})
+
+	for col := range a[0] {
+		if a[0][col] != b[0][col] {
+			t.Fatalf("same seed and frame glitched differently at col %d", col)
+		}
+	}
+	if a[0][0].Rune == 'x' {
+		t.Error("glitch with chance 1 did not shift the row")
+	}
+}
+
+func TestRainbowKeepsLightness(t *testing.T) {
+	r := &Rainbow{Speed: 90, Spread: 0}
+	g := Grid{{{Rune: '█', FG: termcolor.RGB{R: 255}, HasFG: true}}}
+	r.Apply(g, Context{Frame: 1})
+
+	_, _, l := g[0][0].FG.HSL()
+	if l < 0.49 || l > 0.51 {
+		t.Errorf("rainbow lightness = %v, want 0.5", l)
+	}
+	if g[0][0].FG == (termcolor.RGB{R: 255}) {
+		t.Error("rainbow did not rotate the hue")
+	}
+}
+
+func TestPipelineApply(t *testing.T) {
+	p, err := Build([]domain.EffectSpec{{Type: "gradient", Params: map[string]any{"levels": []any{0.5}}}})
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+
+	// Uncolored cells take the base color
+	got := p.Apply([]string{"ab"}, Context{Base: termcolor.RGB{R: 200, G: 100, B: 0}})
+	want := "\x1b[38;2;100;50;0mab\x1b[0m"
+	if got[0] != want {
+		t.Errorf("Apply() = %q, want %q", got[0], want)
+	}
+
+	lines := []string{"ab"}
+	if got := Pipeline(nil).Apply(lines, Context{}); &got[0] != &lines[0] {
+		t.Error("empty pipeline should return lines as-is")
+	}
+}
+
+func TestKeyframesBuild(t *testing.T) {
+	kf := func(items ...map[string]any) map[string]any {
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = item
+		}
+		return map[string]any{"keyframes": list}
+	}
+
+	tests := []struct {
+		name    string
+		params  map[string]any
+		wantErr bool
+	}{
+		{"valid", kf(map[string]any{"at": 0.0, "color": "#FF0000"}, map[string]any{"at": 1.0, "color": "base"}), false},
+		{"missing list", nil, true},
+		{"no position", kf(map[string]any{"color": "#FF0000"}), true},
+		{"two positions", kf(map[string]any{"at": 0.0, "ms": 10.0}), true},
+		{"mixed units", kf(map[string]any{"at": 0.0}, map[string]any{"frame": 2.0}), true},
+		{"at out of range", kf(map[string]any{"at": 1.5}), true},
+		{"bad color", kf(map[string]any{"at": 0.0, "color": "red"}), true},
+		{"bad ease", kf(map[string]any{"at": 0.0, "ease": "bounce"}), true},
+		{"bad mix", kf(map[string]any{"at": 0.0, "color": "#FF0000", "mix": 2.0}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(domain.EffectSpec{Type: "keyframes", Params: tt.params})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyframesTimeline(t *testing.T) {
+	red := termcolor.RGB{R: 255}
+	white := termcolor.RGB{R: 200, G: 200, B: 200}
+
+	tests := []struct {
+		name string
+		k    *Keyframes
+		ctx  Context
+		want termcolor.RGB
+	}{
+		{
+			"loop start is base",
+			&Keyframes{Unit: UnitLoop, Loop: true, Frames: []Keyframe{{At: 0, Ease: "linear"}, {At: 1, Color: red, Mix: 1}}},
+			Context{Frame: 0, Frames: 3}, white,
+		},
+		{
+			"loop midpoint",
+			&Keyframes{Unit: UnitLoop, Loop: true, Frames: []Keyframe{{At: 0, Ease: "linear"}, {At: 1, Color: red, Mix: 1}}},
+			Context{Frame: 1, Frames: 3}, white.Lerp(red, 0.5),
+		},
+		{
+			"loop end reaches last keyframe",
+			&Keyframes{Unit: UnitLoop, Loop: true, Frames: []Keyframe{{At: 0, Ease: "linear"}, {At: 1, Color: red, Mix: 1}}},
+			Context{Frame: 2, Frames: 3}, red,
+		},
+		{
+			"loop wraps",
+			&Keyframes{Unit: UnitLoop, Loop: true, Frames: []Keyframe{{At: 0, Ease: "linear"}, {At: 1, Color: red, Mix: 1}}},
+			Context{Frame: 3, Frames: 3}, white,
+		},
+		{
+			"no loop holds last keyframe",
+			&Keyframes{Unit: UnitLoop, Frames: []Keyframe{{At: 0, Color: red, Mix: 1, Ease: "linear"}, {At: 1}}},
+			Context{Frame: 7, Frames: 3}, white,
+		},
+		{
+			"frame index",
+			&Keyframes{Unit: UnitFrame, Loop: true, Frames: []Keyframe{{At: 0, Ease: "step"}, {At: 2, Color: red, Mix: 1}}},
+			Context{Frame: 1, Frames: 3}, white,
+		},
+		{
+			"milliseconds with ease-in",
+			&Keyframes{Unit: UnitMS, Loop: true, Frames: []Keyframe{{At: 0, Ease: "ease-in"}, {At: 1000, Color: red, Mix: 1}}},
+			Context{Elapsed: 1500 * time.Millisecond}, white.Lerp(red, 0.25),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := grid(1)
+			tt.k.Apply(g, tt.ctx)
+			if got := g[0][0].FG; got != tt.want {
+				t.Errorf("FG = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package effects
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// Sweep directions for the gradient effect
+const (
+	DirectionRTL      = "rtl"      // Bright band moves right to left (the classic marquee)
+	DirectionLTR      = "ltr"      // Bright band moves left to right
+	DirectionDown     = "down"     // Band moves top to bottom
+	DirectionUp       = "up"       // Band moves bottom to top
+	DirectionDiagonal = "diagonal" // Band moves from the bottom right to the top left
+	DirectionRadial   = "radial"   // Rings expand from the center
+)
+
+// Color spaces the gradient can work in
+const (
+	SpaceRGB   = "rgb"   // Multiply channels by the level (clips saturated colors)
+	SpaceHSL   = "hsl"   // Sweep HSL lightness
+	SpaceOKLCH = "oklch" // Sweep perceptual OKLCH lightness
+)
+
+// DefaultLevels are the brightness multipliers of the shifting gradient,
+// dark to bright.
+var DefaultLevels = []float64{0.25, 0.40, 0.55, 0.70, 0.85, 1.00, 1.15, 1.30}
+
+// Gradient is the "Wall Street ticker" marquee: each column gets a
+// brightness level and the pattern shifts by Speed columns per frame.
+//
+// The levels are stretched over one period, interpolating between entries,
+// so a character of any width gets a full dark-to-bright sweep. The period
+// defaults to the frame size along the sweep direction.
+//
+// In the "rgb" space the levels multiply each channel. In "hsl" and "oklch"
+// they are normalized to a position between MinLightness and MaxLightness:
+// cells in the base color get exactly that lightness, and other cells
+// (eyes, accents) keep their lightness offset from the base. Hue and
+// chroma are untouched, so saturated colors don't clip.
+//
+// Parameters: direction ("rtl", "ltr", "down", "up", "diagonal", "radial"),
+// speed (cells per frame, default 1), levels (default DefaultLevels),
+// period (cells per cycle), space ("rgb", "hsl", "oklch", default "rgb"),
+// min_lightness and max_lightness (default 0.3 and 0.9).
+type Gradient struct {
+	Levels       []float64
+	Direction    string
+	Speed        int
+	Period       int // Cells per cycle; 0 = frame size along the direction
+	Space        string
+	MinLightness float64
+	MaxLightness float64
+}
+
+func newGradient(spec domain.EffectSpec) (Effect, error) {
+	g := &Gradient{
+		Levels:       spec.Floats("levels", DefaultLevels),
+		Direction:    spec.String("direction", DirectionRTL),
+		Speed:        spec.Int("speed", 1),
+		Period:       spec.Int("period", 0),
+		Space:        spec.String("space", SpaceRGB),
+		MinLightness: spec.Float("min_lightness", 0.3),
+		MaxLightness: spec.Float("max_lightness", 0.9),
+	}
+	switch g.Direction {
+	case DirectionRTL, DirectionLTR, DirectionDown, DirectionUp, DirectionDiagonal, DirectionRadial:
+	default:
+		return nil, fmt.Errorf("unknown direction %q", g.Direction)
+	}
+	switch g.Space {
+	case SpaceRGB, SpaceHSL, SpaceOKLCH:
+	default:
+		return nil, fmt.Errorf("unknown color space %q (want %q, %q or %q)", g.Space, SpaceRGB, SpaceHSL, SpaceOKLCH)
+	}
+	if len(g.Levels) == 0 {
+		return nil, fmt.Errorf("levels must not be empty")
+	}
+	if g.MinLightness < 0 || g.MaxLightness > 1 || g.MinLightness > g.MaxLightness {
+		return nil, fmt.Errorf("lightness range [%g, %g] must lie within [0, 1]", g.MinLightness, g.MaxLightness)
+	}
+	return g, nil
+}
+
+// Apply implements Effect
+func (g *Gradient) Apply(grid Grid, ctx Context) {
+	width, height := grid.Width(), len(grid)
+	period := float64(g.Period)
+	if period < 1 {
+		period = g.extent(width, height)
+	}
+	shift := float64(ctx.Frame * g.Speed)
+	var baseL float64
+	if g.perceptual() {
+		baseL = g.lightness(ctx.Base)
+	}
+
+	for r, row := range grid {
+		for c := range row {
+			pos := fmod(g.position(c, r, width, height)+shift, period)
+			level := g.level(pos, period)
+			if !g.perceptual() {
+				scaleCell(&row[c], level)
+				continue
+			}
+			target := g.MinLightness + (g.MaxLightness-g.MinLightness)*g.normalize(level)
+			row[c].FG = g.relight(row[c].FG, target, baseL)
+			if row[c].HasBG {
+				row[c].BG = g.relight(row[c].BG, target, baseL)
+			}
+		}
+	}
+}
+
+// perceptual reports whether the gradient sweeps lightness instead of
+// scaling channels. An empty Space means "rgb".
+func (g *Gradient) perceptual() bool {
+	return g.Space == SpaceHSL || g.Space == SpaceOKLCH
+}
+
+// position returns a cell's coordinate along the sweep, increasing towards
+// the cell lit first. Adding the frame shift moves the bright band in the
+// named direction.
+func (g *Gradient) position(col, row, width, height int) float64 {
+	switch g.Direction {
+	case DirectionLTR:
+		return float64(width - 1 - col)
+	case DirectionDown:
+		return float64(height - 1 - row)
+	case DirectionUp:
+		return float64(row)
+	case DirectionDiagonal:
+		return float64(col + row)
+	case DirectionRadial:
+		// Terminal cells are about twice as tall as wide
+		dx := float64(col) - float64(width-1)/2
+		dy := (float64(row) - float64(height-1)/2) * 2
+		return g.extent(width, height) - 1 - math.Hypot(dx, dy)
+	default:
+		return float64(col)
+	}
+}
+
+// extent is the default period: the frame size along the direction
+func (g *Gradient) extent(width, height int) float64 {
+	switch g.Direction {
+	case DirectionDown, DirectionUp:
+		return float64(height)
+	case DirectionDiagonal:
+		return float64(width + height - 1)
+	case DirectionRadial:
+		return math.Ceil(math.Hypot(float64(width-1)/2, float64(height-1))) + 1
+	default:
+		return float64(width)
+	}
+}
+
+// level returns the brightness at position p of a cycle, interpolating
+// linearly between adjacent levels
+func (g *Gradient) level(p, period float64) float64 {
+	last := len(g.Levels) - 1
+	if last == 0 || period < 2 {
+		return g.Levels[last]
+	}
+	x := p / (period - 1) * float64(last)
+	i := int(x)
+	if i >= last {
+		return g.Levels[last]
+	}
+	return g.Levels[i] + (g.Levels[i+1]-g.Levels[i])*(x-float64(i))
+}
+
+// normalize maps a level onto [0, 1] relative to the lowest and highest levels
+func (g *Gradient) normalize(level float64) float64 {
+	lo, hi := g.Levels[0], g.Levels[0]
+	for _, l := range g.Levels {
+		lo, hi = math.Min(lo, l), math.Max(hi, l)
+	}
+	if hi == lo {
+		return 1
+	}
+	return (level - lo) / (hi - lo)
+}
+
+// lightness returns c's lightness in the gradient's color space
+func (g *Gradient) lightness(c termcolor.RGB) float64 {
+	if g.Space == SpaceHSL {
+		_, _, l := c.HSL()
+		return l
+	}
+	l, _, _ := c.OKLCH()
+	return l
+}
+
+// relight moves c to the target lightness, keeping its offset from the
+// base lightness along with its hue and saturation/chroma
+func (g *Gradient) relight(c termcolor.RGB, target, baseL float64) termcolor.RGB {
+	if g.Space == SpaceHSL {
+		h, s, l := c.HSL()
+		return termcolor.FromHSL(h, s, target+l-baseL)
+	}
+	l, chroma, h := c.OKLCH()
+	return termcolor.FromOKLCH(target+l-baseL, chroma, h)
+}
+
+// fmod is the always-positive floating point remainder
+func fmod(a, n float64) float64 {
+	if n <= 0 {
+		return 0
+	}
+	return math.Mod(math.Mod(a, n)+n, n)
+}
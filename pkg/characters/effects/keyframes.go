@@ -0,0 +1,212 @@
+package effects
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// BaseColor is the keyframe color that leaves cells in their own color
+const BaseColor = "base"
+
+// Keyframe timeline units. Every keyframe of an effect uses the same one.
+const (
+	UnitLoop  = "at"    // Fraction of the state's loop, 0 to 1
+	UnitFrame = "frame" // Frame index within the loop
+	UnitMS    = "ms"    // Milliseconds since the state started
+)
+
+// Easing curves for the transition out of a keyframe
+var easings = map[string]func(t float64) float64{
+	"linear":      func(t float64) float64 { return t },
+	"ease-in":     func(t float64) float64 { return t * t },
+	"ease-out":    func(t float64) float64 { return 1 - (1-t)*(1-t) },
+	"ease-in-out": func(t float64) float64 { return t * t * (3 - 2*t) },
+	"step":        func(t float64) float64 { return 0 },
+}
+
+// Keyframe tints the frame towards Color by Mix at position At.
+type Keyframe struct {
+	At    float64       // Position on the timeline, in the effect's unit
+	Color termcolor.RGB // Tint color
+	Mix   float64       // 0 keeps the cell's color, 1 replaces it
+	Ease  string        // Curve of the transition to the next keyframe
+}
+
+// Keyframes animates color over a state's timeline: cells are blended
+// towards the tint interpolated between the surrounding keyframes.
+//
+//	{"type": "keyframes", "keyframes": [
+//	  {"at": 0, "color": "#000000"},
+//	  {"at": 1, "color": "base", "ease": "ease-out"}
+//	], "loop": false}
+//
+// Each keyframe sets its position with exactly one of "at" (fraction of
+// the loop), "frame" (frame index in the loop) or "ms" (milliseconds since
+// the state started), a "color" (hex or "base" for the cell's own color),
+// an optional "mix" (default 1, or 0 for "base") and an optional "ease"
+// ("linear", "ease-in", "ease-out", "ease-in-out", "step").
+//
+// Parameters: keyframes, loop (repeat the timeline, default true),
+// duration_ms (length of an "ms" timeline, default the last keyframe).
+type Keyframes struct {
+	Frames   []Keyframe
+	Unit     string
+	Loop     bool
+	Duration float64 // Timeline length for UnitMS; 0 = last keyframe
+}
+
+func newKeyframes(spec domain.EffectSpec) (Effect, error) {
+	raw, ok := spec.Params["keyframes"].([]any)
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("keyframes must be a non-empty list")
+	}
+
+	k := &Keyframes{Loop: true, Duration: spec.Float("duration_ms", 0)}
+	if loop, ok := spec.Params["loop"].(bool); ok {
+		k.Loop = loop
+	}
+
+	for i, item := range raw {
+		obj, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("keyframe %d: must be an object", i)
+		}
+		kf, unit, err := parseKeyframe(domain.EffectSpec{Params: obj})
+		if err != nil {
+			return nil, fmt.Errorf("keyframe %d: %w", i, err)
+		}
+		if k.Unit != "" && unit != k.Unit {
+			return nil, fmt.Errorf("keyframe %d: uses %q but earlier keyframes use %q", i, unit, k.Unit)
+		}
+		k.Unit = unit
+		k.Frames = append(k.Frames, kf)
+	}
+
+	sort.SliceStable(k.Frames, func(i, j int) bool { return k.Frames[i].At < k.Frames[j].At })
+	return k, nil
+}
+
+// parseKeyframe reads one keyframe object, returning its timeline unit
+func parseKeyframe(obj domain.EffectSpec) (Keyframe, string, error) {
+	var kf Keyframe
+	unit := ""
+	for _, u := range []string{UnitLoop, UnitFrame, UnitMS} {
+		if _, ok := obj.Params[u]; !ok {
+			continue
+		}
+		if unit != "" {
+			return kf, "", fmt.Errorf("sets both %q and %q", unit, u)
+		}
+		unit = u
+		kf.At = obj.Float(u, math.NaN())
+		if math.IsNaN(kf.At) || kf.At < 0 {
+			return kf, "", fmt.Errorf("%q must be a non-negative number", u)
+		}
+	}
+	if unit == "" {
+		return kf, "", fmt.Errorf("needs one of %q, %q or %q", UnitLoop, UnitFrame, UnitMS)
+	}
+	if unit == UnitLoop && kf.At > 1 {
+		return kf, "", fmt.Errorf("%q must be between 0 and 1, got %g", UnitLoop, kf.At)
+	}
+
+	color := obj.String("color", BaseColor)
+	if color == BaseColor {
+		kf.Mix = obj.Float("mix", 0)
+	} else {
+		rgb, err := termcolor.ParseHex(color)
+		if err != nil {
+			return kf, "", err
+		}
+		kf.Color = rgb
+		kf.Mix = obj.Float("mix", 1)
+	}
+	if kf.Mix < 0 || kf.Mix > 1 {
+		return kf, "", fmt.Errorf("mix must be between 0 and 1, got %g", kf.Mix)
+	}
+
+	kf.Ease = obj.String("ease", "linear")
+	if _, ok := easings[kf.Ease]; !ok {
+		return kf, "", fmt.Errorf("unknown ease %q", kf.Ease)
+	}
+	return kf, unit, nil
+}
+
+// Apply implements Effect
+func (k *Keyframes) Apply(grid Grid, ctx Context) {
+	color, mix := k.tint(k.position(ctx))
+	if mix == 0 {
+		return
+	}
+	for _, row := range grid {
+		for col := range row {
+			row[col].FG = row[col].FG.Lerp(color, mix)
+			if row[col].HasBG {
+				row[col].BG = row[col].BG.Lerp(color, mix)
+			}
+		}
+	}
+}
+
+// position returns where on the timeline ctx falls, in the effect's unit
+func (k *Keyframes) position(ctx Context) float64 {
+	frames := max(ctx.Frames, 1)
+	switch k.Unit {
+	case UnitMS:
+		ms := float64(ctx.Elapsed.Milliseconds())
+		length := k.Duration
+		if length <= 0 {
+			length = k.Frames[len(k.Frames)-1].At
+		}
+		if k.Loop && length > 0 {
+			return math.Mod(ms, length)
+		}
+		return ms
+	case UnitFrame:
+		if k.Loop {
+			return float64(mod(ctx.Frame, frames))
+		}
+		return float64(ctx.Frame)
+	default:
+		// The last frame of a loop sits at 1
+		frame := ctx.Frame
+		if k.Loop {
+			frame = mod(frame, frames)
+		}
+		if frames == 1 {
+			return 1
+		}
+		return math.Min(float64(frame)/float64(frames-1), 1)
+	}
+}
+
+// tint returns the color and mix at position p. Before the first and after
+// the last keyframe the nearest one holds.
+func (k *Keyframes) tint(p float64) (termcolor.RGB, float64) {
+	first, last := k.Frames[0], k.Frames[len(k.Frames)-1]
+	if p <= first.At {
+		return first.Color, first.Mix
+	}
+	if p >= last.At {
+		return last.Color, last.Mix
+	}
+
+	i := sort.Search(len(k.Frames), func(i int) bool { return k.Frames[i].At > p }) - 1
+	from, to := k.Frames[i], k.Frames[i+1]
+	t := easings[from.Ease]((p - from.At) / (to.At - from.At))
+
+	// A "base" keyframe has no color of its own; borrow its neighbor's so
+	// fading to or from base only changes the mix
+	fromColor, toColor := from.Color, to.Color
+	if from.Mix == 0 {
+		fromColor = toColor
+	}
+	if to.Mix == 0 {
+		toColor = fromColor
+	}
+	return fromColor.Lerp(toColor, t), from.Mix + (to.Mix-from.Mix)*t
+}
@@ -2,6 +2,9 @@ package characters
 
 import (
 	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
 )
 
 func TestFrameCache(t *testing.T) {
@@ -141,3 +144,106 @@ func TestBackwardCompatibility(t *testing.T) {
 		t.Errorf("expected character name 'sam', got '%s'", char.Name)
 	}
 }
+
+func TestFrameCacheGlyphSet(t *testing.T) {
+	agent, err := LibraryAgent("sam")
+	if err != nil {
+		t.Fatalf("failed to load library agent: %v", err)
+	}
+
+	unicodeCache := agent.GetFrameCache()
+	if unicodeCache.GetGlyphSet() != "unicode" {
+		t.Errorf("default glyph set = %q, want unicode", unicodeCache.GetGlyphSet())
+	}
+
+	if err := agent.SetGlyphSet("ascii"); err != nil {
+		t.Fatalf("SetGlyphSet(ascii) error = %v", err)
+	}
+
+	asciiCache := agent.GetFrameCache()
+	if asciiCache == unicodeCache {
+		t.Fatal("SetGlyphSet should invalidate the frame cache")
+	}
+	if asciiCache.GetGlyphSet() != "ascii" {
+		t.Errorf("glyph set = %q, want ascii", asciiCache.GetGlyphSet())
+	}
+
+	for _, stateName := range asciiCache.ListStates() {
+		for frameIdx, frame := range asciiCache.GetStateFrames(stateName) {
+			for _, line := range frame {
+				for _, r := range stripEscapes(line) {
+					if r > 127 && r != '◌' {
+						t.Fatalf("state %q frame %d has non-ASCII rune %q", stateName, frameIdx, r)
+					}
+				}
+			}
+		}
+	}
+
+	if err := agent.SetGlyphSet("nonexistent"); err == nil {
+		t.Error("SetGlyphSet(nonexistent) should fail")
+	}
+	if agent.GlyphSet() != "ascii" {
+		t.Errorf("failed SetGlyphSet changed glyph set to %q", agent.GlyphSet())
+	}
+}
+
+// stripEscapes removes SGR sequences so tests can inspect glyphs
+func stripEscapes(s string) string {
+	var out []rune
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\x1b':
+			inEscape = true
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		default:
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
+
+func TestFrameCacheNoise(t *testing.T) {
+	char := &domain.Character{
+		Name:      "noisy",
+		Color:     "#FFFFFF",
+		Width:     4,
+		Height:    1,
+		BaseFrame: domain.Frame{Name: "base", Lines: []string{"FFFF"}},
+		States: map[string]domain.State{
+			"think": {Name: "think", Frames: []domain.Frame{{Lines: []string{"F$$F"}}}, NoisePool: "..", NoiseRate: 3},
+			"plain": {Name: "plain", Frames: []domain.Frame{{Lines: []string{"F$$F"}}}},
+		},
+	}
+	agent := NewAgentCharacter(char)
+	cache := agent.GetFrameCache()
+
+	if got := cache.GetNoisePool("think"); string(got) != "░░" {
+		t.Errorf("think noise pool = %q, want %q", string(got), "░░")
+	}
+	if got := cache.GetNoiseRate("think"); got != 3 {
+		t.Errorf("think noise rate = %d, want 3", got)
+	}
+	if got := cache.GetNoiseRate("plain"); got != patterns.DefaultNoiseRate {
+		t.Errorf("plain noise rate = %d, want default %d", got, patterns.DefaultNoiseRate)
+	}
+
+	lines := cache.ApplyNoise(cache.GetStateFrames("think")[0], "think", patterns.NewNoiseSource(1))
+	if got := stripEscapes(lines[0]); got != "█░░█" {
+		t.Errorf("think with noise = %q, want %q", got, "█░░█")
+	}
+
+	// Pools follow the glyph set
+	if err := agent.SetGlyphSet("ascii"); err != nil {
+		t.Fatalf("SetGlyphSet(ascii) error = %v", err)
+	}
+	for _, r := range agent.GetFrameCache().GetNoisePool("plain") {
+		if r > 127 {
+			t.Errorf("ascii default noise pool has non-ASCII rune %q", r)
+		}
+	}
+}
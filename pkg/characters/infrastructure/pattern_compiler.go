@@ -10,6 +10,8 @@ import (
 // SimplePatternCompiler implements the PatternCompiler interface
 type SimplePatternCompiler struct {
 	patterns   map[rune]rune
+	glyphs     map[rune]rune // default glyph -> glyph set rune (non-default sets only)
+	codes      map[rune]rune // glyph -> pattern code, for Decompile
 	validators []PatternValidator
 }
 
@@ -20,8 +22,25 @@ type PatternValidator interface {
 
 // NewPatternCompiler creates a new pattern compiler
 func NewPatternCompiler() domain.PatternCompiler {
-	codes := patterns.DefaultPatternCodes()
-	return &SimplePatternCompiler{
+	return NewPatternCompilerWithCodes(patterns.DefaultPatternCodes())
+}
+
+// NewGlyphSetCompiler creates a pattern compiler for a named glyph set
+// (see patterns.ListGlyphSets).
+func NewGlyphSetCompiler(glyphSet string) (domain.PatternCompiler, error) {
+	codes, err := patterns.GetGlyphSet(glyphSet)
+	if err != nil {
+		return nil, err
+	}
+	return NewPatternCompilerWithCodes(codes), nil
+}
+
+// NewPatternCompilerWithCodes creates a pattern compiler that emits the given
+// runes. For non-default codes, the default Unicode block elements are also
+// translated, so frames that were already compiled can be re-rendered with
+// a different glyph set.
+func NewPatternCompilerWithCodes(codes patterns.PatternCodes) domain.PatternCompiler {
+	compiler := &SimplePatternCompiler{
 		patterns: map[rune]rune{
 			// Basic blocks (uppercase)
 			'F': codes.FullBlock,  // Full Block
@@ -64,11 +83,32 @@ func NewPatternCompiler() domain.PatternCompiler {
 			// Dynamic noise (replaced at render time)
 			'$': codes.Noise, // Noise placeholder
 		},
+		glyphs: make(map[rune]rune),
 		validators: []PatternValidator{
 			&LengthValidator{},
 			&CharacterValidator{},
 		},
 	}
+
+	// Translate already-compiled default glyphs (e.g. █ -> #)
+	defaults := patterns.DefaultPatternCodes().Mapping()
+	for code, glyph := range codes.Mapping() {
+		if def := defaults[code]; def != glyph {
+			compiler.glyphs[def] = glyph
+		}
+	}
+
+	// Map glyphs back to codes, this set's glyphs first
+	compiler.codes = make(map[rune]rune)
+	for _, mapping := range []map[rune]rune{compiler.patterns, defaults} {
+		for _, code := range decompileOrder {
+			if _, taken := compiler.codes[mapping[code]]; !taken {
+				compiler.codes[mapping[code]] = code
+			}
+		}
+	}
+
+	return compiler
 }
 
 // Compile compiles a pattern string to Unicode block elements
@@ -77,6 +117,8 @@ func (c *SimplePatternCompiler) Compile(pattern string) string {
 	for _, char := range pattern {
 		if unicode, exists := c.patterns[char]; exists {
 			result = append(result, unicode)
+		} else if glyph, exists := c.glyphs[char]; exists {
+			result = append(result, glyph)
 		} else {
 			result = append(result, char)
 		}
@@ -84,6 +126,30 @@ func (c *SimplePatternCompiler) Compile(pattern string) string {
 	return string(result)
 }
 
+// decompileOrder is the code preferred when several codes share a glyph
+const decompileOrder = "FTBLR12345678.:#\\/_$"
+
+// Decompile converts a line of rendered block art back to pattern codes.
+// Glyphs of the compiler's glyph set and default Unicode block elements
+// are recognized, and spaces become "_". Runes with no pattern code are
+// kept as they are and reported in a *domain.DecompileError.
+func (c *SimplePatternCompiler) Decompile(line string) (string, error) {
+	result := make([]rune, 0, len(line))
+	var unmapped []domain.UnmappedRune
+	for i, r := range []rune(line) {
+		code, ok := c.codes[r]
+		if !ok {
+			unmapped = append(unmapped, domain.UnmappedRune{Position: i, Rune: r})
+			code = r
+		}
+		result = append(result, code)
+	}
+	if len(unmapped) > 0 {
+		return string(result), &domain.DecompileError{Line: line, Unmapped: unmapped}
+	}
+	return string(result), nil
+}
+
 // Validate validates a pattern string with detailed error reporting
 func (c *SimplePatternCompiler) Validate(pattern string) error {
 	if pattern == "" {
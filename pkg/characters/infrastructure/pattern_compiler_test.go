@@ -1,8 +1,12 @@
 package infrastructure
 
 import (
+	"errors"
+	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
 )
 
 func TestPatternCompiler(t *testing.T) {
@@ -109,3 +113,106 @@ func TestCharacterValidator(t *testing.T) {
 		})
 	}
 }
+
+func TestGlyphSetCompiler(t *testing.T) {
+	tests := []struct {
+		glyphSet string
+		input    string
+		expected string
+	}{
+		{"unicode", "_26FFFFF51_", " ▝▜█████▛▘ "},
+		{"ascii", "_26FFFFF51_", " '#######` "},
+		{"safe", "_26FFFFF51_", " ▀▀█████▀▀ "},
+		{"braille", "R5FFF6L", "⢸⡟⣿⣿⣿⢻⡇"},
+		// Already-compiled default glyphs are translated too
+		{"ascii", " ▝▜█████▛▘ ", " '#######` "},
+		{"safe", "▐▛███▜▌", "▐▀███▀▌"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.glyphSet+"/"+test.input, func(t *testing.T) {
+			compiler, err := NewGlyphSetCompiler(test.glyphSet)
+			if err != nil {
+				t.Fatalf("NewGlyphSetCompiler(%q) error = %v", test.glyphSet, err)
+			}
+			if result := compiler.Compile(test.input); result != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, result)
+			}
+		})
+	}
+
+	if _, err := NewGlyphSetCompiler("unknown"); err == nil {
+		t.Error("NewGlyphSetCompiler(unknown) should fail")
+	}
+}
+
+func TestDecompile(t *testing.T) {
+	compiler := NewPatternCompiler().(*SimplePatternCompiler)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"Blocks", "█▐▌▀▄", "FRLTB"},
+		{"Quadrants", "▘▝▖▗▛▜▙▟", "12345678"},
+		{"Shades and diagonals", "░▒▓▚▞", ".:#\\/"},
+		{"Spaces", " █ ", "_F_"},
+		{"Noise placeholder", "◌", "$"},
+		{"Empty string", "", ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := compiler.Decompile(test.input)
+			if err != nil {
+				t.Fatalf("Decompile(%q) error = %v", test.input, err)
+			}
+			if result != test.expected {
+				t.Errorf("expected %q, got %q", test.expected, result)
+			}
+			if test.input != "" && compiler.Compile(result) != test.input {
+				t.Errorf("Compile(%q) = %q, want the original", result, compiler.Compile(result))
+			}
+		})
+	}
+}
+
+func TestDecompileUnmapped(t *testing.T) {
+	compiler := NewPatternCompiler().(*SimplePatternCompiler)
+
+	result, err := compiler.Decompile("█x▐▔█")
+	if result != "FxR▔F" {
+		t.Errorf("expected %q, got %q", "FxR▔F", result)
+	}
+	var decompileErr *domain.DecompileError
+	if !errors.As(err, &decompileErr) {
+		t.Fatalf("expected DecompileError, got %v", err)
+	}
+	want := []domain.UnmappedRune{{Position: 1, Rune: 'x'}, {Position: 3, Rune: '▔'}}
+	if !reflect.DeepEqual(decompileErr.Unmapped, want) {
+		t.Errorf("unmapped = %+v, want %+v", decompileErr.Unmapped, want)
+	}
+	if !strings.Contains(err.Error(), "'▔' at 3") {
+		t.Errorf("error %q should name the rune and its position", err)
+	}
+}
+
+func TestDecompileGlyphSet(t *testing.T) {
+	compiler, err := NewGlyphSetCompiler("ascii")
+	if err != nil {
+		t.Fatal(err)
+	}
+	decompiler := compiler.(domain.PatternDecompiler)
+
+	// Both the set's glyphs and Unicode block art decompile
+	for _, line := range []string{compiler.Compile("FRT_L"), "█▐▀ ▌"} {
+		result, err := decompiler.Decompile(line)
+		if err != nil {
+			t.Fatalf("Decompile(%q) error = %v", line, err)
+		}
+		if compiler.Compile(result) != compiler.Compile("FRT_L") {
+			t.Errorf("Decompile(%q) = %q, which renders differently", line, result)
+		}
+	}
+}
@@ -81,3 +81,29 @@ const (
 	Theme4ColorDa = "#B592D4" // Soft violet - creative depth
 	Theme4ColorNi = "#DE99B8" // Blush - approachable
 )
+
+// Palette slots shared by every character in a theme. The body slot is the
+// character's theme color; eyes and accent complete the (body, eyes, accent)
+// palette addressed by frame color layers.
+const (
+	Theme1Eyes   = "#FFFFFF" // White - reads on every saturated body
+	Theme1Accent = "#00FFFF" // Cyan - sparks and highlights
+
+	Theme2Eyes   = "#303446" // Frappe base - soft dark pupils
+	Theme2Accent = "#F2D5CF" // Rosewater - gentle highlight
+
+	Theme3Eyes   = "#2E3A2F" // Deep forest - grounded pupils
+	Theme3Accent = "#E8DCC4" // Parchment - warm highlight
+
+	Theme4Eyes   = "#2B2D3A" // Ink - crisp pupils
+	Theme4Accent = "#F5E6C8" // Cream - soft highlight
+)
+
+// Background affinity of each theme: the terminal background ("light" or
+// "dark") its colors are designed for. AutoTheme prefers matching themes.
+const (
+	Theme1Affinity = "dark" // Saturated neons glow on black
+	Theme2Affinity = "dark" // Pastels, too light for white backgrounds
+	Theme3Affinity = "dark" // Earth tones on a dark terminal
+	Theme4Affinity = "dark" // Warm pastels that need a dark window
+)
@@ -0,0 +1,257 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+)
+
+// shadeCoverage is the share of each quadrant a shade code covers
+var shadeCoverage = map[rune]float64{'.': 0.25, ':': 0.5, '#': 0.75}
+
+// cellCoverage returns the coverage of each quadrant (UL, UR, LL, LR) of a
+// pattern code, and whether it is noise
+func cellCoverage(code rune) (cover [4]float64, noise bool, ok bool) {
+	if code == '$' {
+		return [4]float64{1, 1, 1, 1}, true, true
+	}
+	if shade, isShade := shadeCoverage[code]; isShade {
+		return [4]float64{shade, shade, shade, shade}, false, true
+	}
+	if mask, isBlock := patterns.QuadrantMask(code); isBlock {
+		for q := 0; q < 4; q++ {
+			if mask&(1<<q) != 0 {
+				cover[q] = 1
+			}
+		}
+		return cover, false, true
+	}
+	return cover, false, false
+}
+
+// coverageGrid is a frame as a grid of quadrant "pixels", two per cell
+// in each direction
+type coverageGrid struct {
+	width, height int // In pixels
+	cover         []float64
+	noise         []bool
+}
+
+func (g *coverageGrid) at(x, y int) (float64, bool) {
+	return g.cover[y*g.width+x], g.noise[y*g.width+x]
+}
+
+// DeriveMicro proposes a micro variant of a regular character by
+// downsampling its frames. Padding columns empty in every frame are
+// trimmed, the rest is area-sampled onto the micro grid at quadrant
+// resolution, and the result is returned as a micro definition for
+// designers to refine.
+func DeriveMicro(char LibraryCharacter) (*microstateregistry.MicroDefinition, error) {
+	variant := sizeVariants[SizeMicro]
+	return DeriveSize(char, variant.Width, variant.Height)
+}
+
+// DeriveSize downsamples the frames of a character to width x height
+// cells; see DeriveMicro.
+func DeriveSize(char LibraryCharacter, width, height int) (*microstateregistry.MicroDefinition, error) {
+	if width <= 0 || height <= 0 {
+		return nil, fmt.Errorf("invalid size %dx%d", width, height)
+	}
+	if len(char.Patterns) == 0 {
+		return nil, fmt.Errorf("character %q has no frames", char.Name)
+	}
+
+	first, last, err := contentColumns(char.Patterns)
+	if err != nil {
+		return nil, fmt.Errorf("character %q: %w", char.Name, err)
+	}
+
+	def := &microstateregistry.MicroDefinition{
+		Name:   char.Name,
+		Width:  width,
+		Height: height,
+	}
+
+	// Group frames by state in frame order
+	states := make(map[string]*microstateregistry.MicroState)
+	var order []string
+	for _, frame := range char.Patterns {
+		derived := downsampleFrame(frame, first, last, width, height)
+		if frame.Name == "base" {
+			def.BaseFrame = derived
+			continue
+		}
+		name := frame.Name
+		if i := strings.LastIndex(name, "_"); i > 0 {
+			name = name[:i]
+		}
+		state, ok := states[name]
+		if !ok {
+			info := char.States[name]
+			state = &microstateregistry.MicroState{
+				Name:      name,
+				FPS:       info.FPS,
+				NoisePool: info.NoisePool,
+				NoiseRate: info.NoiseRate,
+				Effects:   info.Effects,
+			}
+			states[name] = state
+			order = append(order, name)
+		}
+		state.Frames = append(state.Frames, derived)
+	}
+
+	// Without a base frame, use the first frame like the agent does
+	if len(def.BaseFrame.Lines) == 0 {
+		def.BaseFrame = downsampleFrame(char.Patterns[0], first, last, width, height)
+	}
+	def.BaseFrame.Name = "base"
+
+	sort.Strings(order)
+	for _, name := range order {
+		def.States = append(def.States, *states[name])
+	}
+	return def, nil
+}
+
+// contentColumns returns the first and last cell columns that are filled
+// in any frame, checking every code is known
+func contentColumns(frames []Frame) (first, last int, err error) {
+	first, last = -1, -1
+	for _, frame := range frames {
+		for y, line := range frame.Lines {
+			for x, code := range []rune(line) {
+				cover, _, ok := cellCoverage(code)
+				if !ok {
+					return 0, 0, fmt.Errorf("frame %s line %d column %d: unknown pattern code %q", frame.Name, y+1, x+1, code)
+				}
+				if cover == [4]float64{} {
+					continue
+				}
+				if first < 0 || x < first {
+					first = x
+				}
+				if x > last {
+					last = x
+				}
+			}
+		}
+	}
+	if first < 0 {
+		// Nothing drawn; keep the full width
+		first, last = 0, 0
+		for _, frame := range frames {
+			for _, line := range frame.Lines {
+				if n := len([]rune(line)) - 1; n > last {
+					last = n
+				}
+			}
+		}
+	}
+	return first, last, nil
+}
+
+// newCoverageGrid converts cell columns first..last of a frame to pixels
+func newCoverageGrid(lines []string, first, last int) *coverageGrid {
+	g := &coverageGrid{width: 2 * (last - first + 1), height: 2 * len(lines)}
+	g.cover = make([]float64, g.width*g.height)
+	g.noise = make([]bool, g.width*g.height)
+	for y, line := range lines {
+		runes := []rune(line)
+		for x := first; x <= last && x < len(runes); x++ {
+			cover, noise, _ := cellCoverage(runes[x])
+			for q := 0; q < 4; q++ {
+				px, py := 2*(x-first)+q%2, 2*y+q/2
+				g.cover[py*g.width+px] = cover[q]
+				g.noise[py*g.width+px] = noise
+			}
+		}
+	}
+	return g
+}
+
+// downsampleFrame area-samples columns first..last of a frame onto a
+// width x height cell frame
+func downsampleFrame(frame Frame, first, last, width, height int) microstateregistry.MicroFrame {
+	src := newCoverageGrid(frame.Lines, first, last)
+	scaleX := float64(src.width) / float64(2*width)
+	scaleY := float64(src.height) / float64(2*height)
+
+	lines := make([]string, height)
+	var fg, bg []string
+	if len(frame.FG) > 0 {
+		fg = make([]string, height)
+	}
+	if len(frame.BG) > 0 {
+		bg = make([]string, height)
+	}
+
+	for cy := 0; cy < height; cy++ {
+		var line, fgLine, bgLine []rune
+		for cx := 0; cx < width; cx++ {
+			mask := 0
+			var noise, solid, bestWeight float64
+			bestX, bestY := -1, -1
+
+			for q := 0; q < 4; q++ {
+				tx, ty := 2*cx+q%2, 2*cy+q/2
+				x0, x1 := float64(tx)*scaleX, float64(tx+1)*scaleX
+				y0, y1 := float64(ty)*scaleY, float64(ty+1)*scaleY
+
+				var covered float64
+				for py := int(y0); py < src.height && float64(py) < y1; py++ {
+					for px := int(x0); px < src.width && float64(px) < x1; px++ {
+						overlap := (min(x1, float64(px+1)) - max(x0, float64(px))) *
+							(min(y1, float64(py+1)) - max(y0, float64(py)))
+						value, isNoise := src.at(px, py)
+						weight := overlap * value
+						covered += weight
+						if isNoise {
+							noise += weight
+						} else {
+							solid += weight
+						}
+						if weight > bestWeight {
+							bestWeight, bestX, bestY = weight, px/2+first, py/2
+						}
+					}
+				}
+				if covered >= 0.5*scaleX*scaleY {
+					mask |= 1 << q
+				}
+			}
+
+			code := patterns.QuadrantCode(mask)
+			if mask != 0 && noise > solid {
+				code = '$'
+			}
+			line = append(line, code)
+			fgLine = append(fgLine, layerCell(frame.FG, bestX, bestY))
+			bgLine = append(bgLine, layerCell(frame.BG, bestX, bestY))
+		}
+		lines[cy] = string(line)
+		if fg != nil {
+			fg[cy] = string(fgLine)
+		}
+		if bg != nil {
+			bg[cy] = string(bgLine)
+		}
+	}
+
+	return microstateregistry.MicroFrame{Lines: lines, FG: fg, BG: bg}
+}
+
+// layerCell returns a color layer's rune at a cell, or "_" (default color)
+func layerCell(layer []string, x, y int) rune {
+	if x < 0 || y < 0 || y >= len(layer) {
+		return '_'
+	}
+	runes := []rune(layer[y])
+	if x >= len(runes) {
+		return '_'
+	}
+	return runes[x]
+}
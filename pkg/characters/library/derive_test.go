@@ -0,0 +1,171 @@
+package library
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
+)
+
+func TestDeriveSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		lines  []string
+		width  int
+		height int
+		want   []string
+	}{
+		{
+			// 2x2 cells halve exactly: each cell's quadrants come from one source cell
+			name:   "quadrants",
+			lines:  []string{"f_", "_f", "_f", "f_"},
+			width:  1,
+			height: 2,
+			want:   []string{"\\", "/"},
+		},
+		{
+			name:   "half blocks",
+			lines:  []string{"ffff", "____"},
+			width:  2,
+			height: 1,
+			want:   []string{"tt"},
+		},
+		{
+			// The empty columns are trimmed before scaling
+			name:   "padding trimmed",
+			lines:  []string{"__ff__", "__ff__"},
+			width:  1,
+			height: 1,
+			want:   []string{"f"},
+		},
+		{
+			name:   "uppercase and shades",
+			lines:  []string{"FT", "#."},
+			width:  1,
+			height: 1,
+			want:   []string{"5"},
+		},
+		{
+			name:   "noise",
+			lines:  []string{"$$", "$f"},
+			width:  1,
+			height: 1,
+			want:   []string{"$"},
+		},
+		{
+			name:   "empty",
+			lines:  []string{"____", "____"},
+			width:  2,
+			height: 1,
+			want:   []string{"__"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			char := LibraryCharacter{
+				Name:     "bolt",
+				Patterns: []Frame{{Name: "base", Lines: tt.lines}, {Name: "wait_1", Lines: tt.lines}},
+			}
+			def, err := DeriveSize(char, tt.width, tt.height)
+			if err != nil {
+				t.Fatalf("DeriveSize() error = %v", err)
+			}
+			if !reflect.DeepEqual(def.BaseFrame.Lines, tt.want) {
+				t.Errorf("DeriveSize() base = %q, want %q", def.BaseFrame.Lines, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveSizeStates(t *testing.T) {
+	char := LibraryCharacter{
+		Name: "bolt",
+		Patterns: []Frame{
+			{Name: "wait_1", Lines: []string{"ff", "ff"}, FG: []string{"12", "34"}},
+			{Name: "wait_2", Lines: []string{"__", "ff"}, FG: []string{"12", "34"}},
+			{Name: "deep_think_1", Lines: []string{"ff", "__"}},
+		},
+		States: map[string]StateInfo{"wait": {FPS: 3, NoiseRate: 7}},
+	}
+	def, err := DeriveSize(char, 1, 1)
+	if err != nil {
+		t.Fatalf("DeriveSize() error = %v", err)
+	}
+
+	// No base frame: the first frame stands in
+	if want := []string{"f"}; !reflect.DeepEqual(def.BaseFrame.Lines, want) || def.BaseFrame.Name != "base" {
+		t.Errorf("base = %+v, want %q", def.BaseFrame, want)
+	}
+	if len(def.States) != 2 || def.States[0].Name != "deep_think" || def.States[1].Name != "wait" {
+		t.Fatalf("states = %+v, want deep_think and wait", def.States)
+	}
+	wait := def.States[1]
+	if wait.FPS != 3 || wait.NoiseRate != 7 || len(wait.Frames) != 2 {
+		t.Errorf("wait = %+v, want 2 frames at 3 FPS", wait)
+	}
+	if got := wait.Frames[1].Lines; !reflect.DeepEqual(got, []string{"b"}) {
+		t.Errorf("wait frame 2 = %q, want [b]", got)
+	}
+	// The color of the most covering source cell is kept
+	if got := wait.Frames[1].FG; !reflect.DeepEqual(got, []string{"3"}) {
+		t.Errorf("wait frame 2 fg = %q, want [3]", got)
+	}
+	if def.States[0].Frames[0].FG != nil {
+		t.Error("frame without color layers gained one")
+	}
+}
+
+func TestDeriveSizeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		char LibraryCharacter
+		w, h int
+	}{
+		{"no frames", LibraryCharacter{Name: "bolt"}, 8, 2},
+		{"unknown code", LibraryCharacter{Name: "bolt", Patterns: []Frame{{Name: "base", Lines: []string{"fXf"}}}}, 8, 2},
+		{"bad size", LibraryCharacter{Name: "bolt", Patterns: []Frame{{Name: "base", Lines: []string{"f"}}}}, 0, 2},
+	}
+
+	for _, tt := range tests {
+		if _, err := DeriveSize(tt.char, tt.w, tt.h); err == nil {
+			t.Errorf("%s: DeriveSize() error = nil, want error", tt.name)
+		}
+	}
+}
+
+func TestDeriveMicro(t *testing.T) {
+	sam, err := Get("sam")
+	if err != nil {
+		t.Fatalf("Get(sam) error = %v", err)
+	}
+	def, err := DeriveMicro(sam)
+	if err != nil {
+		t.Fatalf("DeriveMicro(sam) error = %v", err)
+	}
+	if len(def.States) != len(sam.States) {
+		t.Errorf("%d states, want %d", len(def.States), len(sam.States))
+	}
+
+	// The proposal round-trips through the micro definition format
+	data, err := json.Marshal(def)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	parsed, err := microstateregistry.Parse(data)
+	if err != nil {
+		t.Fatalf("microstateregistry.Parse() error = %v", err)
+	}
+	frames := append([]microstateregistry.MicroFrame{parsed.BaseFrame}, parsed.States[0].Frames...)
+	for _, frame := range frames {
+		if len(frame.Lines) != 2 {
+			t.Fatalf("frame has %d lines, want 2", len(frame.Lines))
+		}
+		for _, line := range frame.Lines {
+			if len([]rune(line)) != 8 {
+				t.Errorf("line %q is not 8 wide", line)
+			}
+		}
+	}
+}
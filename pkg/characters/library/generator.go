@@ -3,6 +3,7 @@ package library
 import (
 	"fmt"
 	"sort"
+	"strings"
 
 	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
 	"github.com/wildreason/tangent/pkg/characters/stateregistry"
@@ -18,11 +19,15 @@ type CharacterMetadata struct {
 	Height      int
 }
 
-// GenerateFromRegistry creates a LibraryCharacter from state registry
+// GenerateFromRegistry creates a LibraryCharacter from state registry, with the
+// character's own state overrides
 func GenerateFromRegistry(metadata CharacterMetadata) LibraryCharacter {
-	// Get all states from registry
-	allStates := stateregistry.All()
+	return GenerateFromStates(metadata, stateregistry.ForCharacter(metadata.Name))
+}
 
+// GenerateFromStates creates a LibraryCharacter from a set of states. A
+// state named "base" becomes the single base frame.
+func GenerateFromStates(metadata CharacterMetadata, allStates map[string]stateregistry.StateDefinition) LibraryCharacter {
 	// Build frames from all states in alphabetical order
 	var frames []Frame
 	stateNames := make([]string, 0, len(allStates))
@@ -31,16 +36,37 @@ func GenerateFromRegistry(metadata CharacterMetadata) LibraryCharacter {
 	}
 	sort.Strings(stateNames)
 
+	if base, ok := allStates["base"]; ok && len(base.Frames) > 0 {
+		frames = append(frames, Frame{
+			Name:  "base",
+			Lines: base.Frames[0].Lines,
+			FG:    base.Frames[0].FG,
+			BG:    base.Frames[0].BG,
+		})
+	}
+
 	// Convert state registry frames to library frames
+	states := make(map[string]StateInfo, len(allStates))
 	for _, stateName := range stateNames {
+		if stateName == "base" {
+			continue
+		}
 		state := allStates[stateName]
 		for i, stateFrame := range state.Frames {
 			frame := Frame{
 				Name:  fmt.Sprintf("%s_%d", stateName, i+1),
 				Lines: stateFrame.Lines,
+				FG:    stateFrame.FG,
+				BG:    stateFrame.BG,
 			}
 			frames = append(frames, frame)
 		}
+		states[stateName] = StateInfo{
+			FPS:       state.FPS,
+			NoisePool: state.NoisePool,
+			NoiseRate: state.NoiseRate,
+			Effects:   state.Effects,
+		}
 	}
 
 	return LibraryCharacter{
@@ -51,12 +77,19 @@ func GenerateFromRegistry(metadata CharacterMetadata) LibraryCharacter {
 		Width:       metadata.Width,
 		Height:      metadata.Height,
 		Patterns:    frames,
+		States:      states,
 	}
 }
 
-// GenerateMicroFromRegistry creates a micro LibraryCharacter from the micro state registry
+// GenerateMicroFromRegistry creates a micro LibraryCharacter from the micro state registry,
+// using the character's own micro design where it has one
 func GenerateMicroFromRegistry(metadata CharacterMetadata) LibraryCharacter {
-	def := microstateregistry.Get()
+	name := strings.TrimSuffix(metadata.Name, sizeVariants[SizeMicro].Suffix)
+	return GenerateMicroFromDefinition(metadata, microstateregistry.ForCharacter(name))
+}
+
+// GenerateMicroFromDefinition creates a micro LibraryCharacter from a micro definition
+func GenerateMicroFromDefinition(metadata CharacterMetadata, def *microstateregistry.MicroDefinition) LibraryCharacter {
 	if def == nil {
 		return LibraryCharacter{}
 	}
@@ -68,17 +101,28 @@ func GenerateMicroFromRegistry(metadata CharacterMetadata) LibraryCharacter {
 	frames = append(frames, Frame{
 		Name:  "base",
 		Lines: def.BaseFrame.Lines,
+		FG:    def.BaseFrame.FG,
+		BG:    def.BaseFrame.BG,
 	})
 
 	// Convert micro state frames to library frames
+	states := make(map[string]StateInfo, len(def.States))
 	for _, state := range def.States {
 		for i, stateFrame := range state.Frames {
 			frame := Frame{
 				Name:  fmt.Sprintf("%s_%d", state.Name, i+1),
 				Lines: stateFrame.Lines,
+				FG:    stateFrame.FG,
+				BG:    stateFrame.BG,
 			}
 			frames = append(frames, frame)
 		}
+		states[state.Name] = StateInfo{
+			FPS:       state.FPS,
+			NoisePool: state.NoisePool,
+			NoiseRate: state.NoiseRate,
+			Effects:   state.Effects,
+		}
 	}
 
 	return LibraryCharacter{
@@ -89,5 +133,6 @@ func GenerateMicroFromRegistry(metadata CharacterMetadata) LibraryCharacter {
 		Width:       def.Width,
 		Height:      def.Height,
 		Patterns:    frames,
+		States:      states,
 	}
 }
@@ -34,6 +34,9 @@ type ThemeDef struct {
 	Number      int               // 1, 2, 3, 4
 	Description string            // Theme description
 	ColorConsts map[string]string // CharacterSa -> Theme1ColorSa
+	EyesConst   string            // Theme1Eyes (optional palette slot)
+	AccentConst string            // Theme1Accent (optional palette slot)
+	Affinity    string            // "light" or "dark" background (optional)
 }
 
 const characterTemplate = `// Code generated by generator_codegen.go; DO NOT EDIT.
@@ -59,15 +62,26 @@ package library
 // Theme initialization - register all themes
 func init() {
 {{- range .Themes}}
+{{- $theme := .}}
 	// Theme {{.Number}}: {{.Name | title}}
 	registerTheme(ThemeDefinition{
 		Name:        "{{.Name}}",
 		Description: "{{.Description}}",
+{{- if .Affinity}}
+		Affinity:    {{.Affinity | affinityConst}},
+{{- end}}
 		Colors: map[string]string{
 {{- range $charConst, $themeColorConst := .ColorConsts}}
 			{{$charConst}}: {{$themeColorConst}},
 {{- end}}
 		},
+{{- if and .EyesConst .AccentConst}}
+		Palettes: map[string][]string{
+{{- range $charConst, $themeColorConst := .ColorConsts}}
+			{{$charConst}}: { {{- $themeColorConst}}, {{$theme.EyesConst}}, {{$theme.AccentConst}}},
+{{- end}}
+		},
+{{- end}}
 	})
 {{end -}}
 }
@@ -127,6 +141,7 @@ func parseConstants(filename string) ([]CharacterDef, []ThemeDef, error) {
 	charNames := make(map[string]string)    // CharacterSa -> "sam"
 	charColors := make(map[string]string)   // ColorSa -> "#FF0000"
 	themeColors := make(map[string]string)  // Theme1ColorSa -> "#FF0000"
+	themeSlots := make(map[string]string)   // Theme1Eyes -> "#FFFFFF"
 	themeDescs := make(map[string]string)   // "bright" -> description
 	charComments := make(map[string]string) // CharacterSa -> "Shadja (Red)"
 
@@ -170,6 +185,8 @@ func parseConstants(filename string) ([]CharacterDef, []ThemeDef, error) {
 				charColors[name] = value
 			} else if strings.HasPrefix(name, "Theme") && strings.Contains(name, "Color") {
 				themeColors[name] = value
+			} else if themeSlotRe.MatchString(name) {
+				themeSlots[name] = value
 			}
 
 			// Extract theme descriptions from block comments
@@ -249,12 +266,26 @@ func parseConstants(filename string) ([]CharacterDef, []ThemeDef, error) {
 			theme.ColorConsts[constName] = themeColorConst
 		}
 
+		// Palette slots shared by all characters in the theme
+		eyesConst := fmt.Sprintf("Theme%dEyes", themeNum)
+		accentConst := fmt.Sprintf("Theme%dAccent", themeNum)
+		if _, ok := themeSlots[eyesConst]; ok {
+			theme.EyesConst = eyesConst
+		}
+		if _, ok := themeSlots[accentConst]; ok {
+			theme.AccentConst = accentConst
+		}
+		theme.Affinity = themeSlots[fmt.Sprintf("Theme%dAffinity", themeNum)]
+
 		themes = append(themes, theme)
 	}
 
 	return characters, themes, nil
 }
 
+// themeSlotRe matches palette slot constants such as Theme2Eyes
+var themeSlotRe = regexp.MustCompile(`^Theme\d+(Eyes|Accent|Affinity)$`)
+
 func extractStringValue(expr ast.Expr) string {
 	lit, ok := expr.(*ast.BasicLit)
 	if !ok || lit.Kind != token.STRING {
@@ -287,6 +318,9 @@ func validate(characters []CharacterDef, themes []ThemeDef) error {
 
 	// Validate themes have all characters
 	for _, theme := range themes {
+		if theme.Affinity != "" && theme.Affinity != "light" && theme.Affinity != "dark" {
+			return fmt.Errorf("theme %s has affinity %q, expected \"light\" or \"dark\"", theme.Name, theme.Affinity)
+		}
 		if len(theme.ColorConsts) != len(characters) {
 			return fmt.Errorf("theme %s has %d characters, expected %d",
 				theme.Name, len(theme.ColorConsts), len(characters))
@@ -316,6 +350,8 @@ func generateCharacterFile(char CharacterDef) error {
 func generateThemesFile(themes []ThemeDef) error {
 	funcMap := template.FuncMap{
 		"title": strings.Title,
+		// "dark" -> AffinityDark
+		"affinityConst": func(affinity string) string { return "Affinity" + strings.Title(affinity) },
 	}
 	tmpl := template.Must(template.New("themes").Funcs(funcMap).Parse(themesTemplate))
 
@@ -1,7 +1,12 @@
 package library
 
 import (
+	"reflect"
 	"testing"
+	"testing/fstest"
+
+	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
+	"github.com/wildreason/tangent/pkg/characters/stateregistry"
 )
 
 func TestGenerateFromRegistry(t *testing.T) {
@@ -86,3 +91,73 @@ func TestCharacterColors(t *testing.T) {
 		}
 	}
 }
+
+func TestMicroCharacterDesigns(t *testing.T) {
+	fsys := fstest.MapFS{
+		"characters/micro-design.json": {Data: []byte(`{"states": [{"name": "wait", "frames": [{"lines": ["rffffffl", "rf7ff7fl"]}, {"lines": ["rffffffl", "rff77ffl"]}]}]}`)},
+	}
+	if err := microstateregistry.LoadCharacters(fsys, "characters"); err != nil {
+		t.Fatalf("LoadCharacters() error = %v", err)
+	}
+	design := GenerateMicroFromRegistry(CharacterMetadata{Name: "micro-design-micro", Width: 8, Height: 2})
+	rio, err := GetMicro("rio")
+	if err != nil {
+		t.Fatalf("GetMicro(rio) error = %v", err)
+	}
+
+	frames := func(char LibraryCharacter) map[string][]string {
+		byName := make(map[string][]string, len(char.Patterns))
+		for _, frame := range char.Patterns {
+			byName[frame.Name] = frame.Lines
+		}
+		return byName
+	}
+	designFrames, rioFrames := frames(design), frames(rio)
+
+	// The design has its own wait state; everything else is shared
+	if want := []string{"rffffffl", "rff77ffl"}; !reflect.DeepEqual(designFrames["wait_2"], want) {
+		t.Errorf("wait_2 = %v, want the design's own %v", designFrames["wait_2"], want)
+	}
+	if reflect.DeepEqual(designFrames["wait_2"], rioFrames["wait_2"]) {
+		t.Errorf("rio wait_2 = %v, want the shared frame", rioFrames["wait_2"])
+	}
+	for _, name := range []string{"base", "resting_1", "read_2"} {
+		if !reflect.DeepEqual(designFrames[name], rioFrames[name]) {
+			t.Errorf("%s: design = %v, rio = %v, want the shared frame", name, designFrames[name], rioFrames[name])
+		}
+	}
+}
+
+func TestCharacterStateOverrides(t *testing.T) {
+	fsys := fstest.MapFS{
+		"characters/state-design/wait.json": {Data: []byte(`{"name": "wait", "frames": [
+			{"lines": ["___________", "_rfffffffl_", "_rfffffffl_", "_rfffffffl_"]},
+			{"lines": ["___________", "_rfffffffl_", "_rf7fff7fl_", "_rfffffffl_"]}
+		]}`)},
+	}
+	if err := stateregistry.LoadCharacters(fsys, "characters"); err != nil {
+		t.Fatalf("LoadCharacters() error = %v", err)
+	}
+	design := GenerateFromRegistry(CharacterMetadata{Name: "state-design", Width: 11, Height: 4})
+	rio, _ := Get("rio")
+
+	lines := func(char LibraryCharacter, name string) []string {
+		for _, frame := range char.Patterns {
+			if frame.Name == name {
+				return frame.Lines
+			}
+		}
+		return nil
+	}
+
+	// The design overrides wait; rio inherits it
+	if want := []string{"___________", "_rfffffffl_", "_rf7fff7fl_", "_rfffffffl_"}; !reflect.DeepEqual(lines(design, "wait_2"), want) {
+		t.Errorf("design wait_2 = %v, want %v", lines(design, "wait_2"), want)
+	}
+	if shared, _ := stateregistry.Get("wait"); !reflect.DeepEqual(lines(rio, "wait_2"), shared.Frames[1].Lines) {
+		t.Errorf("rio wait_2 = %v, want the shared frame", lines(rio, "wait_2"))
+	}
+	if !reflect.DeepEqual(lines(design, "read_1"), lines(rio, "read_1")) {
+		t.Errorf("design read_1 = %v, want the shared frame", lines(design, "read_1"))
+	}
+}
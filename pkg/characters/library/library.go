@@ -3,12 +3,17 @@ package library
 import (
 	"fmt"
 	"sort"
+	"sync"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
 )
 
 // Frame represents a single animation frame
 type Frame struct {
 	Name  string
 	Lines []string
+	FG    []string // Optional foreground color layer
+	BG    []string // Optional background color layer
 }
 
 // LibraryCharacter represents a pre-built character from the library
@@ -20,6 +25,18 @@ type LibraryCharacter struct {
 	Patterns    []Frame
 	Width       int
 	Height      int
+
+	// Playback settings by state name. States missing here use the shared
+	// state registries.
+	States map[string]StateInfo
+}
+
+// StateInfo holds the playback settings of one state
+type StateInfo struct {
+	FPS       int
+	NoisePool string
+	NoiseRate int
+	Effects   []domain.EffectSpec
 }
 
 // characters holds all available library characters
@@ -28,19 +45,29 @@ var libraryCharacters = make(map[string]LibraryCharacter)
 // microCharacters holds all available micro (10x2) library characters
 var microCharacters = make(map[string]LibraryCharacter)
 
+// registryMu guards the character and theme registries, which packs and
+// user themes change while characters render
+var registryMu sync.RWMutex
+
 // register adds a character to the library
 func register(char LibraryCharacter) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	libraryCharacters[char.Name] = char
 }
 
 // registerMicro adds a micro character to the micro library
 func registerMicro(char LibraryCharacter) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	microCharacters[char.Name] = char
 }
 
 // Get retrieves a library character by name (returns patterns, not built character)
 func Get(name string) (LibraryCharacter, error) {
+	registryMu.RLock()
 	libChar, exists := libraryCharacters[name]
+	registryMu.RUnlock()
 	if !exists {
 		return LibraryCharacter{}, fmt.Errorf("library character %q not found", name)
 	}
@@ -49,6 +76,8 @@ func Get(name string) (LibraryCharacter, error) {
 
 // List returns all available library character names in alphabetical order
 func List() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	names := make([]string, 0, len(libraryCharacters))
 	for name := range libraryCharacters {
 		names = append(names, name)
@@ -59,6 +88,8 @@ func List() []string {
 
 // All returns all library characters with their metadata
 func All() map[string]LibraryCharacter {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	result := make(map[string]LibraryCharacter, len(libraryCharacters))
 	for name, char := range libraryCharacters {
 		result[name] = char
@@ -66,22 +97,16 @@ func All() map[string]LibraryCharacter {
 	return result
 }
 
-// GetMicro retrieves a micro library character by name
+// GetMicro retrieves a micro library character by name, with or without
+// the "-micro" suffix
 func GetMicro(name string) (LibraryCharacter, error) {
-	// Try with -micro suffix first
-	microName := name + "-micro"
-	if libChar, exists := microCharacters[microName]; exists {
-		return libChar, nil
-	}
-	// Try exact name
-	if libChar, exists := microCharacters[name]; exists {
-		return libChar, nil
-	}
-	return LibraryCharacter{}, fmt.Errorf("micro character %q not found", name)
+	return GetSize(name, SizeMicro)
 }
 
 // ListMicro returns all available micro character names in alphabetical order
 func ListMicro() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	names := make([]string, 0, len(microCharacters))
 	for name := range microCharacters {
 		names = append(names, name)
@@ -92,6 +117,8 @@ func ListMicro() []string {
 
 // AllMicro returns all micro library characters with their metadata
 func AllMicro() map[string]LibraryCharacter {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	result := make(map[string]LibraryCharacter, len(microCharacters))
 	for name, char := range microCharacters {
 		result[name] = char
@@ -2,7 +2,8 @@ package library
 
 func init() {
 	// Register all micro (10x2) character variants
-	// All use the same micro patterns from microstateregistry, just with different colors
+	// Each uses the shared micro patterns from microstateregistry, with its own
+	// design from states/characters/<name>.json where it has one
 
 	registerMicro(GenerateMicroFromRegistry(CharacterMetadata{
 		Name:        CharacterSa + "-micro",
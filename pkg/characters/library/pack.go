@@ -0,0 +1,558 @@
+package library
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/wildreason/tangent/pkg/characters/effects"
+	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
+	"github.com/wildreason/tangent/pkg/characters/stateregistry"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// PackManifestFile is the manifest at the root of a character pack
+const PackManifestFile = "pack.json"
+
+// PackManifest describes a character pack:
+//
+//	pack.json        manifest
+//	states/*.json    states in the stateregistry format, including "base"
+//	micro.json       optional micro variant in the microstateregistry format
+//	themes/*.json    optional themes (see LoadThemes), e.g. colors for the
+//	                 pack's characters inheriting from built-in themes
+//	pack.sig         optional ed25519 signature of pack.json (see SealPack)
+type PackManifest struct {
+	Name        string          `json:"name"`
+	Version     string          `json:"version,omitempty"`
+	Description string          `json:"description,omitempty"`
+	Author      string          `json:"author,omitempty"`
+	Characters  []PackCharacter `json:"characters"`
+
+	// Files maps every other file of the pack to its SHA-256 (hex). When
+	// present, files are verified and unlisted files rejected.
+	Files map[string]string `json:"files,omitempty"`
+}
+
+// PackCharacter is one character of a pack manifest
+type PackCharacter struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Color       string `json:"color"`            // Default color, used by themes without one
+	Width       int    `json:"width,omitempty"`  // Default 11
+	Height      int    `json:"height,omitempty"` // Default 4
+	States      string `json:"states,omitempty"` // State directory, default "states"
+	Micro       string `json:"micro,omitempty"`  // Optional micro definition file
+}
+
+// Pack is a loaded character pack
+type Pack struct {
+	Manifest   PackManifest
+	Characters []string // Characters registered
+	Themes     []string // Themes registered
+}
+
+// LoadPack loads a data-only character pack from fsys with the default
+// limits and registers its characters and themes. fsys may be a directory
+// (os.DirFS) or an archive (zip.Reader). Characters are only registered
+// once every file of the pack has been read and checked, and may not
+// replace existing characters. Errors are *PackError values.
+func LoadPack(fsys fs.FS) (*Pack, error) {
+	return LoadPackWithOptions(fsys, PackOptions{})
+}
+
+// LoadPackWithOptions is LoadPack with custom limits and integrity
+// requirements. Files listed in the manifest's "files" are verified
+// against their SHA-256 checksums, and every other file read is rejected.
+func LoadPackWithOptions(fsys fs.FS, opts PackOptions) (*Pack, error) {
+	limits := opts.Limits.withDefaults()
+	pfs := &packFS{fsys: fsys, limits: limits}
+
+	manifest, raw, err := readPackManifest(pfs)
+	if err != nil {
+		return nil, err
+	}
+	pfs.pack = manifest.Name
+	fail := func(file, kind, message string, cause error) error {
+		return pfs.fail(file, kind, message, cause)
+	}
+
+	if len(opts.PublicKeys) > 0 {
+		if err := verifyPackSignature(pfs, raw, opts.PublicKeys); err != nil {
+			return nil, err
+		}
+	}
+	if manifest.Files != nil {
+		pfs.sums = make(map[string]string, len(manifest.Files))
+		for name, sum := range manifest.Files {
+			pfs.sums[path.Clean(name)] = sum
+		}
+	} else if opts.RequireChecksums || len(opts.PublicKeys) > 0 {
+		// A signature only covers the other files through their checksums
+		return nil, fail(PackManifestFile, PackErrChecksum, "no file checksums", nil)
+	}
+
+	if len(manifest.Characters) > limits.MaxCharacters {
+		return nil, fail(PackManifestFile, PackErrLimit, fmt.Sprintf("%d characters, limit %d", len(manifest.Characters), limits.MaxCharacters), nil)
+	}
+
+	pack := &Pack{Manifest: manifest}
+	var chars, micros []LibraryCharacter
+	statesByDir := make(map[string]packStates)
+	cells := 0
+	for _, pc := range manifest.Characters {
+		if _, err := Get(pc.Name); err == nil {
+			return nil, fail(PackManifestFile, PackErrConflict, fmt.Sprintf("character %q already exists", pc.Name), nil)
+		}
+		if pc.Width == 0 && pc.Height == 0 {
+			pc.Width, pc.Height = 11, 4
+		}
+		if pc.States == "" {
+			pc.States = "states"
+		}
+		if pc.Width > limits.MaxWidth || pc.Height > limits.MaxHeight {
+			return nil, fail(PackManifestFile, PackErrLimit, fmt.Sprintf("character %q is %dx%d, limit %dx%d", pc.Name, pc.Width, pc.Height, limits.MaxWidth, limits.MaxHeight), nil)
+		}
+		meta := CharacterMetadata{
+			Name:        pc.Name,
+			Description: pc.Description,
+			Author:      manifest.Author,
+			Color:       pc.Color,
+			Width:       pc.Width,
+			Height:      pc.Height,
+		}
+
+		states, ok := statesByDir[pc.States]
+		if !ok {
+			states, err = readPackStates(pfs, pc.States)
+			if err != nil {
+				return nil, err
+			}
+			statesByDir[pc.States] = states
+		}
+		n, err := checkPackStates(pfs, pc, states)
+		if err != nil {
+			return nil, err
+		}
+		if cells += n; cells > limits.MaxTotalCells {
+			return nil, fail(pc.States, PackErrLimit, fmt.Sprintf("over %d cells", limits.MaxTotalCells), nil)
+		}
+		chars = append(chars, GenerateFromStates(meta, states.states))
+
+		if pc.Micro != "" {
+			data, err := pfs.ReadFile(pc.Micro)
+			if err != nil {
+				return nil, err
+			}
+			def, err := microstateregistry.Parse(data)
+			if err != nil {
+				return nil, fail(pc.Micro, PackErrInvalid, "malformed micro definition", err)
+			}
+			n, err := checkPackMicro(pfs, pc.Micro, def)
+			if err != nil {
+				return nil, err
+			}
+			if cells += n; cells > limits.MaxTotalCells {
+				return nil, fail(pc.Micro, PackErrLimit, fmt.Sprintf("over %d cells", limits.MaxTotalCells), nil)
+			}
+			meta.Name += "-micro"
+			micros = append(micros, GenerateMicroFromDefinition(meta, def))
+		}
+	}
+
+	// Themes can color the pack's characters, so they are checked with
+	// them and registered last
+	var themes []ThemeDefinition
+	if _, err := fs.Stat(fsys, "themes"); err == nil {
+		sub, err := fs.Sub(pfs, "themes")
+		if err != nil {
+			return nil, fail("themes", PackErrRead, "can't read directory", err)
+		}
+		themes, err = readThemes(sub)
+		if err != nil {
+			var packErr *PackError
+			if errors.As(err, &packErr) {
+				return nil, packErr
+			}
+			return nil, fail("themes", PackErrInvalid, "invalid themes", err)
+		}
+	}
+	if err := pfs.verifyUnread(); err != nil {
+		return nil, err
+	}
+
+	// Check again under the lock, in case another pack registered the
+	// same character since
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	own := make(map[string]bool, len(chars))
+	for _, char := range chars {
+		if _, exists := libraryCharacters[char.Name]; exists {
+			return nil, fail(PackManifestFile, PackErrConflict, fmt.Sprintf("character %q already exists", char.Name), nil)
+		}
+		own[char.Name] = true
+	}
+	for i, theme := range themes {
+		existing, exists := themeRegistry[theme.Name]
+		if !exists {
+			continue
+		}
+		merged, err := mergePackTheme(existing, theme, own)
+		if err != nil {
+			return nil, fail("themes", PackErrConflict, err.Error(), nil)
+		}
+		themes[i] = merged
+	}
+	for _, char := range chars {
+		libraryCharacters[char.Name] = char
+		pack.Characters = append(pack.Characters, char.Name)
+	}
+	for _, char := range micros {
+		microCharacters[char.Name] = char
+	}
+	for _, theme := range themes {
+		themeRegistry[theme.Name] = theme
+		pack.Themes = append(pack.Themes, theme.Name)
+	}
+	return pack, nil
+}
+
+// mergePackTheme adds a pack theme's colors for the pack's own characters
+// to the existing theme of the same name. A pack may not recolor other
+// characters in a theme it didn't add.
+func mergePackTheme(existing, theme ThemeDefinition, own map[string]bool) (ThemeDefinition, error) {
+	merged := existing
+	merged.Colors = make(map[string]string, len(existing.Colors)+len(own))
+	for name, hex := range existing.Colors {
+		merged.Colors[name] = hex
+	}
+	merged.Palettes = make(map[string][]string, len(existing.Palettes))
+	for name, palette := range existing.Palettes {
+		merged.Palettes[name] = palette
+	}
+
+	for name, hex := range theme.Colors {
+		if !own[name] && existing.Colors[name] != hex {
+			return ThemeDefinition{}, fmt.Errorf("theme %q already exists; the pack can only add colors for its own characters, not %q", theme.Name, name)
+		}
+		merged.Colors[name] = hex
+	}
+	for name, palette := range theme.Palettes {
+		if !own[name] && strings.Join(existing.Palettes[name], ",") != strings.Join(palette, ",") {
+			return ThemeDefinition{}, fmt.Errorf("theme %q already exists; the pack can only add palettes for its own characters, not %q", theme.Name, name)
+		}
+		merged.Palettes[name] = palette
+	}
+	return merged, nil
+}
+
+// UserPacksDir returns the directory user packs are loaded from:
+// $XDG_CONFIG_HOME/tangent/packs, or ~/.config/tangent/packs.
+func UserPacksDir() string {
+	return userConfigDir("packs")
+}
+
+// LoadUserPacks loads every pack directory and *.zip archive in
+// UserPacksDir. A missing directory is not an error; packs that fail to
+// load are skipped and their errors joined.
+func LoadUserPacks() ([]*Pack, error) {
+	dir := UserPacksDir()
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var packs []*Pack
+	var errs []error
+	for _, entry := range entries {
+		if !entry.IsDir() && filepath.Ext(entry.Name()) != ".zip" {
+			continue
+		}
+		pack, err := OpenPack(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		packs = append(packs, pack)
+	}
+	return packs, errors.Join(errs...)
+}
+
+// OpenPack loads a pack from a directory or a .zip archive on disk. An
+// archive may hold the pack at its root or in a single top-level directory.
+func OpenPack(name string) (*Pack, error) {
+	return OpenPackWithOptions(name, PackOptions{})
+}
+
+// OpenPackWithOptions is OpenPack with custom limits and integrity
+// requirements (see LoadPackWithOptions).
+func OpenPackWithOptions(name string, opts PackOptions) (*Pack, error) {
+	if filepath.Ext(name) != ".zip" {
+		return LoadPackWithOptions(os.DirFS(name), opts)
+	}
+
+	info, err := os.Stat(name)
+	if err != nil {
+		return nil, &PackError{File: name, Kind: PackErrRead, Message: "can't open archive", Cause: err}
+	}
+	limits := opts.Limits.withDefaults()
+	if info.Size() > limits.MaxPackSize {
+		return nil, &PackError{File: name, Kind: PackErrLimit, Message: fmt.Sprintf("archive is %d bytes, limit %d", info.Size(), limits.MaxPackSize)}
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, &PackError{File: name, Kind: PackErrRead, Message: "can't read archive", Cause: err}
+	}
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, &PackError{File: name, Kind: PackErrRead, Message: "can't read archive", Cause: err}
+	}
+	fsys, err := packRoot(archive)
+	if err != nil {
+		return nil, &PackError{File: name, Kind: PackErrManifest, Message: err.Error()}
+	}
+	return LoadPackWithOptions(fsys, opts)
+}
+
+// packRoot returns the directory of an archive holding the manifest
+func packRoot(fsys fs.FS) (fs.FS, error) {
+	if _, err := fs.Stat(fsys, PackManifestFile); err == nil {
+		return fsys, nil
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		return fs.Sub(fsys, entries[0].Name())
+	}
+	return nil, fmt.Errorf("no %s in archive", PackManifestFile)
+}
+
+// readPackManifest reads and checks pack.json, returning it along with its
+// raw bytes for signature checks
+func readPackManifest(pfs *packFS) (PackManifest, []byte, error) {
+	data, err := pfs.ReadFile(PackManifestFile)
+	if err != nil {
+		return PackManifest{}, nil, err
+	}
+	fail := func(message string, cause error) error {
+		return pfs.fail(PackManifestFile, PackErrManifest, message, cause)
+	}
+
+	var manifest PackManifest
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&manifest); err != nil {
+		return PackManifest{}, nil, fail("malformed manifest", err)
+	}
+	pfs.pack = manifest.Name
+
+	if manifest.Name == "" {
+		return PackManifest{}, nil, fail("pack name is required", nil)
+	}
+	if len(manifest.Characters) == 0 {
+		return PackManifest{}, nil, fail("no characters", nil)
+	}
+	seen := make(map[string]bool)
+	for _, pc := range manifest.Characters {
+		switch {
+		case pc.Name == "" || strings.ContainsAny(pc.Name, "/\\ "):
+			return PackManifest{}, nil, fail(fmt.Sprintf("invalid character name %q", pc.Name), nil)
+		case seen[pc.Name]:
+			return PackManifest{}, nil, fail(fmt.Sprintf("character %q listed twice", pc.Name), nil)
+		case pc.Width < 0 || pc.Height < 0 || (pc.Width == 0) != (pc.Height == 0):
+			return PackManifest{}, nil, fail(fmt.Sprintf("character %q: invalid size %dx%d", pc.Name, pc.Width, pc.Height), nil)
+		}
+		if _, err := termcolor.ParseHex(pc.Color); err != nil {
+			return PackManifest{}, nil, fail(fmt.Sprintf("character %q", pc.Name), err)
+		}
+		seen[pc.Name] = true
+	}
+	return manifest, data, nil
+}
+
+// packStates are the states of a pack directory and the files they came from
+type packStates struct {
+	states map[string]stateregistry.StateDefinition
+	files  map[string]string
+}
+
+// readPackStates reads the *.json states in dir
+func readPackStates(pfs *packFS, dir string) (packStates, error) {
+	entries, err := pfs.ReadDir(dir)
+	if err != nil {
+		return packStates{}, err
+	}
+
+	states := make(map[string]stateregistry.StateDefinition)
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		file := path.Join(dir, entry.Name())
+		data, err := pfs.ReadFile(file)
+		if err != nil {
+			return packStates{}, err
+		}
+		var state stateregistry.StateDefinition
+		if err := json.Unmarshal(data, &state); err != nil {
+			return packStates{}, pfs.fail(file, PackErrInvalid, "malformed state", err)
+		}
+		// Frame names are "<state>_<n>", split on the first underscore
+		if state.Name == "" || strings.Contains(state.Name, "_") {
+			return packStates{}, pfs.fail(file, PackErrInvalid, fmt.Sprintf("invalid state name %q", state.Name), nil)
+		}
+		if other, dup := files[state.Name]; dup {
+			return packStates{}, pfs.fail(file, PackErrInvalid, fmt.Sprintf("state %q already defined in %s", state.Name, other), nil)
+		}
+		states[state.Name] = state
+		files[state.Name] = file
+	}
+	if len(states) == 0 {
+		return packStates{}, pfs.fail(dir, PackErrInvalid, "no states", nil)
+	}
+	if _, ok := states["base"]; !ok {
+		return packStates{}, pfs.fail(dir, PackErrInvalid, `no "base" state`, nil)
+	}
+	return packStates{states, files}, nil
+}
+
+// checkPackStates checks a character's states against its size and the
+// limits, returning the number of cells they hold
+func checkPackStates(pfs *packFS, pc PackCharacter, states packStates) (int, error) {
+	if len(states.states) > pfs.limits.MaxStates {
+		return 0, pfs.fail(pc.States, PackErrLimit, fmt.Sprintf("%d states, limit %d", len(states.states), pfs.limits.MaxStates), nil)
+	}
+	names := make([]string, 0, len(states.states))
+	for name := range states.states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	cells := 0
+	for _, name := range names {
+		state, file := states.states[name], states.files[name]
+		frames := make([]packFrame, len(state.Frames))
+		for i, f := range state.Frames {
+			frames[i] = packFrame{f.Lines, f.FG, f.BG}
+		}
+		n, err := checkPackFrames(pfs, file, fmt.Sprintf("character %q state %q", pc.Name, name), frames, pc.Width, pc.Height)
+		if err != nil {
+			return 0, err
+		}
+		cells += n
+		if err := checkPackRates(pfs, file, fmt.Sprintf("state %q", name), state.FPS, state.NoiseRate); err != nil {
+			return 0, err
+		}
+		if _, err := effects.Build(state.Effects); err != nil {
+			return 0, pfs.fail(file, PackErrInvalid, fmt.Sprintf("state %q", name), err)
+		}
+	}
+	return cells, nil
+}
+
+// checkPackMicro checks a micro definition's frames and effects,
+// returning the number of cells it holds
+func checkPackMicro(pfs *packFS, file string, def *microstateregistry.MicroDefinition) (int, error) {
+	if def.Width <= 0 || def.Height <= 0 {
+		return 0, pfs.fail(file, PackErrInvalid, fmt.Sprintf("invalid size %dx%d", def.Width, def.Height), nil)
+	}
+	if def.Width > pfs.limits.MaxWidth || def.Height > pfs.limits.MaxHeight {
+		return 0, pfs.fail(file, PackErrLimit, fmt.Sprintf("size %dx%d, limit %dx%d", def.Width, def.Height, pfs.limits.MaxWidth, pfs.limits.MaxHeight), nil)
+	}
+	if len(def.States) > pfs.limits.MaxStates {
+		return 0, pfs.fail(file, PackErrLimit, fmt.Sprintf("%d states, limit %d", len(def.States), pfs.limits.MaxStates), nil)
+	}
+
+	base := []packFrame{{def.BaseFrame.Lines, def.BaseFrame.FG, def.BaseFrame.BG}}
+	cells, err := checkPackFrames(pfs, file, "base frame", base, def.Width, def.Height)
+	if err != nil {
+		return 0, err
+	}
+	for _, state := range def.States {
+		if state.Name == "" || state.Name == "base" || strings.Contains(state.Name, "_") {
+			return 0, pfs.fail(file, PackErrInvalid, fmt.Sprintf("invalid state name %q", state.Name), nil)
+		}
+		frames := make([]packFrame, len(state.Frames))
+		for i, f := range state.Frames {
+			frames[i] = packFrame{f.Lines, f.FG, f.BG}
+		}
+		n, err := checkPackFrames(pfs, file, fmt.Sprintf("state %q", state.Name), frames, def.Width, def.Height)
+		if err != nil {
+			return 0, err
+		}
+		cells += n
+		if err := checkPackRates(pfs, file, fmt.Sprintf("state %q", state.Name), state.FPS, state.NoiseRate); err != nil {
+			return 0, err
+		}
+		if _, err := effects.Build(state.Effects); err != nil {
+			return 0, pfs.fail(file, PackErrInvalid, fmt.Sprintf("state %q", state.Name), err)
+		}
+	}
+	return cells, nil
+}
+
+// checkPackRates checks a state's frame and noise rates against the
+// limits; renderers divide a second by them
+func checkPackRates(pfs *packFS, file, where string, fps, noiseRate int) error {
+	if fps > pfs.limits.MaxFPS {
+		return pfs.fail(file, PackErrLimit, fmt.Sprintf("%s: %d fps, limit %d", where, fps, pfs.limits.MaxFPS), nil)
+	}
+	if noiseRate > pfs.limits.MaxNoiseRate {
+		return pfs.fail(file, PackErrLimit, fmt.Sprintf("%s: noise rate %d, limit %d", where, noiseRate, pfs.limits.MaxNoiseRate), nil)
+	}
+	return nil
+}
+
+// packFrame is the pattern lines and optional color layers of a frame
+type packFrame struct {
+	lines, fg, bg []string
+}
+
+// checkPackFrames checks a state has between one and MaxFrames frames of
+// width x height, returning the number of cells
+func checkPackFrames(pfs *packFS, file, where string, frames []packFrame, width, height int) (int, error) {
+	if len(frames) == 0 {
+		return 0, pfs.fail(file, PackErrInvalid, where+": no frames", nil)
+	}
+	if len(frames) > pfs.limits.MaxFrames {
+		return 0, pfs.fail(file, PackErrLimit, fmt.Sprintf("%s: %d frames, limit %d", where, len(frames), pfs.limits.MaxFrames), nil)
+	}
+	for i, frame := range frames {
+		layers := []struct {
+			name  string
+			lines []string
+		}{{"lines", frame.lines}, {"fg", frame.fg}, {"bg", frame.bg}}
+		for _, layer := range layers {
+			if layer.name != "lines" && layer.lines == nil {
+				continue
+			}
+			if len(layer.lines) != height {
+				return 0, pfs.fail(file, PackErrInvalid, fmt.Sprintf("%s frame %d: %d %s, want %d", where, i+1, len(layer.lines), layer.name, height), nil)
+			}
+			for row, line := range layer.lines {
+				if n := utf8.RuneCountInString(line); n != width {
+					return 0, pfs.fail(file, PackErrInvalid, fmt.Sprintf("%s frame %d: %s row %d is %d wide, want %d", where, i+1, layer.name, row+1, n, width), nil)
+				}
+			}
+		}
+	}
+	return len(frames) * width * height, nil
+}
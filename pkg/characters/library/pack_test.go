@@ -0,0 +1,278 @@
+package library
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// keepCharacters restores the character registries when the test ends
+func keepCharacters(t *testing.T) {
+	saved := All()
+	savedMicro := AllMicro()
+	savedSized := sizedCharacters
+	savedVariants := make(map[Size]SizeVariant, len(sizeVariants))
+	for size, v := range sizeVariants {
+		savedVariants[size] = v
+	}
+	sizedCharacters = make(map[Size]map[string]LibraryCharacter)
+	t.Cleanup(func() {
+		libraryCharacters = saved
+		microCharacters = savedMicro
+		sizedCharacters = savedSized
+		sizeVariants = savedVariants
+	})
+}
+
+// testPack is a small valid pack with one regular state, a base frame, a
+// micro variant and a theme
+func testPack() fstest.MapFS {
+	return fstest.MapFS{
+		"pack.json": {Data: []byte(`{
+  "name": "robots",
+  "version": "1.0.0",
+  "author": "Robot Co",
+  "characters": [
+    {"name": "bolt", "description": "A robot", "color": "#4FA3FF", "micro": "micro.json"},
+    {"name": "nut", "color": "#FF8844"}
+  ]
+}`)},
+		"states/base.json": {Data: []byte(`{"name": "base", "frames": [{"lines": ["___________", "__RFFFFFL__", "_2FFFFFFF1_", "___11_22___"]}]}`)},
+		"states/beep.json": {Data: []byte(`{
+  "name": "beep",
+  "fps": 8,
+  "effects": [{"type": "gradient"}],
+  "frames": [
+    {"lines": ["___________", "__R5F6FL___", "_2FFFFFFF1_", "___11_22___"]},
+    {"lines": ["___________", "__RF5F6L___", "_2FFFFFFF1_", "___11_22___"]}
+  ]
+}`)},
+		"micro.json": {Data: []byte(`{
+  "name": "micro",
+  "width": 8,
+  "height": 2,
+  "base_frame": {"lines": ["_RFFFFL_", "_2FFFF1_"]},
+  "states": [{"name": "beep", "fps": 12, "frames": [{"lines": ["_R5FF6L_", "_2FFFF1_"]}]}]
+}`)},
+		"themes/bright.json": {Data: []byte(`{"name": "bright", "inherits": "bright", "colors": {"bolt": "#00AAFF"}}`)},
+	}
+}
+
+func TestLoadPack(t *testing.T) {
+	keepCharacters(t)
+	keepThemes(t)
+
+	pack, err := LoadPack(testPack())
+	if err != nil {
+		t.Fatalf("LoadPack() error = %v", err)
+	}
+	if got := strings.Join(pack.Characters, ","); got != "bolt,nut" {
+		t.Errorf("Characters = %s, want bolt,nut", got)
+	}
+	if got := strings.Join(pack.Themes, ","); got != "bright" {
+		t.Errorf("Themes = %s, want bright", got)
+	}
+
+	bolt, err := Get("bolt")
+	if err != nil {
+		t.Fatalf("Get(bolt) error = %v", err)
+	}
+	if bolt.Author != "Robot Co" || bolt.Width != 11 || bolt.Height != 4 {
+		t.Errorf("bolt = %s %dx%d, want Robot Co 11x4", bolt.Author, bolt.Width, bolt.Height)
+	}
+	var names []string
+	for _, frame := range bolt.Patterns {
+		names = append(names, frame.Name)
+	}
+	if got := strings.Join(names, ","); got != "base,beep_1,beep_2" {
+		t.Errorf("bolt frames = %s, want base,beep_1,beep_2", got)
+	}
+	if info := bolt.States["beep"]; info.FPS != 8 || len(info.Effects) != 1 {
+		t.Errorf("bolt beep = %+v, want fps 8 and one effect", info)
+	}
+
+	micro, err := GetMicro("bolt")
+	if err != nil {
+		t.Fatalf("GetMicro(bolt) error = %v", err)
+	}
+	if micro.Width != 8 || micro.States["beep"].FPS != 12 {
+		t.Errorf("bolt micro = %dx%d fps %d, want 8x2 fps 12", micro.Width, micro.Height, micro.States["beep"].FPS)
+	}
+	if _, err := GetMicro("nut"); err == nil {
+		t.Error("GetMicro(nut) found a micro variant the pack doesn't have")
+	}
+
+	bright, _ := GetTheme("bright")
+	if bright.Colors["bolt"] != "#00AAFF" || bright.Colors["sam"] == "" {
+		t.Errorf("bright = %v, want bolt added to the built-in colors", bright.Colors)
+	}
+	if bright.Affinity != AffinityDark {
+		t.Errorf("bright affinity = %q, want the built-in theme's", bright.Affinity)
+	}
+
+	// Loading again would replace the pack's characters
+	if _, err := LoadPack(testPack()); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second LoadPack() error = %v, want already exists", err)
+	}
+}
+
+func TestLoadPackConcurrent(t *testing.T) {
+	keepCharacters(t)
+	keepThemes(t)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			Get("bolt")
+			List()
+			GetMicro("bolt")
+			GetTheme("bright")
+		}
+	}()
+	if _, err := LoadPack(testPack()); err != nil {
+		t.Errorf("LoadPack() error = %v", err)
+	}
+	<-done
+}
+
+func TestLoadPackErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(fstest.MapFS)
+		want   string
+	}{
+		{
+			name:   "missing manifest",
+			modify: func(fsys fstest.MapFS) { delete(fsys, "pack.json") },
+			want:   "pack.json",
+		},
+		{
+			name: "unknown manifest field",
+			modify: func(fsys fstest.MapFS) {
+				fsys["pack.json"] = &fstest.MapFile{Data: []byte(`{"name": "x", "charaters": []}`)}
+			},
+			want: "unknown field",
+		},
+		{
+			name: "existing character",
+			modify: func(fsys fstest.MapFS) {
+				register(LibraryCharacter{Name: "sam"})
+				fsys["pack.json"] = &fstest.MapFile{Data: []byte(`{"name": "x", "characters": [{"name": "sam", "color": "#FF0000"}]}`)}
+			},
+			want: "already exists",
+		},
+		{
+			name: "bad color",
+			modify: func(fsys fstest.MapFS) {
+				fsys["pack.json"] = &fstest.MapFile{Data: []byte(`{"name": "x", "characters": [{"name": "bolt", "color": "red"}]}`)}
+			},
+			want: "invalid hex",
+		},
+		{
+			name: "wrong frame width",
+			modify: func(fsys fstest.MapFS) {
+				fsys["states/wide.json"] = &fstest.MapFile{Data: []byte(`{"name": "wide", "frames": [{"lines": ["____________", "_", "_", "_"]}]}`)}
+			},
+			want: `state "wide" frame 1: lines row 1 is 12 wide, want 11`,
+		},
+		{
+			name: "wrong color layer height",
+			modify: func(fsys fstest.MapFS) {
+				fsys["states/tint.json"] = &fstest.MapFile{Data: []byte(`{"name": "tint", "frames": [{"lines": ["___________", "___________", "___________", "___________"], "fg": ["00000000000"]}]}`)}
+			},
+			want: "1 fg, want 4",
+		},
+		{
+			name: "underscore in state name",
+			modify: func(fsys fstest.MapFS) {
+				fsys["states/x.json"] = &fstest.MapFile{Data: []byte(`{"name": "big_beep", "frames": [{"lines": ["___________", "___________", "___________", "___________"]}]}`)}
+			},
+			want: "invalid state name",
+		},
+		{
+			name: "unknown effect",
+			modify: func(fsys fstest.MapFS) {
+				fsys["states/x.json"] = &fstest.MapFile{Data: []byte(`{"name": "x", "effects": [{"type": "sparkle"}], "frames": [{"lines": ["___________", "___________", "___________", "___________"]}]}`)}
+			},
+			want: "sparkle",
+		},
+		{
+			name:   "missing base state",
+			modify: func(fsys fstest.MapFS) { delete(fsys, "states/base.json") },
+			want:   `states: no "base" state`,
+		},
+		{
+			name: "bad theme color",
+			modify: func(fsys fstest.MapFS) {
+				fsys["themes/bright.json"] = &fstest.MapFile{Data: []byte(`{"name": "bright", "inherits": "bright", "colors": {"bolt": "blue"}}`)}
+			},
+			want: "bright.json",
+		},
+		{
+			name: "recolors a built-in character",
+			modify: func(fsys fstest.MapFS) {
+				fsys["themes/latte.json"] = &fstest.MapFile{Data: []byte(`{"name": "latte", "colors": {"bolt": "#00AAFF", "sam": "#000000"}}`)}
+			},
+			want: `theme "latte" already exists; the pack can only add colors for its own characters, not "sam"`,
+		},
+		{
+			name:   "missing micro file",
+			modify: func(fsys fstest.MapFS) { delete(fsys, "micro.json") },
+			want:   "micro.json",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepCharacters(t)
+			keepThemes(t)
+			fsys := testPack()
+			tt.modify(fsys)
+
+			_, err := LoadPack(fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("LoadPack() error = %v, want %q", err, tt.want)
+			}
+			// A failed pack registers nothing
+			if _, err := Get("bolt"); err == nil {
+				t.Error("failed LoadPack() registered bolt")
+			}
+		})
+	}
+}
+
+func TestOpenPackArchive(t *testing.T) {
+	keepCharacters(t)
+	keepThemes(t)
+
+	// Archive with the pack in a top-level directory, as zip tools make it
+	name := filepath.Join(t.TempDir(), "robots.zip")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(f)
+	for file, data := range testPack() {
+		fw, err := w.Create("robots/" + file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fw.Write(data.Data)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	pack, err := OpenPack(name)
+	if err != nil {
+		t.Fatalf("OpenPack() error = %v", err)
+	}
+	if pack.Manifest.Name != "robots" || len(pack.Characters) != 2 {
+		t.Errorf("OpenPack() = %s with %v, want robots with 2 characters", pack.Manifest.Name, pack.Characters)
+	}
+}
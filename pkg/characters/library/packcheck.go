@@ -0,0 +1,304 @@
+package library
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// PackSignatureFile holds the base64 ed25519 signature of pack.json
+const PackSignatureFile = "pack.sig"
+
+// Pack error kinds
+const (
+	PackErrRead      = "read"      // A file is missing or unreadable
+	PackErrManifest  = "manifest"  // pack.json is malformed
+	PackErrChecksum  = "checksum"  // A file is unlisted or doesn't match its checksum
+	PackErrSignature = "signature" // The signature is missing or invalid
+	PackErrLimit     = "limit"     // The pack exceeds a PackLimits bound
+	PackErrInvalid   = "invalid"   // State, frame or theme data is invalid
+	PackErrConflict  = "conflict"  // A character already exists
+)
+
+// PackError is the error returned for a pack that can't be loaded.
+type PackError struct {
+	Pack    string // Pack name, when the manifest was read
+	File    string // File within the pack, if any
+	Kind    string // One of the PackErr kinds
+	Message string
+	Cause   error
+}
+
+func (e *PackError) Error() string {
+	var b strings.Builder
+	if e.Pack != "" {
+		fmt.Fprintf(&b, "pack %q: ", e.Pack)
+	}
+	if e.File != "" {
+		fmt.Fprintf(&b, "%s: ", e.File)
+	}
+	b.WriteString(e.Message)
+	if e.Cause != nil {
+		fmt.Fprintf(&b, ": %v", e.Cause)
+	}
+	return b.String()
+}
+
+func (e *PackError) Unwrap() error {
+	return e.Cause
+}
+
+// PackLimits bounds the resources a pack may use. Zero fields take the
+// value from DefaultPackLimits.
+type PackLimits struct {
+	MaxFileSize   int64 // Bytes per file
+	MaxPackSize   int64 // Bytes across all files read, and of an archive
+	MaxFiles      int   // Files read, and entries per directory
+	MaxCharacters int
+	MaxStates     int // States per character
+	MaxFrames     int // Frames per state
+	MaxWidth      int
+	MaxHeight     int
+	MaxTotalCells int // Cells across every frame of every character
+	MaxFPS        int // Frame rate of a state
+	MaxNoiseRate  int // Noise changes per second of a state
+}
+
+// DefaultPackLimits are generous for hand-made characters; the built-in
+// characters use about 4,000 cells each.
+var DefaultPackLimits = PackLimits{
+	MaxFileSize:   1 << 20,
+	MaxPackSize:   16 << 20,
+	MaxFiles:      512,
+	MaxCharacters: 64,
+	MaxStates:     128,
+	MaxFrames:     64,
+	MaxWidth:      64,
+	MaxHeight:     32,
+	MaxTotalCells: 250000,
+	MaxFPS:        60,
+	MaxNoiseRate:  60,
+}
+
+// withDefaults fills zero limits from DefaultPackLimits
+func (l PackLimits) withDefaults() PackLimits {
+	d := DefaultPackLimits
+	if l.MaxFileSize <= 0 {
+		l.MaxFileSize = d.MaxFileSize
+	}
+	if l.MaxPackSize <= 0 {
+		l.MaxPackSize = d.MaxPackSize
+	}
+	if l.MaxFiles <= 0 {
+		l.MaxFiles = d.MaxFiles
+	}
+	if l.MaxCharacters <= 0 {
+		l.MaxCharacters = d.MaxCharacters
+	}
+	if l.MaxStates <= 0 {
+		l.MaxStates = d.MaxStates
+	}
+	if l.MaxFrames <= 0 {
+		l.MaxFrames = d.MaxFrames
+	}
+	if l.MaxWidth <= 0 {
+		l.MaxWidth = d.MaxWidth
+	}
+	if l.MaxHeight <= 0 {
+		l.MaxHeight = d.MaxHeight
+	}
+	if l.MaxTotalCells <= 0 {
+		l.MaxTotalCells = d.MaxTotalCells
+	}
+	if l.MaxFPS <= 0 {
+		l.MaxFPS = d.MaxFPS
+	}
+	if l.MaxNoiseRate <= 0 {
+		l.MaxNoiseRate = d.MaxNoiseRate
+	}
+	return l
+}
+
+// PackOptions configures LoadPackWithOptions.
+type PackOptions struct {
+	Limits PackLimits
+
+	// RequireChecksums rejects packs whose manifest lists no file checksums
+	RequireChecksums bool
+
+	// PublicKeys are the trusted signing keys. When set, the pack must
+	// carry pack.sig, a signature of pack.json by one of them; since the
+	// manifest must list every file's checksum, that covers the whole
+	// pack.
+	PublicKeys []ed25519.PublicKey
+}
+
+// packFS reads the files of a pack within its limits, verifying them
+// against the manifest checksums once those are known
+type packFS struct {
+	fsys   fs.FS
+	pack   string
+	limits PackLimits
+	sums   map[string]string // path -> SHA-256 hex, nil when not listed
+	read   map[string]bool   // Listed files read and verified
+	files  int
+	total  int64
+}
+
+func (p *packFS) Open(name string) (fs.File, error) {
+	return p.fsys.Open(name)
+}
+
+func (p *packFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(p.fsys, name)
+	if err != nil {
+		return nil, p.fail(name, PackErrRead, "can't read directory", err)
+	}
+	if len(entries) > p.limits.MaxFiles {
+		return nil, p.fail(name, PackErrLimit, fmt.Sprintf("%d entries, limit %d", len(entries), p.limits.MaxFiles), nil)
+	}
+	return entries, nil
+}
+
+func (p *packFS) ReadFile(name string) ([]byte, error) {
+	if p.files++; p.files > p.limits.MaxFiles {
+		return nil, p.fail(name, PackErrLimit, fmt.Sprintf("more than %d files", p.limits.MaxFiles), nil)
+	}
+
+	f, err := p.fsys.Open(name)
+	if err != nil {
+		return nil, p.fail(name, PackErrRead, "can't open", err)
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > p.limits.MaxFileSize {
+		return nil, p.fail(name, PackErrLimit, fmt.Sprintf("%d bytes, limit %d", info.Size(), p.limits.MaxFileSize), nil)
+	}
+
+	// The reported size can't be trusted (archive headers), so the read
+	// is bounded as well
+	data, err := io.ReadAll(io.LimitReader(f, p.limits.MaxFileSize+1))
+	if err != nil {
+		return nil, p.fail(name, PackErrRead, "can't read", err)
+	}
+	if int64(len(data)) > p.limits.MaxFileSize {
+		return nil, p.fail(name, PackErrLimit, fmt.Sprintf("more than %d bytes", p.limits.MaxFileSize), nil)
+	}
+	if p.total += int64(len(data)); p.total > p.limits.MaxPackSize {
+		return nil, p.fail(name, PackErrLimit, fmt.Sprintf("pack is over %d bytes", p.limits.MaxPackSize), nil)
+	}
+
+	if p.sums != nil {
+		want, listed := p.sums[path.Clean(name)]
+		if !listed {
+			return nil, p.fail(name, PackErrChecksum, "not listed in "+PackManifestFile, nil)
+		}
+		sum := sha256.Sum256(data)
+		if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+			return nil, p.fail(name, PackErrChecksum, fmt.Sprintf("checksum mismatch (want %s, got %s)", want, got), nil)
+		}
+		if p.read == nil {
+			p.read = make(map[string]bool)
+		}
+		p.read[path.Clean(name)] = true
+	}
+	return data, nil
+}
+
+// verifyUnread reads and verifies the listed files loading didn't read, so
+// a file removed from a sealed pack is detected
+func (p *packFS) verifyUnread() error {
+	var unread []string
+	for name := range p.sums {
+		if !p.read[name] {
+			unread = append(unread, name)
+		}
+	}
+	sort.Strings(unread)
+	for _, name := range unread {
+		if _, err := p.ReadFile(name); err != nil {
+			var packErr *PackError
+			if errors.As(err, &packErr) && packErr.Kind == PackErrRead {
+				return p.fail(name, PackErrChecksum, "listed in "+PackManifestFile+" but missing", err)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// fail builds a PackError for a file of this pack
+func (p *packFS) fail(file, kind, message string, cause error) *PackError {
+	return &PackError{Pack: p.pack, File: file, Kind: kind, Message: message, Cause: cause}
+}
+
+// verifyPackSignature checks pack.sig is a signature of manifest by one of keys
+func verifyPackSignature(p *packFS, manifest []byte, keys []ed25519.PublicKey) error {
+	sig, err := p.ReadFile(PackSignatureFile)
+	if err != nil {
+		var packErr *PackError
+		if errors.As(err, &packErr) && packErr.Kind == PackErrRead {
+			return p.fail(PackSignatureFile, PackErrSignature, "signature required", err)
+		}
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(sig)))
+	if err != nil || len(decoded) != ed25519.SignatureSize {
+		return p.fail(PackSignatureFile, PackErrSignature, "malformed signature", err)
+	}
+	for _, key := range keys {
+		if len(key) == ed25519.PublicKeySize && ed25519.Verify(key, manifest, decoded) {
+			return nil
+		}
+	}
+	return p.fail(PackSignatureFile, PackErrSignature, "not signed by a trusted key", nil)
+}
+
+// PackChecksums returns the SHA-256 of every file in fsys except the
+// manifest and signature, for the manifest's "files" field.
+func PackChecksums(fsys fs.FS) (map[string]string, error) {
+	sums := make(map[string]string)
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || name == PackManifestFile || name == PackSignatureFile {
+			return err
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		sums[name] = hex.EncodeToString(sum[:])
+		return nil
+	})
+	return sums, err
+}
+
+// SealPack lists the checksums of every file of the pack in fsys in its
+// manifest and returns the new pack.json. With a key it also returns the
+// pack.sig contents signing it.
+func SealPack(fsys fs.FS, key ed25519.PrivateKey) (manifest, signature []byte, err error) {
+	m, _, err := readPackManifest(&packFS{fsys: fsys, limits: DefaultPackLimits})
+	if err != nil {
+		return nil, nil, err
+	}
+	if m.Files, err = PackChecksums(fsys); err != nil {
+		return nil, nil, err
+	}
+	if manifest, err = json.MarshalIndent(m, "", "  "); err != nil {
+		return nil, nil, err
+	}
+	manifest = append(manifest, '\n')
+	if key != nil {
+		sig := ed25519.Sign(key, manifest)
+		signature = []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+	}
+	return manifest, signature, nil
+}
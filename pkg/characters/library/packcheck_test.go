@@ -0,0 +1,238 @@
+package library
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// sealedPack returns testPack with checksums in its manifest, signed by key
+func sealedPack(t *testing.T, key ed25519.PrivateKey) fstest.MapFS {
+	fsys := testPack()
+	manifest, sig, err := SealPack(fsys, key)
+	if err != nil {
+		t.Fatalf("SealPack() error = %v", err)
+	}
+	fsys[PackManifestFile] = &fstest.MapFile{Data: manifest}
+	if sig != nil {
+		fsys[PackSignatureFile] = &fstest.MapFile{Data: sig}
+	}
+	return fsys
+}
+
+func TestLoadPackIntegrity(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	otherPub, otherPriv, _ := ed25519.GenerateKey(nil)
+
+	tests := []struct {
+		name     string
+		fsys     func() fstest.MapFS
+		opts     PackOptions
+		wantKind string // "" for success
+	}{
+		{
+			name: "unsealed pack",
+			fsys: testPack,
+		},
+		{
+			name: "checksums verified",
+			fsys: func() fstest.MapFS { return sealedPack(t, nil) },
+		},
+		{
+			name:     "checksums required",
+			fsys:     testPack,
+			opts:     PackOptions{RequireChecksums: true},
+			wantKind: PackErrChecksum,
+		},
+		{
+			name: "tampered file",
+			fsys: func() fstest.MapFS {
+				fsys := sealedPack(t, nil)
+				fsys["micro.json"].Data = []byte(strings.Replace(string(fsys["micro.json"].Data), "_R5FF6L_", "_R6FF5L_", 1))
+				return fsys
+			},
+			wantKind: PackErrChecksum,
+		},
+		{
+			name: "unlisted file",
+			fsys: func() fstest.MapFS {
+				fsys := sealedPack(t, nil)
+				fsys["states/extra.json"] = &fstest.MapFile{Data: []byte(`{"name": "extra", "frames": [{"lines": ["___________", "___________", "___________", "___________"]}]}`)}
+				return fsys
+			},
+			wantKind: PackErrChecksum,
+		},
+		{
+			name: "listed state removed",
+			fsys: func() fstest.MapFS {
+				fsys := sealedPack(t, priv)
+				delete(fsys, "states/beep.json")
+				return fsys
+			},
+			opts:     PackOptions{PublicKeys: []ed25519.PublicKey{pub}},
+			wantKind: PackErrChecksum,
+		},
+		{
+			name: "listed theme removed",
+			fsys: func() fstest.MapFS {
+				fsys := sealedPack(t, priv)
+				delete(fsys, "themes/bright.json")
+				return fsys
+			},
+			opts:     PackOptions{PublicKeys: []ed25519.PublicKey{pub}},
+			wantKind: PackErrChecksum,
+		},
+		{
+			name: "listed file unused by loading",
+			fsys: func() fstest.MapFS {
+				fsys := testPack()
+				fsys["README.md"] = &fstest.MapFile{Data: []byte("robots")}
+				manifest, _, err := SealPack(fsys, nil)
+				if err != nil {
+					t.Fatalf("SealPack() error = %v", err)
+				}
+				fsys[PackManifestFile] = &fstest.MapFile{Data: manifest}
+				return fsys
+			},
+		},
+		{
+			name: "signed by trusted key",
+			fsys: func() fstest.MapFS { return sealedPack(t, priv) },
+			opts: PackOptions{PublicKeys: []ed25519.PublicKey{otherPub, pub}},
+		},
+		{
+			name:     "signed by other key",
+			fsys:     func() fstest.MapFS { return sealedPack(t, otherPriv) },
+			opts:     PackOptions{PublicKeys: []ed25519.PublicKey{pub}},
+			wantKind: PackErrSignature,
+		},
+		{
+			name:     "signature missing",
+			fsys:     func() fstest.MapFS { return sealedPack(t, nil) },
+			opts:     PackOptions{PublicKeys: []ed25519.PublicKey{pub}},
+			wantKind: PackErrSignature,
+		},
+		{
+			name: "signed without checksums",
+			fsys: func() fstest.MapFS {
+				fsys := testPack()
+				sig := ed25519.Sign(priv, fsys[PackManifestFile].Data)
+				fsys[PackSignatureFile] = &fstest.MapFile{Data: []byte(base64.StdEncoding.EncodeToString(sig))}
+				return fsys
+			},
+			opts:     PackOptions{PublicKeys: []ed25519.PublicKey{pub}},
+			wantKind: PackErrChecksum,
+		},
+		{
+			name: "manifest changed after signing",
+			fsys: func() fstest.MapFS {
+				fsys := sealedPack(t, priv)
+				fsys[PackManifestFile].Data = []byte(strings.Replace(string(fsys[PackManifestFile].Data), "Robot Co", "Evil Co", 1))
+				return fsys
+			},
+			opts:     PackOptions{PublicKeys: []ed25519.PublicKey{pub}},
+			wantKind: PackErrSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepCharacters(t)
+			keepThemes(t)
+
+			_, err := LoadPackWithOptions(tt.fsys(), tt.opts)
+			if tt.wantKind == "" {
+				if err != nil {
+					t.Fatalf("LoadPackWithOptions() error = %v", err)
+				}
+				return
+			}
+			var packErr *PackError
+			if !errors.As(err, &packErr) || packErr.Kind != tt.wantKind {
+				t.Fatalf("LoadPackWithOptions() error = %v, want %s PackError", err, tt.wantKind)
+			}
+			if packErr.Pack != "robots" {
+				t.Errorf("PackError.Pack = %q, want robots", packErr.Pack)
+			}
+		})
+	}
+}
+
+func TestLoadPackLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		limits   PackLimits
+		wantFile string
+	}{
+		{"file size", PackLimits{MaxFileSize: 230}, "states/beep.json"},
+		{"pack size", PackLimits{MaxPackSize: 400}, "states/beep.json"},
+		{"file count", PackLimits{MaxFiles: 2}, "states/beep.json"},
+		{"characters", PackLimits{MaxCharacters: 1}, PackManifestFile},
+		{"states", PackLimits{MaxStates: 1}, "states"},
+		{"frames", PackLimits{MaxFrames: 1}, "states/beep.json"},
+		{"width", PackLimits{MaxWidth: 10}, PackManifestFile},
+		{"height", PackLimits{MaxHeight: 3}, PackManifestFile},
+		{"total cells", PackLimits{MaxTotalCells: 200}, "states"},
+		{"fps", PackLimits{MaxFPS: 4}, "states/beep.json"},
+		{"micro fps", PackLimits{MaxFPS: 10}, "micro.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepCharacters(t)
+			keepThemes(t)
+
+			_, err := LoadPackWithOptions(testPack(), PackOptions{Limits: tt.limits})
+			var packErr *PackError
+			if !errors.As(err, &packErr) || packErr.Kind != PackErrLimit {
+				t.Fatalf("LoadPackWithOptions() error = %v, want limit PackError", err)
+			}
+			if packErr.File != tt.wantFile {
+				t.Errorf("PackError.File = %q, want %q (%v)", packErr.File, tt.wantFile, err)
+			}
+			if _, err := Get("bolt"); err == nil {
+				t.Error("pack over its limits registered bolt")
+			}
+		})
+	}
+}
+
+func TestPackErrorKinds(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(fstest.MapFS)
+		want   string
+	}{
+		{"missing manifest", func(fsys fstest.MapFS) { delete(fsys, PackManifestFile) }, PackErrRead},
+		{"malformed manifest", func(fsys fstest.MapFS) { fsys[PackManifestFile].Data = []byte("{") }, PackErrManifest},
+		{"malformed state", func(fsys fstest.MapFS) { fsys["states/beep.json"].Data = []byte(`{"frames": 3}`) }, PackErrInvalid},
+		{"existing character", func(fsys fstest.MapFS) { register(LibraryCharacter{Name: "nut"}) }, PackErrConflict},
+		{"noise rate", func(fsys fstest.MapFS) {
+			fsys["states/beep.json"].Data = []byte(strings.Replace(string(fsys["states/beep.json"].Data), `"fps": 8`, `"noise_rate": 2000000000`, 1))
+		}, PackErrLimit},
+		{"micro noise rate", func(fsys fstest.MapFS) {
+			fsys["micro.json"].Data = []byte(strings.Replace(string(fsys["micro.json"].Data), `"fps": 12`, `"noise_rate": 2000000000`, 1))
+		}, PackErrLimit},
+		{"huge fps", func(fsys fstest.MapFS) {
+			fsys["states/beep.json"].Data = []byte(strings.Replace(string(fsys["states/beep.json"].Data), `"fps": 8`, `"fps": 2000000000`, 1))
+		}, PackErrLimit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keepCharacters(t)
+			keepThemes(t)
+			fsys := testPack()
+			tt.modify(fsys)
+
+			_, err := LoadPack(fsys)
+			var packErr *PackError
+			if !errors.As(err, &packErr) || packErr.Kind != tt.want {
+				t.Errorf("LoadPack() error = %v, want %s PackError", err, tt.want)
+			}
+		})
+	}
+}
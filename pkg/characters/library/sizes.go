@@ -0,0 +1,238 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/microstateregistry"
+	"github.com/wildreason/tangent/pkg/characters/stateregistry"
+)
+
+// Size names a size variant of the characters
+type Size string
+
+// Standard size variants
+const (
+	SizeNano    Size = "nano"    // 4x1
+	SizeMicro   Size = "micro"   // 8x2
+	SizeRegular Size = "regular" // 11x4
+	SizeLarge   Size = "large"   // 22x8
+)
+
+// DefaultFPS is the frame rate of states that set none, for size variants
+// that don't set their own
+const DefaultFPS = 5
+
+// SizeVariant describes a size variant. Size-specific behavior comes from
+// here rather than from a character's dimensions.
+type SizeVariant struct {
+	Size   Size
+	Width  int
+	Height int
+
+	// DefaultFPS is the frame rate of states that don't set one
+	DefaultFPS int
+
+	// Suffix is appended to character names in this variant's registry
+	// (kept for "-micro" names)
+	Suffix string
+
+	// sharedState looks up states missing from a character's own metadata
+	sharedState func(name string) (StateInfo, bool)
+}
+
+// sizeVariants holds the variants by size
+var sizeVariants = map[Size]SizeVariant{
+	SizeNano:    {Size: SizeNano, Width: 4, Height: 1, DefaultFPS: 20},
+	SizeMicro:   {Size: SizeMicro, Width: 8, Height: 2, DefaultFPS: 20, Suffix: "-micro", sharedState: microSharedState},
+	SizeRegular: {Size: SizeRegular, Width: 11, Height: 4, DefaultFPS: DefaultFPS, sharedState: regularSharedState},
+	SizeLarge:   {Size: SizeLarge, Width: 22, Height: 8, DefaultFPS: DefaultFPS},
+}
+
+// sizedCharacters holds the characters of variants other than regular and
+// micro, which keep their own maps
+var sizedCharacters = make(map[Size]map[string]LibraryCharacter)
+
+// RegisterSizeVariant adds or replaces a size variant.
+func RegisterSizeVariant(v SizeVariant) error {
+	if v.Size == "" {
+		return fmt.Errorf("size name is required")
+	}
+	if v.Width <= 0 || v.Height <= 0 {
+		return fmt.Errorf("size %q: invalid dimensions %dx%d", v.Size, v.Width, v.Height)
+	}
+	if v.DefaultFPS <= 0 {
+		v.DefaultFPS = DefaultFPS
+	}
+	if existing, ok := sizeVariants[v.Size]; ok && v.sharedState == nil {
+		v.sharedState = existing.sharedState
+	}
+	sizeVariants[v.Size] = v
+	return nil
+}
+
+// GetSizeVariant returns the variant of a size
+func GetSizeVariant(size Size) (SizeVariant, bool) {
+	v, ok := sizeVariants[size]
+	return v, ok
+}
+
+// Sizes returns every size variant from smallest to largest
+func Sizes() []SizeVariant {
+	sizes := make([]SizeVariant, 0, len(sizeVariants))
+	for _, v := range sizeVariants {
+		sizes = append(sizes, v)
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if a, b := sizes[i].Width*sizes[i].Height, sizes[j].Width*sizes[j].Height; a != b {
+			return a < b
+		}
+		return sizes[i].Size < sizes[j].Size
+	})
+	return sizes
+}
+
+// ParseSize returns the size named s
+func ParseSize(s string) (Size, error) {
+	size := Size(strings.ToLower(strings.TrimSpace(s)))
+	if _, ok := sizeVariants[size]; !ok {
+		names := make([]string, 0, len(sizeVariants))
+		for _, v := range Sizes() {
+			names = append(names, string(v.Size))
+		}
+		return "", fmt.Errorf("unknown size %q (want %s)", s, strings.Join(names, ", "))
+	}
+	return size, nil
+}
+
+// SharedState returns the playback settings the variant's shared state
+// registry has for a state
+func (v SizeVariant) SharedState(name string) (StateInfo, bool) {
+	if v.sharedState == nil {
+		return StateInfo{}, false
+	}
+	return v.sharedState(name)
+}
+
+// characterMap returns the registry of a size, nil when it has none yet.
+// The caller holds registryMu.
+func characterMap(size Size) map[string]LibraryCharacter {
+	switch size {
+	case SizeRegular:
+		return libraryCharacters
+	case SizeMicro:
+		return microCharacters
+	}
+	return sizedCharacters[size]
+}
+
+// RegisterSize adds a character to the registry of a size variant, under
+// its name plus the variant's suffix. The character takes the variant's
+// dimensions unless it has its own.
+func RegisterSize(size Size, char LibraryCharacter) error {
+	v, ok := sizeVariants[size]
+	if !ok {
+		return fmt.Errorf("unknown size %q", size)
+	}
+	if char.Name == "" {
+		return fmt.Errorf("character name is required")
+	}
+	if char.Width == 0 && char.Height == 0 {
+		char.Width, char.Height = v.Width, v.Height
+	}
+	if !strings.HasSuffix(char.Name, v.Suffix) {
+		char.Name += v.Suffix
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	chars := characterMap(size)
+	if chars == nil {
+		chars = make(map[string]LibraryCharacter)
+		sizedCharacters[size] = chars
+	}
+	chars[char.Name] = char
+	return nil
+}
+
+// GetSize retrieves the variant of a character at exactly size
+func GetSize(name string, size Size) (LibraryCharacter, error) {
+	v, ok := sizeVariants[size]
+	if !ok {
+		return LibraryCharacter{}, fmt.Errorf("unknown size %q", size)
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	chars := characterMap(size)
+	if char, ok := chars[name+v.Suffix]; ok {
+		return char, nil
+	}
+	if char, ok := chars[name]; ok {
+		return char, nil
+	}
+	return LibraryCharacter{}, fmt.Errorf("%s character %q not found", size, name)
+}
+
+// GetNearestSize retrieves the variant of a character closest to size: the
+// exact size if there is one, otherwise the variant nearest in cell count,
+// preferring the larger on ties.
+func GetNearestSize(name string, size Size) (LibraryCharacter, SizeVariant, error) {
+	want, ok := sizeVariants[size]
+	if !ok {
+		return LibraryCharacter{}, SizeVariant{}, fmt.Errorf("unknown size %q", size)
+	}
+	area := want.Width * want.Height
+
+	candidates := Sizes()
+	sort.SliceStable(candidates, func(i, j int) bool {
+		di := abs(candidates[i].Width*candidates[i].Height - area)
+		dj := abs(candidates[j].Width*candidates[j].Height - area)
+		if di != dj {
+			return di < dj
+		}
+		return candidates[i].Width*candidates[i].Height > candidates[j].Width*candidates[j].Height
+	})
+	for _, v := range candidates {
+		if char, err := GetSize(name, v.Size); err == nil {
+			return char, v, nil
+		}
+	}
+	return LibraryCharacter{}, SizeVariant{}, fmt.Errorf("library character %q not found at any size", name)
+}
+
+// ListSizes returns the sizes a character is available in, smallest first
+func ListSizes(name string) []Size {
+	var sizes []Size
+	for _, v := range Sizes() {
+		if _, err := GetSize(name, v.Size); err == nil {
+			sizes = append(sizes, v.Size)
+		}
+	}
+	return sizes
+}
+
+// regularSharedState reads the shared regular state registry
+func regularSharedState(name string) (StateInfo, bool) {
+	state, ok := stateregistry.Get(name)
+	if !ok {
+		return StateInfo{}, false
+	}
+	return StateInfo{FPS: state.FPS, NoisePool: state.NoisePool, NoiseRate: state.NoiseRate, Effects: state.Effects}, true
+}
+
+// microSharedState reads the shared micro state registry
+func microSharedState(name string) (StateInfo, bool) {
+	state := microstateregistry.GetState(name)
+	if state == nil {
+		return StateInfo{}, false
+	}
+	return StateInfo{FPS: state.FPS, NoisePool: state.NoisePool, NoiseRate: state.NoiseRate, Effects: state.Effects}, true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,140 @@
+package library
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Size
+		wantErr bool
+	}{
+		{"micro", SizeMicro, false},
+		{" Large ", SizeLarge, false},
+		{"nano", SizeNano, false},
+		{"huge", "", true},
+		{"", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseSize(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("ParseSize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSizes(t *testing.T) {
+	var got []Size
+	for _, v := range Sizes() {
+		got = append(got, v.Size)
+	}
+	want := []Size{SizeNano, SizeMicro, SizeRegular, SizeLarge}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Sizes() = %v, want %v", got, want)
+	}
+}
+
+func TestRegisterSizeVariant(t *testing.T) {
+	keepCharacters(t)
+
+	if err := RegisterSizeVariant(SizeVariant{Size: "wide", Width: 16, Height: 4}); err != nil {
+		t.Fatalf("RegisterSizeVariant() error = %v", err)
+	}
+	v, ok := GetSizeVariant("wide")
+	if !ok || v.DefaultFPS != 5 {
+		t.Errorf("GetSizeVariant(wide) = %+v, %v, want DefaultFPS 5", v, ok)
+	}
+	if _, err := ParseSize("wide"); err != nil {
+		t.Errorf("ParseSize(wide) error = %v", err)
+	}
+
+	// Replacing a built-in variant keeps its shared state registry
+	if err := RegisterSizeVariant(SizeVariant{Size: SizeMicro, Width: 8, Height: 2, DefaultFPS: 10, Suffix: "-micro"}); err != nil {
+		t.Fatalf("RegisterSizeVariant(micro) error = %v", err)
+	}
+	if v, _ := GetSizeVariant(SizeMicro); v.sharedState == nil {
+		t.Error("replacing micro dropped its shared state registry")
+	}
+
+	for _, bad := range []SizeVariant{{Width: 1, Height: 1}, {Size: "flat", Width: 4}} {
+		if err := RegisterSizeVariant(bad); err == nil {
+			t.Errorf("RegisterSizeVariant(%+v) error = nil, want error", bad)
+		}
+	}
+}
+
+func TestGetSize(t *testing.T) {
+	keepCharacters(t)
+	register(LibraryCharacter{Name: "bolt", Width: 11, Height: 4})
+	registerMicro(LibraryCharacter{Name: "bolt-micro", Width: 8, Height: 2})
+	if err := RegisterSize(SizeNano, LibraryCharacter{Name: "nut"}); err != nil {
+		t.Fatalf("RegisterSize() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		size      Size
+		wantName  string
+		wantWidth int
+		wantErr   bool
+	}{
+		{"bolt", SizeRegular, "bolt", 11, false},
+		{"bolt", SizeMicro, "bolt-micro", 8, false},
+		{"bolt-micro", SizeMicro, "bolt-micro", 8, false},
+		{"nut", SizeNano, "nut", 4, false},
+		{"bolt", SizeLarge, "", 0, true},
+		{"bolt", "huge", "", 0, true},
+	}
+
+	for _, tt := range tests {
+		char, err := GetSize(tt.name, tt.size)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetSize(%q, %s) error = %v, wantErr %v", tt.name, tt.size, err, tt.wantErr)
+			continue
+		}
+		if char.Name != tt.wantName || char.Width != tt.wantWidth {
+			t.Errorf("GetSize(%q, %s) = %s %d wide, want %s %d wide", tt.name, tt.size, char.Name, char.Width, tt.wantName, tt.wantWidth)
+		}
+	}
+}
+
+func TestGetNearestSize(t *testing.T) {
+	keepCharacters(t)
+	register(LibraryCharacter{Name: "bolt", Width: 11, Height: 4})
+	registerMicro(LibraryCharacter{Name: "bolt-micro", Width: 8, Height: 2})
+	register(LibraryCharacter{Name: "nut", Width: 11, Height: 4})
+
+	tests := []struct {
+		name     string
+		size     Size
+		wantSize Size
+		wantErr  bool
+	}{
+		{"bolt", SizeMicro, SizeMicro, false},
+		{"bolt", SizeLarge, SizeRegular, false},
+		{"bolt", SizeNano, SizeMicro, false},
+		{"nut", SizeNano, SizeRegular, false},
+		{"gear", SizeRegular, "", true},
+	}
+
+	for _, tt := range tests {
+		_, v, err := GetNearestSize(tt.name, tt.size)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("GetNearestSize(%q, %s) error = %v, wantErr %v", tt.name, tt.size, err, tt.wantErr)
+			continue
+		}
+		if v.Size != tt.wantSize {
+			t.Errorf("GetNearestSize(%q, %s) = %s, want %s", tt.name, tt.size, v.Size, tt.wantSize)
+		}
+	}
+
+	if got, want := ListSizes("bolt"), []Size{SizeMicro, SizeRegular}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ListSizes(bolt) = %v, want %v", got, want)
+	}
+}
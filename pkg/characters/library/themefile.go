@@ -0,0 +1,390 @@
+package library
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// themeFile is a theme as written in a JSON or TOML file. Inherits names a
+// theme to start from; the file's colors and palettes override its entries.
+type themeFile struct {
+	ThemeDefinition
+	Inherits string `json:"inherits,omitempty"`
+}
+
+// RegisterTheme adds a theme to the registry, replacing any theme with the
+// same name. Colors and palette entries must be hex colors.
+func RegisterTheme(theme ThemeDefinition) error {
+	if err := checkTheme(theme); err != nil {
+		return err
+	}
+	registerTheme(theme)
+	return nil
+}
+
+// checkTheme checks a theme can be registered
+func checkTheme(theme ThemeDefinition) error {
+	if theme.Name == "" {
+		return fmt.Errorf("theme name is required")
+	}
+	if len(theme.Colors) == 0 {
+		return fmt.Errorf("theme %q has no colors", theme.Name)
+	}
+	if theme.Affinity != "" && theme.Affinity != AffinityDark && theme.Affinity != AffinityLight {
+		return fmt.Errorf("theme %q: unknown affinity %q (want %q or %q)", theme.Name, theme.Affinity, AffinityDark, AffinityLight)
+	}
+	for name, hex := range theme.Colors {
+		if _, err := termcolor.ParseHex(hex); err != nil {
+			return fmt.Errorf("theme %q: %s: %w", theme.Name, name, err)
+		}
+	}
+	for name, palette := range theme.Palettes {
+		for _, hex := range palette {
+			if _, err := termcolor.ParseHex(hex); err != nil {
+				return fmt.Errorf("theme %q: %s palette: %w", theme.Name, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadThemes registers every *.json and *.toml theme file in the root of
+// fsys and returns the names of the themes loaded. A theme without a name
+// is named after its file. Themes may inherit from built-in themes or from
+// other themes in fsys.
+//
+// Files that fail to load are skipped; their errors are joined in the
+// returned error.
+func LoadThemes(fsys fs.FS) ([]string, error) {
+	themes, err := readThemes(fsys)
+	var loaded []string
+	for _, theme := range themes {
+		registerTheme(theme)
+		loaded = append(loaded, theme.Name)
+	}
+	return loaded, err
+}
+
+// readThemes reads and checks the theme files in the root of fsys without
+// registering them, parents before the themes inheriting from them. Files
+// that fail are left out and their errors joined.
+func readThemes(fsys fs.FS) ([]ThemeDefinition, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	pending := make(map[string]themeFile)
+	files := make(map[string]string) // theme name -> file name
+	for _, entry := range entries {
+		ext := path.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".json" && ext != ".toml") {
+			continue
+		}
+		file, err := readThemeFile(fsys, entry.Name())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Name(), err))
+			continue
+		}
+		if file.Name == "" {
+			file.Name = strings.TrimSuffix(entry.Name(), ext)
+		}
+		if other, dup := files[file.Name]; dup {
+			errs = append(errs, fmt.Errorf("%s: theme %q already defined in %s", entry.Name(), file.Name, other))
+			continue
+		}
+		pending[file.Name] = file
+		files[file.Name] = entry.Name()
+	}
+
+	// Resolve parents before the themes inheriting from them
+	var loaded []ThemeDefinition
+	resolved := make(map[string]ThemeDefinition)
+	resolving := make(map[string]bool)
+	var resolve func(name string) error
+	resolve = func(name string) error {
+		file, ok := pending[name]
+		if !ok {
+			return nil
+		}
+		if resolving[name] {
+			return fmt.Errorf("theme %q inherits from itself", name)
+		}
+		resolving[name] = true
+		defer delete(resolving, name)
+
+		if file.Inherits != "" && file.Inherits != name {
+			if err := resolve(file.Inherits); err != nil {
+				return err
+			}
+		}
+		delete(pending, name)
+
+		theme := file.ThemeDefinition
+		if file.Inherits != "" {
+			// A theme inheriting its own name extends the registered one
+			parent, ok := resolved[file.Inherits]
+			if !ok {
+				var err error
+				if parent, err = GetTheme(file.Inherits); err != nil {
+					return fmt.Errorf("theme %q inherits from unknown theme %q", name, file.Inherits)
+				}
+			}
+			theme = inheritTheme(parent, theme)
+		}
+		if err := checkTheme(theme); err != nil {
+			return err
+		}
+		resolved[name] = theme
+		loaded = append(loaded, theme)
+		return nil
+	}
+
+	names := make([]string, 0, len(pending))
+	for name := range pending {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := resolve(name); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", files[name], err))
+		}
+	}
+	return loaded, errors.Join(errs...)
+}
+
+// UserThemesDir returns the directory user themes are loaded from:
+// $XDG_CONFIG_HOME/tangent/themes, or ~/.config/tangent/themes.
+func UserThemesDir() string {
+	return userConfigDir("themes")
+}
+
+// userConfigDir returns a directory under $XDG_CONFIG_HOME/tangent, or ""
+// when there is no home directory to fall back on
+func userConfigDir(name string) string {
+	config := os.Getenv("XDG_CONFIG_HOME")
+	if config == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		config = filepath.Join(home, ".config")
+	}
+	return filepath.Join(config, "tangent", name)
+}
+
+// LoadUserThemes loads the themes in UserThemesDir. A missing directory is
+// not an error.
+func LoadUserThemes() ([]string, error) {
+	dir := UserThemesDir()
+	if dir == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(dir); errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return LoadThemes(os.DirFS(dir))
+}
+
+// inheritTheme overlays child on a copy of parent. A color overridden
+// without a palette also replaces the body of the parent's palette.
+func inheritTheme(parent, child ThemeDefinition) ThemeDefinition {
+	theme := ThemeDefinition{
+		Name:        child.Name,
+		Description: child.Description,
+		Affinity:    child.Affinity,
+		Colors:      make(map[string]string, len(parent.Colors)),
+		Palettes:    make(map[string][]string, len(parent.Palettes)),
+	}
+	if theme.Description == "" {
+		theme.Description = parent.Description
+	}
+	if theme.Affinity == "" {
+		theme.Affinity = parent.Affinity
+	}
+	for name, color := range parent.Colors {
+		theme.Colors[name] = color
+	}
+	for name, palette := range parent.Palettes {
+		theme.Palettes[name] = append([]string(nil), palette...)
+	}
+	for name, color := range child.Colors {
+		theme.Colors[name] = color
+		if palette, ok := theme.Palettes[name]; ok && len(palette) > 0 {
+			palette[0] = color
+		}
+	}
+	for name, palette := range child.Palettes {
+		theme.Palettes[name] = palette
+	}
+	return theme
+}
+
+// readThemeFile decodes one JSON or TOML theme file
+func readThemeFile(fsys fs.FS, name string) (themeFile, error) {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return themeFile{}, err
+	}
+	if path.Ext(name) == ".toml" {
+		table, err := parseThemeTOML(string(data))
+		if err != nil {
+			return themeFile{}, err
+		}
+		// Round-trip through JSON so both formats share one set of field names
+		if data, err = json.Marshal(table); err != nil {
+			return themeFile{}, err
+		}
+	}
+
+	var file themeFile
+	dec := json.NewDecoder(strings.NewReader(string(data)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&file); err != nil {
+		return themeFile{}, err
+	}
+	return file, nil
+}
+
+// parseThemeTOML parses the subset of TOML theme files use: comments,
+// [tables], and keys set to strings or arrays of strings.
+//
+//	name = "ocean"
+//	inherits = "latte"
+//
+//	[colors]
+//	sam = "#4FA3FF"
+//
+//	[palettes]
+//	sam = ["#4FA3FF", "#1B2A3A", "#E8F4FF"]
+func parseThemeTOML(src string) (map[string]any, error) {
+	root := make(map[string]any)
+	table := root
+	for i, line := range strings.Split(src, "\n") {
+		lineNo := i + 1
+		line = strings.TrimSpace(stripTOMLComment(line))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: unsupported table header %q", lineNo, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if _, exists := root[name]; exists {
+				return nil, fmt.Errorf("line %d: table %q defined twice", lineNo, name)
+			}
+			table = make(map[string]any)
+			root[name] = table
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected key = value", lineNo)
+		}
+		key = strings.TrimSpace(key)
+		if unquoted, err := parseTOMLString(key); err == nil {
+			key = unquoted
+		}
+		if key == "" {
+			return nil, fmt.Errorf("line %d: empty key", lineNo)
+		}
+		if _, exists := table[key]; exists {
+			return nil, fmt.Errorf("line %d: key %q defined twice", lineNo, key)
+		}
+
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "[") {
+			if !strings.HasSuffix(value, "]") {
+				return nil, fmt.Errorf("line %d: arrays must be on one line", lineNo)
+			}
+			items := []string{}
+			for _, item := range splitTOMLArray(value[1 : len(value)-1]) {
+				s, err := parseTOMLString(item)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", lineNo, err)
+				}
+				items = append(items, s)
+			}
+			table[key] = items
+			continue
+		}
+		s, err := parseTOMLString(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNo, err)
+		}
+		table[key] = s
+	}
+	return root, nil
+}
+
+// parseTOMLString parses a basic ("...") or literal ('...') string
+func parseTOMLString(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return strconv.Unquote(s)
+	}
+	return "", fmt.Errorf("expected a quoted string, got %q", s)
+}
+
+// stripTOMLComment removes a # comment outside of strings
+func stripTOMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitTOMLArray splits the items of a one-line array on commas outside of
+// strings, dropping a trailing comma
+func splitTOMLArray(s string) []string {
+	var items []string
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ',':
+			items = append(items, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" {
+		items = append(items, last)
+	}
+	return items
+}
@@ -0,0 +1,207 @@
+package library
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+)
+
+// keepThemes restores the theme registry when the test ends
+func keepThemes(t *testing.T) {
+	saved := make(map[string]ThemeDefinition, len(themeRegistry))
+	for name, theme := range themeRegistry {
+		saved[name] = theme
+	}
+	t.Cleanup(func() { themeRegistry = saved })
+}
+
+func TestRegisterTheme(t *testing.T) {
+	keepThemes(t)
+
+	tests := []struct {
+		name    string
+		theme   ThemeDefinition
+		wantErr bool
+	}{
+		{"valid", ThemeDefinition{Name: "mine", Colors: map[string]string{"sam": "#112233"}}, false},
+		{"missing name", ThemeDefinition{Colors: map[string]string{"sam": "#112233"}}, true},
+		{"no colors", ThemeDefinition{Name: "mine"}, true},
+		{"bad color", ThemeDefinition{Name: "mine", Colors: map[string]string{"sam": "blue"}}, true},
+		{"bad palette", ThemeDefinition{Name: "mine", Colors: map[string]string{"sam": "#112233"}, Palettes: map[string][]string{"sam": {"#112233", "x"}}}, true},
+		{"bad affinity", ThemeDefinition{Name: "mine", Affinity: "dim", Colors: map[string]string{"sam": "#112233"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RegisterTheme(tt.theme)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RegisterTheme() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := GetTheme("mine"); err != nil {
+		t.Errorf("GetTheme(mine) error = %v", err)
+	}
+}
+
+func TestRegisterThemeConcurrent(t *testing.T) {
+	keepThemes(t)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			RegisterTheme(ThemeDefinition{Name: fmt.Sprintf("t%d", i), Colors: map[string]string{"sam": "#FF0000"}})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			GetTheme("latte")
+			ListThemes()
+		}
+	}()
+	wg.Wait()
+}
+
+func TestLoadThemes(t *testing.T) {
+	keepThemes(t)
+	latte, _ := GetTheme("latte")
+
+	fsys := fstest.MapFS{
+		"ocean.json": {Data: []byte(`{
+  "affinity": "dark",
+  "colors": {"sam": "#4FA3FF", "rio": "#3DDC84"}
+}`)},
+		"mocha.toml": {Data: []byte(`# A darker latte
+name = "mocha"
+inherits = "latte"
+description = "Latte with a darker sam"
+
+[colors]
+sam = "#7A4E2D"  # roasted
+
+[palettes]
+rio = ['#112233', "#445566", "#778899",]
+`)},
+		"deep.json":   {Data: []byte(`{"name": "deep", "inherits": "ocean", "colors": {"rio": "#0B6E4F"}}`)},
+		"notes.txt":   {Data: []byte("not a theme")},
+		"broken.json": {Data: []byte(`{"name": "broken", "colours": {}}`)},
+	}
+
+	loaded, err := LoadThemes(fsys)
+	if err == nil || !strings.Contains(err.Error(), "broken.json") {
+		t.Errorf("LoadThemes() error = %v, want broken.json error", err)
+	}
+	if got, want := strings.Join(loaded, ","), "ocean,deep,mocha"; got != want {
+		t.Errorf("LoadThemes() loaded = %s, want %s", got, want)
+	}
+
+	ocean, _ := GetTheme("ocean")
+	if ocean.Colors["sam"] != "#4FA3FF" || ocean.Affinity != AffinityDark {
+		t.Errorf("ocean = %+v, want name from file and its colors", ocean)
+	}
+
+	mocha, _ := GetTheme("mocha")
+	if mocha.Colors["sam"] != "#7A4E2D" {
+		t.Errorf("mocha sam = %s, want override #7A4E2D", mocha.Colors["sam"])
+	}
+	if mocha.Colors["ga"] != latte.Colors["ga"] {
+		t.Errorf("mocha ga = %s, want inherited %s", mocha.Colors["ga"], latte.Colors["ga"])
+	}
+	if mocha.Affinity != latte.Affinity || mocha.Description != "Latte with a darker sam" {
+		t.Errorf("mocha affinity/description = %q/%q", mocha.Affinity, mocha.Description)
+	}
+	if p := mocha.Palettes["sam"]; len(p) > 0 && p[0] != "#7A4E2D" {
+		t.Errorf("mocha sam palette body = %s, want overridden color", p[0])
+	}
+	if p := mocha.Palettes["rio"]; strings.Join(p, ",") != "#112233,#445566,#778899" {
+		t.Errorf("mocha rio palette = %v", p)
+	}
+	if latte.Colors["sam"] == "#7A4E2D" || (len(latte.Palettes["sam"]) > 0 && latte.Palettes["sam"][0] == "#7A4E2D") {
+		t.Error("inheriting modified the parent theme")
+	}
+
+	deep, _ := GetTheme("deep")
+	if deep.Colors["sam"] != "#4FA3FF" || deep.Colors["rio"] != "#0B6E4F" {
+		t.Errorf("deep colors = %v, want sam from ocean and rio override", deep.Colors)
+	}
+}
+
+func TestLoadThemesInheritanceErrors(t *testing.T) {
+	keepThemes(t)
+
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+		want string
+	}{
+		{
+			name: "unknown parent",
+			fsys: fstest.MapFS{"a.json": {Data: []byte(`{"inherits": "nope"}`)}},
+			want: "unknown theme",
+		},
+		{
+			name: "cycle",
+			fsys: fstest.MapFS{
+				"a.json": {Data: []byte(`{"inherits": "b"}`)},
+				"b.json": {Data: []byte(`{"inherits": "a"}`)},
+			},
+			want: "inherits from itself",
+		},
+		{
+			name: "duplicate name",
+			fsys: fstest.MapFS{
+				"a.json": {Data: []byte(`{"name": "x", "colors": {"sam": "#000000"}}`)},
+				"b.toml": {Data: []byte("name = \"x\"\n[colors]\nsam = \"#FFFFFF\"\n")},
+			},
+			want: "already defined",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadThemes(tt.fsys)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("LoadThemes() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseThemeTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"missing equals", "name"},
+		{"unquoted value", "name = ocean"},
+		{"multi-line array", "[palettes]\nsam = [\"#000000\","},
+		{"array of tables", "[[colors]]"},
+		{"duplicate key", "name = \"a\"\nname = \"b\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseThemeTOML(tt.src); err == nil {
+				t.Errorf("parseThemeTOML(%q) error = nil, want error", tt.src)
+			}
+		})
+	}
+}
+
+func TestUserThemesDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+	if got, want := UserThemesDir(), "/tmp/config/tangent/themes"; got != want {
+		t.Errorf("UserThemesDir() = %s, want %s", got, want)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir()+"/missing")
+	if loaded, err := LoadUserThemes(); err != nil || len(loaded) != 0 {
+		t.Errorf("LoadUserThemes() = %v, %v, want nothing for a missing directory", loaded, err)
+	}
+}
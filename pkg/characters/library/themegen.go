@@ -0,0 +1,231 @@
+package library
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// Harmony rules for generated themes
+const (
+	HarmonyEven      = "even"      // Hues evenly spaced around the wheel
+	HarmonyAnalogous = "analogous" // Hues within 120 degrees of the base
+	HarmonyTriadic   = "triadic"   // Hues clustered around three anchors 120 degrees apart
+)
+
+// ThemeOptions describes a theme to generate. Zero values use defaults.
+type ThemeOptions struct {
+	Name        string
+	Description string
+
+	// Seed colors are assigned to the characters in order and kept as they
+	// are (apart from contrast fixes). The base hue, lightness and chroma
+	// default to those of the first seed.
+	Seed []string
+
+	BaseHue   float64 // OKLCH hue in degrees
+	Harmony   string  // HarmonyEven (default), HarmonyAnalogous or HarmonyTriadic
+	Affinity  string  // AffinityDark (default) or AffinityLight
+	Lightness float64 // OKLCH lightness of generated colors (default by affinity)
+	Chroma    float64 // OKLCH chroma of generated colors (default by affinity)
+
+	// Characters to color, in order; default every registered character
+	Characters []string
+
+	MinContrast float64 // Default DefaultMinContrast
+	MinDistance float64 // Default DefaultMinDistance
+}
+
+// GenerateTheme builds a theme for every character from a seed palette or
+// base hue. Hues follow the harmony rule and colors are adjusted to the
+// minimum contrast on the affinity's background. Each generated color then
+// takes the lightness closest to the target that keeps it distinguishable
+// from the colors before it, under normal and simulated colorblind vision.
+//
+// If the rules can't be met the theme is returned along with an error
+// listing the remaining issues.
+func GenerateTheme(opts ThemeOptions) (ThemeDefinition, error) {
+	if opts.Name == "" {
+		return ThemeDefinition{}, fmt.Errorf("theme name is required")
+	}
+	if opts.Harmony == "" {
+		opts.Harmony = HarmonyEven
+	}
+	if opts.Harmony != HarmonyEven && opts.Harmony != HarmonyAnalogous && opts.Harmony != HarmonyTriadic {
+		return ThemeDefinition{}, fmt.Errorf("unknown harmony %q (want %q, %q or %q)", opts.Harmony, HarmonyEven, HarmonyAnalogous, HarmonyTriadic)
+	}
+	if opts.Affinity == "" {
+		opts.Affinity = AffinityDark
+	}
+	if opts.Affinity != AffinityDark && opts.Affinity != AffinityLight {
+		return ThemeDefinition{}, fmt.Errorf("unknown affinity %q (want %q or %q)", opts.Affinity, AffinityDark, AffinityLight)
+	}
+	if len(opts.Characters) == 0 {
+		opts.Characters = List()
+		sort.Slice(opts.Characters, func(i, j int) bool {
+			return characterOrder(opts.Characters[i]) < characterOrder(opts.Characters[j])
+		})
+	}
+	if len(opts.Seed) > len(opts.Characters) {
+		return ThemeDefinition{}, fmt.Errorf("%d seed colors for %d characters", len(opts.Seed), len(opts.Characters))
+	}
+
+	seeds := make([]termcolor.RGB, len(opts.Seed))
+	for i, hex := range opts.Seed {
+		c, err := termcolor.ParseHex(hex)
+		if err != nil {
+			return ThemeDefinition{}, fmt.Errorf("seed %d: %w", i, err)
+		}
+		seeds[i] = c
+	}
+
+	// Lightness and chroma that read well on the affinity's background, and
+	// the lightness band generated colors stay in
+	lightness, chroma := 0.78, 0.12
+	lo, hi := 0.6, 0.95
+	if opts.Affinity == AffinityLight {
+		lightness, chroma = 0.52, 0.14
+		lo, hi = 0.3, 0.62
+	}
+	if len(seeds) > 0 {
+		l, c, h := seeds[0].OKLCH()
+		lightness, chroma, opts.BaseHue = l, c, h
+	}
+	if opts.Lightness > 0 {
+		lightness = opts.Lightness
+	}
+	if opts.Chroma > 0 {
+		chroma = opts.Chroma
+	}
+
+	bg := termcolor.DarkBackground
+	for _, b := range DefaultBackgrounds {
+		if b.Affinity == opts.Affinity {
+			bg, _ = termcolor.ParseHex(b.Color)
+		}
+	}
+	minContrast := opts.MinContrast
+	if minContrast <= 0 {
+		minContrast = DefaultMinContrast
+	}
+
+	minDistance := opts.MinDistance
+	if minDistance <= 0 {
+		minDistance = DefaultMinDistance
+	}
+
+	// Candidate lightness levels, nearest the target first
+	var levels []float64
+	for l := lo; l <= hi+1e-9; l += 0.025 {
+		levels = append(levels, l)
+	}
+	sort.SliceStable(levels, func(i, j int) bool {
+		return math.Abs(levels[i]-lightness) < math.Abs(levels[j]-lightness)
+	})
+
+	hues := harmonyHues(opts.Harmony, opts.BaseHue, len(opts.Characters))
+	eyes, accent := themeSlots(opts.Affinity, opts.BaseHue)
+	theme := ThemeDefinition{
+		Name:        opts.Name,
+		Description: opts.Description,
+		Affinity:    opts.Affinity,
+		Colors:      make(map[string]string, len(opts.Characters)),
+		Palettes:    make(map[string][]string, len(opts.Characters)),
+	}
+
+	var placed []termcolor.RGB
+	for i, name := range opts.Characters {
+		var c termcolor.RGB
+		if i < len(seeds) {
+			c = termcolor.EnsureContrast(seeds[i], bg, minContrast)
+		} else {
+			// The level nearest the target that keeps this color apart from
+			// every placed one, or failing that the most distinct level
+			bestScore := -1.0
+			for _, l := range levels {
+				candidate := termcolor.EnsureContrast(termcolor.FromOKLCH(l, chroma, hues[i]), bg, minContrast)
+				score := nearestDistance(candidate, placed)
+				if score > bestScore {
+					c, bestScore = candidate, score
+				}
+				if score >= minDistance*1.25 {
+					c = candidate
+					break
+				}
+			}
+		}
+		placed = append(placed, c)
+		theme.Colors[name] = c.Hex()
+		theme.Palettes[name] = []string{c.Hex(), eyes, accent}
+	}
+
+	report := ValidateTheme(theme, ValidationOptions{MinContrast: minContrast, MinDistance: minDistance})
+	if report.OK() {
+		return theme, nil
+	}
+	messages := make([]string, len(report.Issues))
+	for i, issue := range report.Issues {
+		messages[i] = issue.Message
+	}
+	return theme, fmt.Errorf("theme %q does not meet the rules: %s", opts.Name, strings.Join(messages, "; "))
+}
+
+// nearestDistance returns the smallest distance from c to any of colors,
+// under normal vision and every simulated deficiency
+func nearestDistance(c termcolor.RGB, colors []termcolor.RGB) float64 {
+	nearest := math.Inf(1)
+	visions := append([]termcolor.Deficiency{0}, termcolor.Deficiencies...)
+	for _, other := range colors {
+		for _, d := range visions {
+			nearest = math.Min(nearest, termcolor.Distance(c.Simulate(d), other.Simulate(d)))
+		}
+	}
+	return nearest
+}
+
+// harmonyHues returns one hue per character
+func harmonyHues(harmony string, base float64, n int) []float64 {
+	hues := make([]float64, n)
+	for i := range hues {
+		switch harmony {
+		case HarmonyAnalogous:
+			if n > 1 {
+				hues[i] = base - 60 + 120*float64(i)/float64(n-1)
+			} else {
+				hues[i] = base
+			}
+		case HarmonyTriadic:
+			// Round-robin over the anchors, fanning out 20 degrees per round
+			round := i / 3
+			offset := float64((round+1)/2) * 20
+			if round%2 == 1 {
+				offset = -offset
+			}
+			hues[i] = base + float64(i%3)*120 + offset
+		default:
+			hues[i] = base + 360*float64(i)/float64(n)
+		}
+		hues[i] = math.Mod(math.Mod(hues[i], 360)+360, 360)
+	}
+	return hues
+}
+
+// themeSlots picks eyes and accent colors for a generated theme: dark
+// tinted pupils and a pale complementary highlight
+func themeSlots(affinity string, hue float64) (eyes, accent string) {
+	eyes = termcolor.FromOKLCH(0.25, 0.03, hue).Hex()
+	accent = termcolor.FromOKLCH(0.94, 0.04, hue+180).Hex()
+	if affinity == AffinityLight {
+		accent = termcolor.FromOKLCH(0.98, 0.02, hue+180).Hex()
+	}
+	return eyes, accent
+}
+
+// ThemeJSON encodes a theme in the JSON format read at runtime.
+func ThemeJSON(theme ThemeDefinition) ([]byte, error) {
+	return json.MarshalIndent(theme, "", "  ")
+}
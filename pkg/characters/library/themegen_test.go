@@ -0,0 +1,119 @@
+package library
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGenerateTheme(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ThemeOptions
+	}{
+		{"even dark", ThemeOptions{Harmony: HarmonyEven, BaseHue: 20}},
+		{"even light", ThemeOptions{Harmony: HarmonyEven, BaseHue: 200, Affinity: AffinityLight}},
+		{"analogous", ThemeOptions{Harmony: HarmonyAnalogous, BaseHue: 0}},
+		{"triadic", ThemeOptions{Harmony: HarmonyTriadic, BaseHue: 90, Affinity: AffinityLight}},
+		{"seeded", ThemeOptions{Seed: []string{Theme2ColorSa, Theme2ColorPa}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Name = "generated"
+			tt.opts.Characters = AllCharacterNames()
+			theme, err := GenerateTheme(tt.opts)
+			if err != nil {
+				t.Fatalf("GenerateTheme() error = %v", err)
+			}
+			if len(theme.Colors) != len(AllCharacterNames()) {
+				t.Errorf("len(Colors) = %d, want %d", len(theme.Colors), len(AllCharacterNames()))
+			}
+			for _, name := range AllCharacterNames() {
+				if len(theme.Palettes[name]) != 3 || theme.Palettes[name][0] != theme.Colors[name] {
+					t.Errorf("palette for %s = %v, want body, eyes, accent", name, theme.Palettes[name])
+				}
+			}
+			if report := ValidateTheme(theme, ValidationOptions{}); !report.OK() {
+				t.Errorf("generated theme has issues: %+v", report.Issues)
+			}
+		})
+	}
+}
+
+func TestGenerateThemeKeepsSeeds(t *testing.T) {
+	theme, err := GenerateTheme(ThemeOptions{
+		Name:       "seeded",
+		Characters: AllCharacterNames(),
+		Seed:       []string{"#E78284", "#85C1DC"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateTheme() error = %v", err)
+	}
+	if theme.Colors[CharacterSa] != "#E78284" || theme.Colors[CharacterRi] != "#85C1DC" {
+		t.Errorf("seeded colors = %s, %s, want #E78284, #85C1DC", theme.Colors[CharacterSa], theme.Colors[CharacterRi])
+	}
+}
+
+func TestGenerateThemeErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ThemeOptions
+	}{
+		{"no name", ThemeOptions{}},
+		{"bad harmony", ThemeOptions{Name: "x", Harmony: "tetradic"}},
+		{"bad affinity", ThemeOptions{Name: "x", Affinity: "dim"}},
+		{"bad seed", ThemeOptions{Name: "x", Seed: []string{"red"}}},
+		{"too many seeds", ThemeOptions{Name: "x", Characters: []string{CharacterSa}, Seed: []string{"#FF0000", "#00FF00"}}},
+		{"unreachable distance", ThemeOptions{Name: "x", Characters: AllCharacterNames(), MinDistance: 0.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := GenerateTheme(tt.opts); err == nil {
+				t.Error("GenerateTheme() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestHarmonyHues(t *testing.T) {
+	tests := []struct {
+		harmony string
+		want    []float64
+	}{
+		{HarmonyEven, []float64{10, 130, 250}},
+		{HarmonyAnalogous, []float64{310, 10, 70}},
+		{HarmonyTriadic, []float64{10, 130, 250}},
+	}
+	for _, tt := range tests {
+		got := harmonyHues(tt.harmony, 10, 3)
+		for i := range tt.want {
+			if got[i] != tt.want[i] {
+				t.Errorf("harmonyHues(%q) = %v, want %v", tt.harmony, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestThemeJSONRoundTrip(t *testing.T) {
+	theme, err := GetTheme("garden")
+	if err != nil {
+		t.Fatalf("GetTheme() error = %v", err)
+	}
+	data, err := ThemeJSON(theme)
+	if err != nil {
+		t.Fatalf("ThemeJSON() error = %v", err)
+	}
+
+	var decoded ThemeDefinition
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if decoded.Name != "garden" || decoded.Affinity != AffinityDark || decoded.Colors[CharacterSa] != Theme3ColorSa {
+		t.Errorf("decoded = %+v, want the garden theme", decoded)
+	}
+	if got := decoded.Palettes[CharacterSa]; len(got) != 3 || got[1] != Theme3Eyes {
+		t.Errorf("decoded palette = %v", got)
+	}
+}
@@ -5,11 +5,19 @@ import (
 	"sort"
 )
 
+// Background affinities of a theme
+const (
+	AffinityLight = "light"
+	AffinityDark  = "dark"
+)
+
 // ThemeDefinition represents a color theme for characters
 type ThemeDefinition struct {
-	Name        string
-	Description string
-	Colors      map[string]string // character name -> hex color
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Affinity    string              `json:"affinity,omitempty"` // AffinityLight, AffinityDark or "" for either
+	Colors      map[string]string   `json:"colors"`             // character name -> hex color
+	Palettes    map[string][]string `json:"palettes,omitempty"` // character name -> body, eyes, accent (optional)
 }
 
 // Theme registry (private)
@@ -17,12 +25,16 @@ var themeRegistry = make(map[string]ThemeDefinition)
 
 // registerTheme adds a theme to the registry (private)
 func registerTheme(theme ThemeDefinition) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	themeRegistry[theme.Name] = theme
 }
 
 // GetTheme returns a theme by name
 func GetTheme(name string) (ThemeDefinition, error) {
+	registryMu.RLock()
 	theme, exists := themeRegistry[name]
+	registryMu.RUnlock()
 	if !exists {
 		return ThemeDefinition{}, fmt.Errorf("theme %q not found", name)
 	}
@@ -31,6 +43,8 @@ func GetTheme(name string) (ThemeDefinition, error) {
 
 // ListThemes returns all available theme names in sorted order
 func ListThemes() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
 	names := make([]string, 0, len(themeRegistry))
 	for name := range themeRegistry {
 		names = append(names, name)
@@ -48,4 +62,18 @@ func (t ThemeDefinition) GetColor(characterName string) (string, error) {
 	return color, nil
 }
 
+// GetPalette returns the palette for a character in this theme, ordered
+// body, eyes, accent. Characters without a palette get a one-entry palette
+// holding their theme color.
+func (t ThemeDefinition) GetPalette(characterName string) ([]string, error) {
+	if palette, ok := t.Palettes[characterName]; ok && len(palette) > 0 {
+		return palette, nil
+	}
+	color, err := t.GetColor(characterName)
+	if err != nil {
+		return nil, err
+	}
+	return []string{color}, nil
+}
+
 // Theme initialization is now in themes_generated.go (auto-generated from constants.go)
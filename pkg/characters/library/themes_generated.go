@@ -8,6 +8,7 @@ func init() {
 	registerTheme(ThemeDefinition{
 		Name:        "bright",
 		Description: "100% saturation - high impact, maximum distinction",
+		Affinity:    AffinityDark,
 		Colors: map[string]string{
 			CharacterDa: Theme1ColorDa,
 			CharacterGa: Theme1ColorGa,
@@ -17,12 +18,22 @@ func init() {
 			CharacterRi: Theme1ColorRi,
 			CharacterSa: Theme1ColorSa,
 		},
+		Palettes: map[string][]string{
+			CharacterDa: {Theme1ColorDa, Theme1Eyes, Theme1Accent},
+			CharacterGa: {Theme1ColorGa, Theme1Eyes, Theme1Accent},
+			CharacterMa: {Theme1ColorMa, Theme1Eyes, Theme1Accent},
+			CharacterNi: {Theme1ColorNi, Theme1Eyes, Theme1Accent},
+			CharacterPa: {Theme1ColorPa, Theme1Eyes, Theme1Accent},
+			CharacterRi: {Theme1ColorRi, Theme1Eyes, Theme1Accent},
+			CharacterSa: {Theme1ColorSa, Theme1Eyes, Theme1Accent},
+		},
 	})
 
 	// Theme 2: Latte
 	registerTheme(ThemeDefinition{
 		Name:        "latte",
 		Description: "Catppuccin-inspired - GUI user friendly, warm pastels",
+		Affinity:    AffinityDark,
 		Colors: map[string]string{
 			CharacterDa: Theme2ColorDa,
 			CharacterGa: Theme2ColorGa,
@@ -32,12 +43,22 @@ func init() {
 			CharacterRi: Theme2ColorRi,
 			CharacterSa: Theme2ColorSa,
 		},
+		Palettes: map[string][]string{
+			CharacterDa: {Theme2ColorDa, Theme2Eyes, Theme2Accent},
+			CharacterGa: {Theme2ColorGa, Theme2Eyes, Theme2Accent},
+			CharacterMa: {Theme2ColorMa, Theme2Eyes, Theme2Accent},
+			CharacterNi: {Theme2ColorNi, Theme2Eyes, Theme2Accent},
+			CharacterPa: {Theme2ColorPa, Theme2Eyes, Theme2Accent},
+			CharacterRi: {Theme2ColorRi, Theme2Eyes, Theme2Accent},
+			CharacterSa: {Theme2ColorSa, Theme2Eyes, Theme2Accent},
+		},
 	})
 
 	// Theme 3: Garden
 	registerTheme(ThemeDefinition{
 		Name:        "garden",
 		Description: "Earthy natural - reduces terminal intimidation",
+		Affinity:    AffinityDark,
 		Colors: map[string]string{
 			CharacterDa: Theme3ColorDa,
 			CharacterGa: Theme3ColorGa,
@@ -47,12 +68,22 @@ func init() {
 			CharacterRi: Theme3ColorRi,
 			CharacterSa: Theme3ColorSa,
 		},
+		Palettes: map[string][]string{
+			CharacterDa: {Theme3ColorDa, Theme3Eyes, Theme3Accent},
+			CharacterGa: {Theme3ColorGa, Theme3Eyes, Theme3Accent},
+			CharacterMa: {Theme3ColorMa, Theme3Eyes, Theme3Accent},
+			CharacterNi: {Theme3ColorNi, Theme3Eyes, Theme3Accent},
+			CharacterPa: {Theme3ColorPa, Theme3Eyes, Theme3Accent},
+			CharacterRi: {Theme3ColorRi, Theme3Eyes, Theme3Accent},
+			CharacterSa: {Theme3ColorSa, Theme3Eyes, Theme3Accent},
+		},
 	})
 
 	// Theme 4: Cozy
 	registerTheme(ThemeDefinition{
 		Name:        "cozy",
 		Description: "Modern GUI hybrid - professional warmth",
+		Affinity:    AffinityDark,
 		Colors: map[string]string{
 			CharacterDa: Theme4ColorDa,
 			CharacterGa: Theme4ColorGa,
@@ -62,5 +93,14 @@ func init() {
 			CharacterRi: Theme4ColorRi,
 			CharacterSa: Theme4ColorSa,
 		},
+		Palettes: map[string][]string{
+			CharacterDa: {Theme4ColorDa, Theme4Eyes, Theme4Accent},
+			CharacterGa: {Theme4ColorGa, Theme4Eyes, Theme4Accent},
+			CharacterMa: {Theme4ColorMa, Theme4Eyes, Theme4Accent},
+			CharacterNi: {Theme4ColorNi, Theme4Eyes, Theme4Accent},
+			CharacterPa: {Theme4ColorPa, Theme4Eyes, Theme4Accent},
+			CharacterRi: {Theme4ColorRi, Theme4Eyes, Theme4Accent},
+			CharacterSa: {Theme4ColorSa, Theme4Eyes, Theme4Accent},
+		},
 	})
 }
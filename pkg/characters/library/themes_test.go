@@ -207,3 +207,61 @@ func TestLatteThemeColors(t *testing.T) {
 		}
 	}
 }
+
+func TestGetPalette(t *testing.T) {
+	theme, err := GetTheme("latte")
+	if err != nil {
+		t.Fatalf("Failed to get latte theme: %v", err)
+	}
+
+	palette, err := theme.GetPalette(CharacterSa)
+	if err != nil {
+		t.Fatalf("GetPalette() error = %v", err)
+	}
+	want := []string{Theme2ColorSa, Theme2Eyes, Theme2Accent}
+	if len(palette) != len(want) {
+		t.Fatalf("GetPalette() = %v, want %v", palette, want)
+	}
+	for i := range want {
+		if palette[i] != want[i] {
+			t.Errorf("palette[%d] = %v, want %v", i, palette[i], want[i])
+		}
+	}
+
+	if _, err := theme.GetPalette("unknown"); err == nil {
+		t.Error("GetPalette() for unknown character should return error")
+	}
+}
+
+func TestGetPaletteSingleColor(t *testing.T) {
+	theme := ThemeDefinition{
+		Name:   "mono",
+		Colors: map[string]string{CharacterSa: "#123456"},
+	}
+
+	palette, err := theme.GetPalette(CharacterSa)
+	if err != nil {
+		t.Fatalf("GetPalette() error = %v", err)
+	}
+	if len(palette) != 1 || palette[0] != "#123456" {
+		t.Errorf("GetPalette() = %v, want single body color", palette)
+	}
+}
+
+func TestThemeAffinity(t *testing.T) {
+	want := map[string]string{
+		"bright": AffinityDark,
+		"latte":  AffinityDark,
+		"garden": AffinityDark,
+		"cozy":   AffinityDark,
+	}
+	for name, affinity := range want {
+		theme, err := GetTheme(name)
+		if err != nil {
+			t.Fatalf("GetTheme(%q) error = %v", name, err)
+		}
+		if theme.Affinity != affinity {
+			t.Errorf("theme %q affinity = %q, want %q", name, theme.Affinity, affinity)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package library
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// Validation defaults
+const (
+	// DefaultMinContrast is the WCAG minimum contrast for graphics (1.4.11)
+	DefaultMinContrast = 3.0
+	// DefaultMinDistance is the smallest OKLab distance at which two
+	// characters still read as different colors at a glance
+	DefaultMinDistance = 0.04
+)
+
+// Background is a terminal background a theme is checked against.
+type Background struct {
+	Name     string // "dark" or "light"
+	Color    string // Hex color
+	Affinity string // Themes with this affinity must meet the contrast minimum
+}
+
+// DefaultBackgrounds are the typical dark and light terminal backgrounds.
+var DefaultBackgrounds = []Background{
+	{Name: "dark", Color: "#1E1E1E", Affinity: AffinityDark},
+	{Name: "light", Color: "#FFFFFF", Affinity: AffinityLight},
+}
+
+// ValidationOptions configures ValidateTheme. Zero values use the defaults.
+type ValidationOptions struct {
+	MinContrast float64
+	MinDistance float64
+	Backgrounds []Background
+}
+
+// ContrastResult is the contrast of one character's color on one background.
+type ContrastResult struct {
+	Character  string
+	Color      string
+	Background string
+	Ratio      float64
+}
+
+// Issue kinds reported by ValidateTheme
+const (
+	IssueContrast = "contrast"
+	IssueConfused = "confused"
+	IssueColor    = "color"
+)
+
+// ThemeIssue is one problem found in a theme.
+type ThemeIssue struct {
+	Kind       string   // IssueContrast, IssueConfused or IssueColor
+	Characters []string // The character, or the pair that can't be told apart
+	Background string   // Background name (contrast issues)
+	Vision     string   // "normal" or a deficiency name (confusion issues)
+	Value      float64  // Contrast ratio or color distance
+	Message    string
+}
+
+// ThemeReport is the result of validating one theme.
+type ThemeReport struct {
+	Theme    string
+	Contrast []ContrastResult
+	Issues   []ThemeIssue
+}
+
+// OK reports whether the theme passed every check
+func (r ThemeReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// ValidateTheme checks a theme's character colors for accessibility:
+//   - WCAG contrast against each background. Every background is reported;
+//     only those matching the theme's affinity (all of them for themes
+//     without one) raise issues below MinContrast.
+//   - Distinguishability: every pair of characters is compared with normal
+//     vision and simulated protanopia, deuteranopia and tritanopia, and
+//     pairs closer than MinDistance are flagged.
+func ValidateTheme(theme ThemeDefinition, opts ValidationOptions) ThemeReport {
+	if opts.MinContrast <= 0 {
+		opts.MinContrast = DefaultMinContrast
+	}
+	if opts.MinDistance <= 0 {
+		opts.MinDistance = DefaultMinDistance
+	}
+	if len(opts.Backgrounds) == 0 {
+		opts.Backgrounds = DefaultBackgrounds
+	}
+
+	report := ThemeReport{Theme: theme.Name}
+
+	names := make([]string, 0, len(theme.Colors))
+	colors := make(map[string]termcolor.RGB, len(theme.Colors))
+	for name, hex := range theme.Colors {
+		c, err := termcolor.ParseHex(hex)
+		if err != nil {
+			report.Issues = append(report.Issues, ThemeIssue{
+				Kind:       IssueColor,
+				Characters: []string{name},
+				Message:    fmt.Sprintf("%s: %v", name, err),
+			})
+			continue
+		}
+		names = append(names, name)
+		colors[name] = c
+	}
+	sort.Slice(names, func(i, j int) bool { return characterOrder(names[i]) < characterOrder(names[j]) })
+
+	for _, bg := range opts.Backgrounds {
+		bgColor, err := termcolor.ParseHex(bg.Color)
+		if err != nil {
+			continue
+		}
+		enforce := theme.Affinity == "" || bg.Affinity == "" || bg.Affinity == theme.Affinity
+		for _, name := range names {
+			ratio := termcolor.ContrastRatio(colors[name], bgColor)
+			report.Contrast = append(report.Contrast, ContrastResult{
+				Character:  name,
+				Color:      theme.Colors[name],
+				Background: bg.Name,
+				Ratio:      ratio,
+			})
+			if enforce && ratio < opts.MinContrast {
+				report.Issues = append(report.Issues, ThemeIssue{
+					Kind:       IssueContrast,
+					Characters: []string{name},
+					Background: bg.Name,
+					Value:      ratio,
+					Message: fmt.Sprintf("%s %s has contrast %.2f:1 on %s background %s (want %.1f:1)",
+						name, theme.Colors[name], ratio, bg.Name, bg.Color, opts.MinContrast),
+				})
+			}
+		}
+	}
+
+	visions := append([]termcolor.Deficiency{0}, termcolor.Deficiencies...)
+	for _, d := range visions {
+		vision, with := "normal", "normal vision"
+		if d != 0 {
+			vision, with = d.String(), d.String()
+		}
+		for i, a := range names {
+			for _, b := range names[i+1:] {
+				dist := termcolor.Distance(colors[a].Simulate(d), colors[b].Simulate(d))
+				if dist >= opts.MinDistance {
+					continue
+				}
+				report.Issues = append(report.Issues, ThemeIssue{
+					Kind:       IssueConfused,
+					Characters: []string{a, b},
+					Vision:     vision,
+					Value:      dist,
+					Message:    fmt.Sprintf("%s and %s are hard to tell apart with %s (distance %.3f, want %.3f)", a, b, with, dist, opts.MinDistance),
+				})
+			}
+		}
+	}
+
+	return report
+}
+
+// ValidateThemes validates every registered theme, in name order.
+func ValidateThemes(opts ValidationOptions) []ThemeReport {
+	var reports []ThemeReport
+	for _, name := range ListThemes() {
+		theme, err := GetTheme(name)
+		if err != nil {
+			continue
+		}
+		reports = append(reports, ValidateTheme(theme, opts))
+	}
+	return reports
+}
+
+// characterOrder sorts the standard characters in scale order, then any
+// others by name
+func characterOrder(name string) string {
+	for i, n := range AllCharacterNames() {
+		if n == name {
+			return fmt.Sprintf("%02d", i)
+		}
+	}
+	return "~" + name
+}
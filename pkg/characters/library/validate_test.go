@@ -0,0 +1,102 @@
+package library
+
+import "testing"
+
+func TestValidateThemeContrast(t *testing.T) {
+	theme := ThemeDefinition{
+		Name:     "test",
+		Affinity: AffinityDark,
+		Colors: map[string]string{
+			CharacterSa: "#FF8888", // light, fine on dark
+			CharacterPa: "#202060", // navy, too dark on dark
+		},
+	}
+
+	report := ValidateTheme(theme, ValidationOptions{})
+	if got := len(report.Contrast); got != 4 {
+		t.Errorf("len(Contrast) = %d, want 4 (2 characters x 2 backgrounds)", got)
+	}
+
+	var contrastIssues []ThemeIssue
+	for _, issue := range report.Issues {
+		if issue.Kind == IssueContrast {
+			contrastIssues = append(contrastIssues, issue)
+		}
+	}
+	if len(contrastIssues) != 1 {
+		t.Fatalf("contrast issues = %+v, want exactly one", contrastIssues)
+	}
+	issue := contrastIssues[0]
+	if issue.Characters[0] != CharacterPa || issue.Background != "dark" {
+		t.Errorf("issue = %+v, want pa on dark", issue)
+	}
+
+	// Without an affinity every background is enforced: the light red is
+	// too light for white
+	theme.Affinity = ""
+	report = ValidateTheme(theme, ValidationOptions{})
+	count := 0
+	for _, issue := range report.Issues {
+		if issue.Kind == IssueContrast {
+			count++
+		}
+	}
+	if count != 2 {
+		t.Errorf("contrast issues without affinity = %d, want 2", count)
+	}
+}
+
+func TestValidateThemeColorblind(t *testing.T) {
+	theme := ThemeDefinition{
+		Name:     "traffic",
+		Affinity: AffinityDark,
+		Colors: map[string]string{
+			CharacterSa: "#DC3232", // red
+			CharacterMa: "#787828", // olive
+		},
+	}
+
+	report := ValidateTheme(theme, ValidationOptions{MinDistance: 0.1})
+	visions := map[string]bool{}
+	for _, issue := range report.Issues {
+		if issue.Kind != IssueConfused {
+			continue
+		}
+		if issue.Characters[0] != CharacterSa || issue.Characters[1] != CharacterMa {
+			t.Errorf("confused pair = %v, want [sam ma]", issue.Characters)
+		}
+		visions[issue.Vision] = true
+	}
+	for _, want := range []string{"protanopia", "deuteranopia"} {
+		if !visions[want] {
+			t.Errorf("red/olive pair not flagged for %s (flagged: %v)", want, visions)
+		}
+	}
+	if visions["normal"] {
+		t.Error("red/olive pair should be distinguishable with normal vision")
+	}
+}
+
+func TestValidateThemeBadColor(t *testing.T) {
+	theme := ThemeDefinition{Name: "bad", Colors: map[string]string{CharacterSa: "red"}}
+	report := ValidateTheme(theme, ValidationOptions{})
+	if report.OK() || report.Issues[0].Kind != IssueColor {
+		t.Errorf("Issues = %+v, want a color issue", report.Issues)
+	}
+}
+
+func TestValidateThemes(t *testing.T) {
+	reports := ValidateThemes(ValidationOptions{})
+	if len(reports) != len(ListThemes()) {
+		t.Fatalf("len(reports) = %d, want %d", len(reports), len(ListThemes()))
+	}
+	for _, report := range reports {
+		want := len(AllCharacterNames()) * len(DefaultBackgrounds)
+		if len(report.Contrast) != want {
+			t.Errorf("theme %q has %d contrast results, want %d", report.Theme, len(report.Contrast), want)
+		}
+		if report.Contrast[0].Character != CharacterSa {
+			t.Errorf("theme %q first result = %q, want characters in scale order", report.Theme, report.Contrast[0].Character)
+		}
+	}
+}
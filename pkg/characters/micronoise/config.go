@@ -1,3 +1,8 @@
+// Package micronoise holds the original micro avatar gradient API.
+//
+// Deprecated: effects are now declared per state in the state JSON files
+// ("effects": [{"type": "gradient"}]) and applied by package effects through
+// FrameCache.ApplyEffects. This package is kept for existing callers.
 package micronoise
 
 // FlickerConfig defines color flicker behavior for micro avatars
@@ -7,6 +12,8 @@ type FlickerConfig struct {
 
 // StateConfigs maps state names to their flicker configuration.
 // States not in this map will have no flicker effect.
+//
+// Deprecated: the render path reads "effects" from the state JSON instead.
 var StateConfigs = map[string]FlickerConfig{
 	"think":     {Enabled: true},
 	"read":      {Enabled: true},
@@ -19,6 +26,7 @@ var StateConfigs = map[string]FlickerConfig{
 }
 
 // BrightnessLevels defines the 8 brightness multipliers for the gradient.
+// Same values as effects.DefaultLevels.
 // Goes from dark (left) to bright (right), creating a "marquee" effect.
 // Values < 1.0 = darker, values > 1.0 = brighter
 var BrightnessLevels = []float64{
@@ -1,15 +1,12 @@
 package micronoise
 
 import (
-	"fmt"
-	"regexp"
 	"strconv"
 	"strings"
-)
 
-// ANSI escape code pattern: \x1b[38;2;R;G;Bm
-var ansiColorRegex = regexp.MustCompile(`\x1b\[38;2;(\d+);(\d+);(\d+)m`)
-var ansiResetRegex = regexp.MustCompile(`\x1b\[0m`)
+	"github.com/wildreason/tangent/pkg/characters/effects"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
 
 // ApplyShiftingGradient applies a dark-to-light gradient that shifts left each frame.
 // Creates a "marquee" / "Wall Street ticker" effect where brightness sweeps across.
@@ -22,6 +19,12 @@ var ansiResetRegex = regexp.MustCompile(`\x1b\[0m`)
 //
 // Each column has a brightness level from BrightnessLevels.
 // Every frame, the pattern shifts left by 1 position (wrapping around).
+// Cells keep their own colors (from color layers) with brightness applied;
+// uncolored cells use the first color found in lines[0].
+// Output is rendered with the active termcolor profile, so on 256 and
+// 16-color terminals each level is quantized to the nearest palette entry.
+//
+// Deprecated: use the "gradient" effect (effects.Gradient).
 func ApplyShiftingGradient(lines []string, width, height int, frameCounter int, cfg *FlickerConfig) []string {
 	if cfg == nil || !cfg.Enabled || len(lines) == 0 {
 		return lines
@@ -29,41 +32,10 @@ func ApplyShiftingGradient(lines []string, width, height int, frameCounter int,
 
 	// Extract base color from first line
 	baseR, baseG, baseB := extractColor(lines[0])
+	base := termcolor.RGB{R: uint8(baseR), G: uint8(baseG), B: uint8(baseB)}
 
-	// Parse lines into raw content (strip ANSI codes)
-	rawLines := make([][]rune, len(lines))
-	for i, line := range lines {
-		rawLines[i] = []rune(stripANSI(line))
-	}
-
-	numLevels := len(BrightnessLevels)
-
-	// Rebuild with shifted gradient colors
-	result := make([]string, len(lines))
-	for row := 0; row < len(rawLines); row++ {
-		var sb strings.Builder
-		lineLen := len(rawLines[row])
-
-		for col := 0; col < lineLen; col++ {
-			char := rawLines[row][col]
-
-			// Calculate which brightness level this column gets
-			// Shift pattern left by frameCounter positions (wrapping)
-			brightnessIdx := (col + frameCounter) % numLevels
-			brightness := BrightnessLevels[brightnessIdx]
-
-			// Apply brightness to base color
-			r := clamp(int(float64(baseR)*brightness), 0, 255)
-			g := clamp(int(float64(baseG)*brightness), 0, 255)
-			b := clamp(int(float64(baseB)*brightness), 0, 255)
-
-			// Write character with brightness-adjusted color
-			sb.WriteString(fmt.Sprintf("\x1b[38;2;%d;%d;%dm%c\x1b[0m", r, g, b, char))
-		}
-		result[row] = sb.String()
-	}
-
-	return result
+	gradient := &effects.Gradient{Levels: BrightnessLevels, Direction: effects.DirectionRTL, Speed: 1, Period: len(BrightnessLevels)}
+	return effects.Pipeline{gradient}.Apply(lines, effects.Context{Frame: frameCounter, Base: base})
 }
 
 // Legacy function names for compatibility
@@ -80,14 +52,11 @@ func ApplyColorWave(lines []string, width, height int, frameCounter int, cfg *Fl
 }
 
 // extractColor extracts RGB values from ANSI colorized string.
+// Understands truecolor, 256-color and 16-color escapes.
 // Returns (255, 255, 255) if no color found.
 func extractColor(s string) (r, g, b int) {
-	matches := ansiColorRegex.FindStringSubmatch(s)
-	if len(matches) == 4 {
-		r, _ = strconv.Atoi(matches[1])
-		g, _ = strconv.Atoi(matches[2])
-		b, _ = strconv.Atoi(matches[3])
-		return
+	if c, ok := termcolor.ExtractForeground(s); ok {
+		return int(c.R), int(c.G), int(c.B)
 	}
 	return 255, 255, 255
 }
@@ -106,20 +75,7 @@ func HexToRGB(hex string) (int, int, int) {
 
 // stripANSI removes all ANSI escape codes from a string.
 func stripANSI(s string) string {
-	s = ansiColorRegex.ReplaceAllString(s, "")
-	s = ansiResetRegex.ReplaceAllString(s, "")
-	return s
-}
-
-// clamp restricts a value to a range.
-func clamp(val, min, max int) int {
-	if val < min {
-		return min
-	}
-	if val > max {
-		return max
-	}
-	return val
+	return termcolor.Strip(s)
 }
 
 // ApplyNoise is deprecated, kept for compatibility
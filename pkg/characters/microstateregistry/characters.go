@@ -0,0 +1,166 @@
+package microstateregistry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+// characterDefinitions holds per-character overrides of the default
+// definition, by character name
+var characterDefinitions = make(map[string]*MicroDefinition)
+
+// RegisterCharacter sets the micro design of a character. The design
+// overrides the default definition: a base frame with lines replaces the
+// shared base frame, and each state replaces the shared state of the same
+// name or adds a new one. Width and height, when set, must match the default.
+func RegisterCharacter(name string, def *MicroDefinition) error {
+	if name == "" {
+		return fmt.Errorf("character name is required")
+	}
+	if def == nil {
+		return fmt.Errorf("character %q: definition is required", name)
+	}
+	if err := validateOverride(def); err != nil {
+		return fmt.Errorf("character %q: %w", name, err)
+	}
+	characterDefinitions[name] = def
+	return nil
+}
+
+// LoadCharacters registers the character designs in dir, one JSON file per
+// character. A file without a name is registered under its file name. A
+// missing dir loads nothing.
+func LoadCharacters(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+		def, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if def.Name == "" {
+			def.Name = strings.TrimSuffix(entry.Name(), ".json")
+		}
+		if err := RegisterCharacter(def.Name, def); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// ForCharacter returns the micro definition of a character: the default
+// definition with the character's overrides applied. Characters without a
+// design of their own get the default.
+func ForCharacter(name string) *MicroDefinition {
+	override, ok := characterDefinitions[name]
+	if !ok || DefaultDefinition == nil {
+		return DefaultDefinition
+	}
+
+	def := *DefaultDefinition
+	def.Name = name
+	if len(override.BaseFrame.Lines) > 0 {
+		def.BaseFrame = override.BaseFrame
+	}
+	def.States = append([]MicroState(nil), DefaultDefinition.States...)
+	for _, state := range override.States {
+		replaced := false
+		for i := range def.States {
+			if def.States[i].Name == state.Name {
+				def.States[i] = state
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			def.States = append(def.States, state)
+		}
+	}
+	return &def
+}
+
+// Overridden reports whether a character's design replaces or adds a state;
+// "base" refers to the base frame
+func Overridden(character, state string) bool {
+	override, ok := characterDefinitions[character]
+	if !ok {
+		return false
+	}
+	if state == "base" {
+		return len(override.BaseFrame.Lines) > 0
+	}
+	for _, s := range override.States {
+		if s.Name == state {
+			return true
+		}
+	}
+	return false
+}
+
+// Characters returns the names of characters with their own designs in
+// alphabetical order
+func Characters() []string {
+	names := make([]string, 0, len(characterDefinitions))
+	for name := range characterDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateOverride checks an override's frames fit the default dimensions
+func validateOverride(def *MicroDefinition) error {
+	width, height := Width(), Height()
+	if (def.Width != 0 && def.Width != width) || (def.Height != 0 && def.Height != height) {
+		return fmt.Errorf("dimensions %dx%d don't match %dx%d", def.Width, def.Height, width, height)
+	}
+
+	check := func(where string, frame MicroFrame) error {
+		if len(frame.Lines) != height {
+			return fmt.Errorf("%s: %d lines, want %d", where, len(frame.Lines), height)
+		}
+		for i, line := range frame.Lines {
+			if n := len([]rune(line)); n != width {
+				return fmt.Errorf("%s: line %d is %d wide, want %d", where, i+1, n, width)
+			}
+		}
+		return nil
+	}
+
+	if len(def.BaseFrame.Lines) > 0 {
+		if err := check("base frame", def.BaseFrame); err != nil {
+			return err
+		}
+	}
+	for _, state := range def.States {
+		if state.Name == "" || strings.Contains(state.Name, "_") {
+			return fmt.Errorf("invalid state name %q", state.Name)
+		}
+		if len(state.Frames) == 0 {
+			return fmt.Errorf("state %q has no frames", state.Name)
+		}
+		for i, frame := range state.Frames {
+			if err := check(fmt.Sprintf("state %q frame %d", state.Name, i+1), frame); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
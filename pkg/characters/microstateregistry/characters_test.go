@@ -0,0 +1,131 @@
+package microstateregistry
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// keepCharacters restores the character designs after a test
+func keepCharacters(t *testing.T) {
+	saved := make(map[string]*MicroDefinition, len(characterDefinitions))
+	for name, def := range characterDefinitions {
+		saved[name] = def
+	}
+	t.Cleanup(func() { characterDefinitions = saved })
+}
+
+func TestForCharacter(t *testing.T) {
+	keepCharacters(t)
+
+	err := RegisterCharacter("bolt", &MicroDefinition{
+		BaseFrame: MicroFrame{Lines: []string{"rf____fl", "rf____fl"}},
+		States: []MicroState{
+			{Name: "wait", FPS: 4, Frames: []MicroFrame{{Lines: []string{"rffffffl", "r______l"}}}},
+			{Name: "spark", Frames: []MicroFrame{{Lines: []string{"r7ffff7l", "rffffffl"}}}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("RegisterCharacter() error = %v", err)
+	}
+
+	def := ForCharacter("bolt")
+	if def.Width != 8 || def.Height != 2 {
+		t.Errorf("ForCharacter(bolt) = %dx%d, want 8x2", def.Width, def.Height)
+	}
+	if want := []string{"rf____fl", "rf____fl"}; !reflect.DeepEqual(def.BaseFrame.Lines, want) {
+		t.Errorf("base frame = %v, want %v", def.BaseFrame.Lines, want)
+	}
+
+	states := make(map[string]MicroState)
+	for _, state := range def.States {
+		states[state.Name] = state
+	}
+	if states["wait"].FPS != 4 || len(states["wait"].Frames) != 1 {
+		t.Errorf("wait = %+v, want the override", states["wait"])
+	}
+	if _, ok := states["spark"]; !ok {
+		t.Error("added state spark missing")
+	}
+	if !reflect.DeepEqual(states["read"], *GetState("read")) {
+		t.Error("read differs from the shared state")
+	}
+	if len(def.States) != len(ListStates())+1 {
+		t.Errorf("%d states, want %d", len(def.States), len(ListStates())+1)
+	}
+
+	// The default definition is untouched
+	if GetState("wait").FPS == 4 || GetState("spark") != nil {
+		t.Error("override changed the default definition")
+	}
+	if ForCharacter("nobody") != DefaultDefinition {
+		t.Error("ForCharacter(nobody) is not the default definition")
+	}
+
+	tests := []struct {
+		state string
+		want  bool
+	}{
+		{"base", true},
+		{"wait", true},
+		{"spark", true},
+		{"read", false},
+	}
+	for _, tt := range tests {
+		if got := Overridden("bolt", tt.state); got != tt.want {
+			t.Errorf("Overridden(bolt, %s) = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestRegisterCharacterErrors(t *testing.T) {
+	keepCharacters(t)
+
+	frame := MicroFrame{Lines: []string{"rffffffl", "rffffffl"}}
+	tests := []struct {
+		name string
+		def  *MicroDefinition
+	}{
+		{"nil definition", nil},
+		{"wrong dimensions", &MicroDefinition{Width: 10, Height: 2}},
+		{"short base frame", &MicroDefinition{BaseFrame: MicroFrame{Lines: []string{"rffffffl"}}}},
+		{"narrow line", &MicroDefinition{States: []MicroState{{Name: "wait", Frames: []MicroFrame{{Lines: []string{"rffffl", "rffffffl"}}}}}}},
+		{"no frames", &MicroDefinition{States: []MicroState{{Name: "wait"}}}},
+		{"underscore in name", &MicroDefinition{States: []MicroState{{Name: "deep_wait", Frames: []MicroFrame{frame}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterCharacter("bolt", tt.def); err == nil {
+				t.Error("RegisterCharacter() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoadCharacters(t *testing.T) {
+	keepCharacters(t)
+
+	fsys := fstest.MapFS{
+		"designs/bolt.json":  {Data: []byte(`{"states": [{"name": "wait", "frames": [{"lines": ["rffffffl", "r7ff7ffl"]}]}]}`)},
+		"designs/nut.json":   {Data: []byte(`{"name": "hex", "base_frame": {"lines": ["rf7ff7fl", "rffffffl"]}}`)},
+		"designs/README.md":  {Data: []byte("not a design")},
+		"designs/other/x.js": {Data: []byte("{")},
+	}
+	if err := LoadCharacters(fsys, "designs"); err != nil {
+		t.Fatalf("LoadCharacters() error = %v", err)
+	}
+	for _, name := range []string{"bolt", "hex"} {
+		if ForCharacter(name) == DefaultDefinition {
+			t.Errorf("%s was not registered", name)
+		}
+	}
+	if err := LoadCharacters(fsys, "missing"); err != nil {
+		t.Errorf("LoadCharacters(missing) error = %v", err)
+	}
+
+	fsys["designs/bad.json"] = &fstest.MapFile{Data: []byte(`{"width": 11}`)}
+	if err := LoadCharacters(fsys, "designs"); err == nil {
+		t.Error("LoadCharacters() with a bad design error = nil, want error")
+	}
+}
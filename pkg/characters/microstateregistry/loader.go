@@ -6,7 +6,7 @@ import (
 	"fmt"
 )
 
-//go:embed states/micro.json
+//go:embed states
 var microFS embed.FS
 
 // DefaultDefinition is the global micro avatar definition loaded from embedded JSON
@@ -18,6 +18,9 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("Failed to load micro state registry: %v", err))
 	}
+	if err := LoadCharacters(microFS, "states/characters"); err != nil {
+		panic(fmt.Sprintf("Failed to load micro character designs: %v", err))
+	}
 }
 
 // LoadEmbedded loads the micro definition from the embedded JSON file
@@ -27,6 +30,11 @@ func LoadEmbedded() (*MicroDefinition, error) {
 		return nil, fmt.Errorf("failed to read micro.json: %w", err)
 	}
 
+	return Parse(data)
+}
+
+// Parse decodes a micro definition in the micro.json format
+func Parse(data []byte) (*MicroDefinition, error) {
 	var def MicroDefinition
 	if err := json.Unmarshal(data, &def); err != nil {
 		return nil, fmt.Errorf("failed to parse micro.json: %w", err)
@@ -84,3 +84,19 @@ func TestDimensions(t *testing.T) {
 		t.Errorf("Height() returned %d, expected 2", Height())
 	}
 }
+
+func TestStateEffects(t *testing.T) {
+	for _, name := range []string{"arise", "read", "write", "search", "approval"} {
+		state := GetState(name)
+		if state == nil {
+			t.Fatalf("GetState(%q) returned nil", name)
+		}
+		if len(state.Effects) == 0 || state.Effects[0].Type != "gradient" {
+			t.Errorf("state %q effects = %+v, want a gradient", name, state.Effects)
+		}
+	}
+
+	if state := GetState("resting"); state != nil && len(state.Effects) != 0 {
+		t.Errorf("resting should have no effects, got %+v", state.Effects)
+	}
+}
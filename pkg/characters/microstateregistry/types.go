@@ -1,10 +1,14 @@
 // Package microstateregistry provides types and loading for micro (10x2) avatar definitions.
 package microstateregistry
 
+import "github.com/wildreason/tangent/pkg/characters/domain"
+
 // MicroFrame represents a single frame in a micro avatar animation.
 type MicroFrame struct {
 	Name  string   `json:"name,omitempty"`
 	Lines []string `json:"lines"`
+	FG    []string `json:"fg,omitempty"` // Optional foreground color layer (palette slot digits)
+	BG    []string `json:"bg,omitempty"` // Optional background color layer (palette slot digits)
 }
 
 // MicroState represents an animation state with multiple frames.
@@ -12,6 +16,13 @@ type MicroState struct {
 	Name   string       `json:"name"`
 	FPS    int          `json:"fps,omitempty"`
 	Frames []MicroFrame `json:"frames"`
+
+	// Optional render-time noise for "$" cells
+	NoisePool string `json:"noise_pool,omitempty"` // Pattern codes to pick from
+	NoiseRate int    `json:"noise_rate,omitempty"` // Changes per second
+
+	// Optional render effects, applied in order (see package effects)
+	Effects []domain.EffectSpec `json:"effects,omitempty"`
 }
 
 // MicroDefinition represents a complete micro avatar definition with base frame and states.
@@ -0,0 +1,172 @@
+package patterns
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Glyph set names. A glyph set decides which runes pattern codes compile to,
+// so the same character definitions render on terminals and fonts with
+// limited Unicode coverage.
+const (
+	GlyphSetUnicode = "unicode" // Block elements including quadrants (default)
+	GlyphSetSafe    = "safe"    // Full/half blocks and shades only, no quadrants
+	GlyphSetASCII   = "ascii"   // Pure 7-bit ASCII
+	GlyphSetBraille = "braille" // Braille dots approximating each quadrant
+)
+
+var (
+	glyphSetsMu sync.RWMutex
+	glyphSets   = map[string]PatternCodes{
+		GlyphSetUnicode: DefaultPatternCodes(),
+		GlyphSetSafe:    SafeBlockPatternCodes(),
+		GlyphSetASCII:   ASCIIPatternCodes(),
+		GlyphSetBraille: BraillePatternCodes(),
+	}
+)
+
+// SafeBlockPatternCodes maps pattern codes to the block elements found in
+// legacy code pages (CP437). Quadrants are approximated by the half block
+// that covers them, diagonals by a medium shade.
+func SafeBlockPatternCodes() PatternCodes {
+	codes := DefaultPatternCodes()
+
+	codes.Quad1 = codes.TopHalf    // ▘ -> ▀
+	codes.Quad2 = codes.TopHalf    // ▝ -> ▀
+	codes.Quad3 = codes.BottomHalf // ▖ -> ▄
+	codes.Quad4 = codes.BottomHalf // ▗ -> ▄
+	codes.Quad5 = codes.TopHalf    // ▛ -> ▀
+	codes.Quad6 = codes.TopHalf    // ▜ -> ▀
+	codes.Quad7 = codes.BottomHalf // ▙ -> ▄
+	codes.Quad8 = codes.BottomHalf // ▟ -> ▄
+
+	codes.DiagonalBackward = codes.MediumShade // ▚ -> ▒
+	codes.DiagonalForward = codes.MediumShade  // ▞ -> ▒
+
+	return codes
+}
+
+// ASCIIPatternCodes maps pattern codes to 7-bit ASCII for terminals and
+// logs that cannot display block elements at all.
+func ASCIIPatternCodes() PatternCodes {
+	return PatternCodes{
+		FullBlock:  '#',
+		TopHalf:    '\'',
+		BottomHalf: '.',
+		LeftHalf:   '[',
+		RightHalf:  ']',
+
+		LightShade:  ':',
+		MediumShade: '=',
+		DarkShade:   '%',
+
+		Quad1: '`',
+		Quad2: '\'',
+		Quad3: ',',
+		Quad4: '.',
+
+		Quad5: '#',
+		Quad6: '#',
+		Quad7: '#',
+		Quad8: '#',
+
+		DiagonalBackward: '\\',
+		DiagonalForward:  '/',
+
+		Space: ' ',
+		Noise: NoisePlaceholder,
+	}
+}
+
+// BraillePatternCodes maps each 2x2 quadrant pattern to the braille cell
+// (2x4 dots) covering the same area. Braille ships with most fonts that
+// lack the quadrant block elements.
+func BraillePatternCodes() PatternCodes {
+	return PatternCodes{
+		FullBlock:  '⣿',
+		TopHalf:    '⠛',
+		BottomHalf: '⣤',
+		LeftHalf:   '⡇',
+		RightHalf:  '⢸',
+
+		LightShade:  '⠡',
+		MediumShade: '⢕',
+		DarkShade:   '⣞',
+
+		Quad1: '⠃', // upper left
+		Quad2: '⠘', // upper right
+		Quad3: '⡄', // lower left
+		Quad4: '⢠', // lower right
+
+		Quad5: '⡟', // UL+UR+LL
+		Quad6: '⢻', // UL+UR+LR
+		Quad7: '⣧', // UL+LL+LR
+		Quad8: '⣼', // UR+LL+LR
+
+		DiagonalBackward: '⢣',
+		DiagonalForward:  '⡜',
+
+		Space: ' ',
+		Noise: NoisePlaceholder,
+	}
+}
+
+// GetGlyphSet returns the pattern codes registered under name.
+// An empty name returns the default Unicode set.
+func GetGlyphSet(name string) (PatternCodes, error) {
+	if name == "" {
+		name = GlyphSetUnicode
+	}
+	glyphSetsMu.RLock()
+	defer glyphSetsMu.RUnlock()
+	codes, ok := glyphSets[name]
+	if !ok {
+		return PatternCodes{}, fmt.Errorf("glyph set %q not found", name)
+	}
+	return codes, nil
+}
+
+// RegisterGlyphSet adds or replaces a named glyph set.
+func RegisterGlyphSet(name string, codes PatternCodes) {
+	glyphSetsMu.Lock()
+	defer glyphSetsMu.Unlock()
+	glyphSets[name] = codes
+}
+
+// ListGlyphSets returns all glyph set names in sorted order.
+func ListGlyphSets() []string {
+	glyphSetsMu.RLock()
+	defer glyphSetsMu.RUnlock()
+	names := make([]string, 0, len(glyphSets))
+	for name := range glyphSets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Mapping returns the pattern code -> rune table for these codes, covering
+// both upper and lowercase block codes.
+func (c PatternCodes) Mapping() map[rune]rune {
+	return map[rune]rune{
+		'F': c.FullBlock, 'f': c.FullBlock,
+		'T': c.TopHalf, 't': c.TopHalf,
+		'B': c.BottomHalf, 'b': c.BottomHalf,
+		'L': c.LeftHalf, 'l': c.LeftHalf,
+		'R': c.RightHalf, 'r': c.RightHalf,
+
+		'.': c.LightShade,
+		':': c.MediumShade,
+		'#': c.DarkShade,
+
+		'1': c.Quad1, '2': c.Quad2, '3': c.Quad3, '4': c.Quad4,
+		'5': c.Quad5, '6': c.Quad6, '7': c.Quad7, '8': c.Quad8,
+
+		'\\': c.DiagonalBackward,
+		'/':  c.DiagonalForward,
+
+		'_': c.Space,
+		'$': c.Noise,
+	}
+}
@@ -0,0 +1,85 @@
+package patterns
+
+import (
+	"testing"
+	"unicode"
+)
+
+func TestGetGlyphSet(t *testing.T) {
+	for _, name := range []string{GlyphSetUnicode, GlyphSetSafe, GlyphSetASCII, GlyphSetBraille} {
+		if _, err := GetGlyphSet(name); err != nil {
+			t.Errorf("GetGlyphSet(%q) error = %v", name, err)
+		}
+	}
+
+	codes, err := GetGlyphSet("")
+	if err != nil {
+		t.Fatalf("GetGlyphSet(\"\") error = %v", err)
+	}
+	if codes != DefaultPatternCodes() {
+		t.Error("empty glyph set name should return default codes")
+	}
+
+	if _, err := GetGlyphSet("klingon"); err == nil {
+		t.Error("GetGlyphSet(klingon) should fail")
+	}
+}
+
+func TestASCIIPatternCodes(t *testing.T) {
+	for code, r := range ASCIIPatternCodes().Mapping() {
+		if code == '$' {
+			continue // noise placeholder is replaced at render time
+		}
+		if r > unicode.MaxASCII {
+			t.Errorf("ascii glyph for %q = %q, not ASCII", code, r)
+		}
+	}
+}
+
+func TestSafeBlockPatternCodes(t *testing.T) {
+	quadrants := map[rune]bool{
+		'▘': true, '▝': true, '▖': true, '▗': true,
+		'▛': true, '▜': true, '▙': true, '▟': true,
+		'▚': true, '▞': true,
+	}
+	for code, r := range SafeBlockPatternCodes().Mapping() {
+		if quadrants[r] {
+			t.Errorf("safe glyph for %q = %q, which is a quadrant", code, r)
+		}
+	}
+}
+
+func TestBraillePatternCodes(t *testing.T) {
+	for code, r := range BraillePatternCodes().Mapping() {
+		if code == '_' || code == '$' {
+			continue
+		}
+		if r < 0x2800 || r > 0x28FF {
+			t.Errorf("braille glyph for %q = %q, outside braille block", code, r)
+		}
+	}
+}
+
+func TestRegisterGlyphSet(t *testing.T) {
+	custom := ASCIIPatternCodes()
+	custom.FullBlock = '@'
+	RegisterGlyphSet("test-at", custom)
+
+	codes, err := GetGlyphSet("test-at")
+	if err != nil {
+		t.Fatalf("GetGlyphSet(test-at) error = %v", err)
+	}
+	if codes.FullBlock != '@' {
+		t.Errorf("FullBlock = %q, want '@'", codes.FullBlock)
+	}
+
+	found := false
+	for _, name := range ListGlyphSets() {
+		if name == "test-at" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("ListGlyphSets() missing registered set")
+	}
+}
@@ -0,0 +1,109 @@
+package patterns
+
+import (
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultNoiseCodes are the pattern codes of NoisePool. Compiling them with a
+// glyph set gives the default noise pool for that set.
+const DefaultNoiseCodes = `12345678.:#\/`
+
+// DefaultNoiseRate is how many times per second noise cells change when a
+// state does not configure its own rate.
+const DefaultNoiseRate = 12
+
+// MaxNoiseRate is the fastest noise can change; higher rates are clamped.
+const MaxNoiseRate = 1000
+
+// NoiseSource replaces noise placeholders at render time.
+//
+// Each source owns a seeded RNG, so two clients never share a noise stream
+// and a fixed seed reproduces the same output. Picks are held for
+// 1/rate seconds of wall-clock time: rendering faster or slower than the
+// animation FPS does not change how fast the noise moves.
+type NoiseSource struct {
+	mu     sync.Mutex
+	rng    *rand.Rand
+	now    func() time.Time
+	rate   int      // rate the current picks were drawn for
+	epoch  int64    // time slot the current picks belong to
+	values []uint32 // one random value per placeholder, in reading order
+}
+
+// NewNoiseSource creates a noise source with its own RNG seeded with seed.
+func NewNoiseSource(seed int64) *NoiseSource {
+	return &NoiseSource{
+		rng:   rand.New(rand.NewSource(seed)),
+		now:   time.Now,
+		epoch: -1,
+	}
+}
+
+// SetClock makes the source read time from now instead of the wall clock,
+// e.g. to render noise along a recording's timeline.
+func (n *NoiseSource) SetClock(now func() time.Time) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.now = now
+}
+
+// Apply returns lines with every NoisePlaceholder replaced by a rune from
+// pool. rate is the number of changes per second (DefaultNoiseRate if < 1,
+// at most MaxNoiseRate) and an empty pool falls back to NoisePool. Lines
+// without placeholders are returned unchanged.
+func (n *NoiseSource) Apply(lines []string, pool []rune, rate int) []string {
+	if !hasNoise(lines) {
+		return lines
+	}
+	if len(pool) == 0 {
+		pool = NoisePool
+	}
+	if rate < 1 {
+		rate = DefaultNoiseRate
+	}
+	rate = min(rate, MaxNoiseRate)
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	// Draw fresh picks when entering a new time slot
+	epoch := n.now().UnixNano() / (int64(time.Second) / int64(rate))
+	if epoch != n.epoch || rate != n.rate {
+		n.epoch, n.rate = epoch, rate
+		n.values = n.values[:0]
+	}
+
+	result := make([]string, len(lines))
+	k := 0
+	for i, line := range lines {
+		if !strings.ContainsRune(line, NoisePlaceholder) {
+			result[i] = line
+			continue
+		}
+		runes := []rune(line)
+		for j, r := range runes {
+			if r != NoisePlaceholder {
+				continue
+			}
+			for k >= len(n.values) {
+				n.values = append(n.values, n.rng.Uint32())
+			}
+			runes[j] = pool[n.values[k]%uint32(len(pool))]
+			k++
+		}
+		result[i] = string(runes)
+	}
+	return result
+}
+
+func hasNoise(lines []string) bool {
+	for _, line := range lines {
+		if strings.ContainsRune(line, NoisePlaceholder) {
+			return true
+		}
+	}
+	return false
+}
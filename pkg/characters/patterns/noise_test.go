@@ -0,0 +1,102 @@
+package patterns
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNoiseSourceApply(t *testing.T) {
+	lines := []string{"█◌█", "◌◌"}
+	pool := []rune{'a', 'b', 'c'}
+
+	n := NewNoiseSource(42)
+	got := n.Apply(lines, pool, 10)
+
+	if len(got) != 2 {
+		t.Fatalf("Apply returned %d lines, want 2", len(got))
+	}
+	for i, line := range got {
+		if strings.ContainsRune(line, NoisePlaceholder) {
+			t.Errorf("line %d still contains placeholder: %q", i, line)
+		}
+		for _, r := range line {
+			if r != '█' && !strings.ContainsRune("abc", r) {
+				t.Errorf("line %d has rune %q outside pool", i, r)
+			}
+		}
+	}
+	if lines[0] != "█◌█" {
+		t.Error("Apply modified its input")
+	}
+}
+
+func TestNoiseSourceSeed(t *testing.T) {
+	lines := []string{"◌◌◌◌◌◌◌◌"}
+	clock := time.Unix(100, 0)
+
+	a, b := NewNoiseSource(7), NewNoiseSource(7)
+	a.now = func() time.Time { return clock }
+	b.now = func() time.Time { return clock }
+
+	if got, want := a.Apply(lines, nil, 0)[0], b.Apply(lines, nil, 0)[0]; got != want {
+		t.Errorf("same seed produced %q and %q", got, want)
+	}
+}
+
+func TestNoiseSourceRate(t *testing.T) {
+	lines := []string{"◌◌◌◌◌◌◌◌◌◌◌◌◌◌◌◌"}
+	clock := time.Unix(100, 0)
+
+	n := NewNoiseSource(1)
+	n.now = func() time.Time { return clock }
+
+	first := n.Apply(lines, nil, 4)[0]
+
+	// Renders within the same 250ms slot keep their picks, however many there are
+	clock = clock.Add(100 * time.Millisecond)
+	if got := n.Apply(lines, nil, 4)[0]; got != first {
+		t.Errorf("noise changed within one slot: %q -> %q", first, got)
+	}
+
+	clock = clock.Add(200 * time.Millisecond)
+	if got := n.Apply(lines, nil, 4)[0]; got == first {
+		t.Errorf("noise did not change after the slot ended: %q", got)
+	}
+}
+
+func TestNoiseSourceNoPlaceholders(t *testing.T) {
+	lines := []string{"███"}
+	got := NewNoiseSource(1).Apply(lines, nil, 0)
+	if &got[0] != &lines[0] {
+		t.Error("lines without noise should be returned as-is")
+	}
+}
+
+func TestNoiseSourceSetClock(t *testing.T) {
+	lines := []string{"◌◌◌◌◌◌◌◌◌◌◌◌◌◌◌◌"}
+	clock := time.Unix(100, 0)
+
+	n := NewNoiseSource(1)
+	n.SetClock(func() time.Time { return clock })
+	first := n.Apply(lines, nil, 4)[0]
+
+	if got := n.Apply(lines, nil, 4)[0]; got != first {
+		t.Errorf("noise changed without the clock moving: %q -> %q", first, got)
+	}
+	clock = clock.Add(time.Second)
+	if got := n.Apply(lines, nil, 4)[0]; got == first {
+		t.Errorf("noise did not change when the clock moved: %q", got)
+	}
+}
+
+func TestNoiseSourceRateClamped(t *testing.T) {
+	lines := []string{"◌◌◌◌"}
+	n := NewNoiseSource(1)
+	n.SetClock(func() time.Time { return time.Unix(100, 0) })
+
+	// Rates past a nanosecond per slot used to divide by zero
+	if got := n.Apply(lines, nil, 2000000000)[0]; strings.ContainsRune(got, NoisePlaceholder) {
+		t.Errorf("Apply() = %q, want noise filled", got)
+	}
+}
@@ -0,0 +1,133 @@
+package patterns
+
+import "strings"
+
+// Quadrant bits of a cell, which block codes fill as 2x2 pixels
+const (
+	QuadUpperLeft = 1 << iota
+	QuadUpperRight
+	QuadLowerLeft
+	QuadLowerRight
+
+	QuadAll = QuadUpperLeft | QuadUpperRight | QuadLowerLeft | QuadLowerRight
+)
+
+// quadrantCodes maps the filled quadrants of a cell to its pattern code
+var quadrantCodes = [16]rune{
+	0:                               '_',
+	QuadUpperLeft:                   '1',
+	QuadUpperRight:                  '2',
+	QuadLowerLeft:                   '3',
+	QuadLowerRight:                  '4',
+	QuadUpperLeft | QuadUpperRight:  't',
+	QuadLowerLeft | QuadLowerRight:  'b',
+	QuadUpperLeft | QuadLowerLeft:   'l',
+	QuadUpperRight | QuadLowerRight: 'r',
+	QuadAll &^ QuadLowerRight:       '5',
+	QuadAll &^ QuadLowerLeft:        '6',
+	QuadAll &^ QuadUpperRight:       '7',
+	QuadAll &^ QuadUpperLeft:        '8',
+	QuadUpperLeft | QuadLowerRight:  '\\',
+	QuadUpperRight | QuadLowerLeft:  '/',
+	QuadAll:                         'f',
+}
+
+// quadrantGlyphs maps the filled quadrants of a cell to its default glyph,
+// and glyphQuadrants maps back, so compiled lines scale like pattern codes
+var quadrantGlyphs, glyphQuadrants = func() ([16]rune, map[rune]int) {
+	mapping := DefaultPatternCodes().Mapping()
+	var glyphs [16]rune
+	masks := make(map[rune]int, len(glyphs))
+	for mask, code := range quadrantCodes {
+		glyphs[mask] = mapping[code]
+		masks[mapping[code]] = mask
+	}
+	return glyphs, masks
+}()
+
+// QuadrantCode returns the pattern code filling the quadrants of mask
+func QuadrantCode(mask int) rune {
+	return quadrantCodes[mask&QuadAll]
+}
+
+// QuadrantMask returns the quadrants a block code fills. Shades, noise and
+// other codes don't map to quadrants and return false.
+func QuadrantMask(code rune) (int, bool) {
+	if code == ' ' {
+		return 0, true
+	}
+	if code >= 'A' && code <= 'Z' {
+		code += 'a' - 'A'
+	}
+	for mask, c := range quadrantCodes {
+		if c == code {
+			return mask, true
+		}
+	}
+	return 0, false
+}
+
+// ScaleLines scales pattern lines by n, expanding each cell into an n x n
+// block. Block codes are scaled as 2x2 pixels, so an odd n splits the
+// middle row and column of the block into halves and edges stay crisp;
+// other codes (shades, noise) fill the whole block. Lines already compiled
+// to the default glyphs scale the same way and stay compiled.
+func ScaleLines(lines []string, n int) []string {
+	if n <= 1 {
+		return lines
+	}
+
+	scaled := make([]string, 0, len(lines)*n)
+	for _, line := range lines {
+		rows := make([]strings.Builder, n)
+		for _, code := range line {
+			mask, ok := QuadrantMask(code)
+			cells := &quadrantCodes
+			if m, glyph := glyphQuadrants[code]; glyph {
+				mask, ok, cells = m, true, &quadrantGlyphs
+			}
+			for i := 0; i < n; i++ {
+				for j := 0; j < n; j++ {
+					if !ok {
+						rows[i].WriteRune(code)
+						continue
+					}
+					// Each pixel of the target cell samples the source
+					// pixel it falls in
+					cell := 0
+					for q := 0; q < 4; q++ {
+						px, py := (2*j+q%2)/n, (2*i+q/2)/n
+						if mask&(1<<(2*py+px)) != 0 {
+							cell |= 1 << q
+						}
+					}
+					rows[i].WriteRune(cells[cell])
+				}
+			}
+		}
+		for i := range rows {
+			scaled = append(scaled, rows[i].String())
+		}
+	}
+	return scaled
+}
+
+// ScaleLayer scales a color layer by n, repeating each rune over its
+// n x n block
+func ScaleLayer(layer []string, n int) []string {
+	if n <= 1 || len(layer) == 0 {
+		return layer
+	}
+
+	scaled := make([]string, 0, len(layer)*n)
+	for _, line := range layer {
+		var b strings.Builder
+		for _, r := range line {
+			b.WriteString(strings.Repeat(string(r), n))
+		}
+		for i := 0; i < n; i++ {
+			scaled = append(scaled, b.String())
+		}
+	}
+	return scaled
+}
@@ -0,0 +1,75 @@
+package patterns
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestQuadrantCodes(t *testing.T) {
+	for mask := 0; mask <= QuadAll; mask++ {
+		code := QuadrantCode(mask)
+		got, ok := QuadrantMask(code)
+		if !ok || got != mask {
+			t.Errorf("QuadrantMask(QuadrantCode(%d) = %q) = %d, %v", mask, code, got, ok)
+		}
+	}
+
+	tests := []struct {
+		code rune
+		mask int
+		ok   bool
+	}{
+		{'F', QuadAll, true},
+		{'R', QuadUpperRight | QuadLowerRight, true},
+		{'5', QuadAll &^ QuadLowerRight, true},
+		{' ', 0, true},
+		{'.', 0, false},
+		{'$', 0, false},
+	}
+	for _, tt := range tests {
+		mask, ok := QuadrantMask(tt.code)
+		if mask != tt.mask || ok != tt.ok {
+			t.Errorf("QuadrantMask(%q) = %d, %v, want %d, %v", tt.code, mask, ok, tt.mask, tt.ok)
+		}
+	}
+}
+
+func TestScaleLines(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines []string
+		n     int
+		want  []string
+	}{
+		{"scale 1", []string{"rfl"}, 1, []string{"rfl"}},
+		{"halves at 2", []string{"rl"}, 2, []string{"_ff_", "_ff_"}},
+		{"halves at 3", []string{"r"}, 3, []string{"_rf", "_rf", "_rf"}},
+		{"quadrant at 3", []string{"1"}, 3, []string{"fl_", "t1_", "___"}},
+		{"three quadrants at 2", []string{"7"}, 2, []string{"f_", "ff"}},
+		{"diagonal at 2", []string{"\\"}, 2, []string{"f_", "_f"}},
+		{"shades and noise", []string{".$"}, 2, []string{"..$$", "..$$"}},
+		{"space", []string{"_"}, 2, []string{"__", "__"}},
+		{"compiled halves at 3", []string{"▐▌"}, 3, []string{" ▐██▌ ", " ▐██▌ ", " ▐██▌ "}},
+		{"compiled quadrant at 2", []string{"▘ "}, 2, []string{"█   ", "    "}},
+		{"compiled shade", []string{"▒"}, 2, []string{"▒▒", "▒▒"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ScaleLines(tt.lines, tt.n); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ScaleLines(%q, %d) = %q, want %q", tt.lines, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScaleLayer(t *testing.T) {
+	got := ScaleLayer([]string{"1_"}, 2)
+	want := []string{"11__", "11__"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ScaleLayer() = %q, want %q", got, want)
+	}
+	if got := ScaleLayer(nil, 3); got != nil {
+		t.Errorf("ScaleLayer(nil) = %q, want nil", got)
+	}
+}
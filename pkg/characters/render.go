@@ -0,0 +1,64 @@
+package characters
+
+import (
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// renderFrame compiles a frame's pattern lines and colors them.
+// Frames without color layers are colored with the character color as a
+// whole line; frames with FG/BG layers are rendered cell by cell.
+func renderFrame(compiler domain.PatternCompiler, frame domain.Frame, char *domain.Character) []string {
+	lines := make([]string, len(frame.Lines))
+	for i, line := range frame.Lines {
+		compiled := compiler.Compile(line)
+		if frame.HasColorLayers() {
+			lines[i] = renderLayeredLine(compiled, layerRow(frame.FG, i), layerRow(frame.BG, i), char)
+		} else {
+			lines[i] = colorize(compiled, char.Color)
+		}
+	}
+	return lines
+}
+
+// renderLayeredLine renders one compiled line with per-cell colors from its
+// FG and BG layer rows
+func renderLayeredLine(compiled string, fgRow, bgRow []rune, char *domain.Character) string {
+	runes := []rune(compiled)
+	cells := make([]termcolor.Cell, len(runes))
+	base, hasBase := parseColor(char.Color)
+
+	for col, r := range runes {
+		cell := termcolor.Cell{Rune: r, FG: base, HasFG: hasBase}
+		if col < len(fgRow) {
+			if c, ok := parseColor(char.SlotColor(domain.ColorSlot(fgRow[col]))); ok {
+				cell.FG, cell.HasFG = c, true
+			}
+		}
+		if col < len(bgRow) {
+			if c, ok := parseColor(char.SlotColor(domain.ColorSlot(bgRow[col]))); ok {
+				cell.BG, cell.HasBG = c, true
+			}
+		}
+		cells[col] = cell
+	}
+
+	return termcolor.Default().RenderCells(cells)
+}
+
+// layerRow returns row i of a color layer as runes, or nil if absent
+func layerRow(layer []string, i int) []rune {
+	if i >= len(layer) {
+		return nil
+	}
+	return []rune(layer[i])
+}
+
+// parseColor parses a hex color, reporting false for "" or invalid input
+func parseColor(hex string) (termcolor.RGB, bool) {
+	if hex == "" {
+		return termcolor.RGB{}, false
+	}
+	c, err := termcolor.ParseHex(hex)
+	return c, err == nil
+}
@@ -0,0 +1,59 @@
+package characters
+
+import (
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+)
+
+// Scaled returns the agent with every frame scaled up by n for large
+// displays: an 11x4 character at 3 is 33x12. Cells expand into n x n
+// blocks following their quadrants (see patterns.ScaleLines), so edges
+// stay crisp. Scales below 2 return the agent itself.
+func (a *AgentCharacter) Scaled(n int) *AgentCharacter {
+	if n <= 1 || a.character == nil {
+		return a
+	}
+	if scaled, ok := a.scaled[n]; ok {
+		return scaled
+	}
+
+	char := *a.character
+	char.Width *= n
+	char.Height *= n
+	char.BaseFrame = scaleFrame(a.character.BaseFrame, n)
+	char.States = make(map[string]domain.State, len(a.character.States))
+	for name, state := range a.character.States {
+		frames := make([]domain.Frame, len(state.Frames))
+		for i, frame := range state.Frames {
+			frames[i] = scaleFrame(frame, n)
+		}
+		state.Frames = frames
+		char.States[name] = state
+	}
+	char.Frames = make([]domain.Frame, len(a.character.Frames))
+	for i, frame := range a.character.Frames {
+		char.Frames[i] = scaleFrame(frame, n)
+	}
+
+	scaled := &AgentCharacter{character: &char, glyphSet: a.glyphSet}
+	if a.scaled == nil {
+		a.scaled = make(map[int]*AgentCharacter)
+	}
+	a.scaled[n] = scaled
+	return scaled
+}
+
+// GetScaledFrameCache returns the frame cache of the agent scaled by n
+func (a *AgentCharacter) GetScaledFrameCache(n int) *FrameCache {
+	return a.Scaled(n).GetFrameCache()
+}
+
+// scaleFrame scales a frame and its color layers by n
+func scaleFrame(frame domain.Frame, n int) domain.Frame {
+	return domain.Frame{
+		Name:  frame.Name,
+		Lines: patterns.ScaleLines(frame.Lines, n),
+		FG:    patterns.ScaleLayer(frame.FG, n),
+		BG:    patterns.ScaleLayer(frame.BG, n),
+	}
+}
@@ -0,0 +1,100 @@
+package characters
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+)
+
+func TestScaled(t *testing.T) {
+	agent := NewAgentCharacter(&domain.Character{
+		Name:      "bolt",
+		Color:     "#4FA3FF",
+		Width:     2,
+		Height:    1,
+		BaseFrame: domain.Frame{Name: "base", Lines: []string{"rl"}, FG: []string{"1_"}},
+		States: map[string]domain.State{
+			"wait": {Name: "wait", AnimationFPS: 4, Frames: []domain.Frame{{Name: "wait_1", Lines: []string{"ff"}}}},
+		},
+	})
+
+	scaled := agent.Scaled(2)
+	char := scaled.GetCharacter()
+	if char.Width != 4 || char.Height != 2 {
+		t.Errorf("Scaled(2) = %dx%d, want 4x2", char.Width, char.Height)
+	}
+	if want := []string{"_ff_", "_ff_"}; !reflect.DeepEqual(char.BaseFrame.Lines, want) {
+		t.Errorf("base lines = %q, want %q", char.BaseFrame.Lines, want)
+	}
+	if want := []string{"11__", "11__"}; !reflect.DeepEqual(char.BaseFrame.FG, want) {
+		t.Errorf("base fg = %q, want %q", char.BaseFrame.FG, want)
+	}
+	if state := char.States["wait"]; state.AnimationFPS != 4 || len(state.Frames[0].Lines) != 2 {
+		t.Errorf("wait = %+v, want 2 lines at 4 FPS", state)
+	}
+
+	// The original is untouched and scaled agents are reused
+	if agent.GetCharacter().Width != 2 || agent.GetCharacter().BaseFrame.Lines[0] != "rl" {
+		t.Error("Scaled changed the original agent")
+	}
+	if agent.Scaled(2) != scaled {
+		t.Error("Scaled(2) built a new agent on the second call")
+	}
+	if agent.Scaled(1) != agent || agent.Scaled(0) != agent {
+		t.Error("Scaled below 2 should return the agent itself")
+	}
+
+	if err := agent.SetGlyphSet("ascii"); err != nil {
+		t.Fatal(err)
+	}
+	if again := agent.Scaled(2); again == scaled || again.GlyphSet() != "ascii" {
+		t.Error("Scaled after SetGlyphSet should rebuild with the new glyph set")
+	}
+}
+
+func TestScaledLibraryAgent(t *testing.T) {
+	agent, err := LibraryAgent("sam")
+	if err != nil {
+		t.Fatalf("LibraryAgent(sam) error = %v", err)
+	}
+
+	// Library frames are compiled to glyphs; their halves split across
+	// the scaled cells rather than repeating
+	base := agent.Scaled(2).GetCharacter().BaseFrame.Lines
+	if got, want := agent.GetCharacter().BaseFrame.Lines[1], " ▐███████▌ "; got != want {
+		t.Fatalf("base line 2 = %q, want %q", got, want)
+	}
+	want := "   " + strings.Repeat("█", 16) + "   "
+	for _, row := range []int{2, 3} {
+		if base[row] != want {
+			t.Errorf("scaled base line %d = %q, want %q", row+1, base[row], want)
+		}
+	}
+	for i, line := range base {
+		if strings.Contains(line, "_") {
+			t.Errorf("scaled base line %d = %q, want spaces left compiled", i+1, line)
+		}
+	}
+}
+
+func TestScaledFrameCache(t *testing.T) {
+	agent, err := LibraryAgent("sam")
+	if err != nil {
+		t.Fatalf("LibraryAgent(sam) error = %v", err)
+	}
+
+	cache := agent.GetScaledFrameCache(3)
+	if got := len(cache.GetBaseFrame()); got != 12 {
+		t.Errorf("scaled base frame has %d lines, want 12", got)
+	}
+	for _, state := range agent.ListStates() {
+		if !cache.HasState(state) {
+			t.Errorf("scaled cache missing state %s", state)
+		}
+	}
+	if len(agent.GetFrameCache().GetBaseFrame()) != 4 {
+		t.Error("scaling changed the unscaled frame cache")
+	}
+}
@@ -3,6 +3,7 @@ package characters
 import (
 	"fmt"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/wildreason/tangent/pkg/characters/domain"
 	"github.com/wildreason/tangent/pkg/characters/infrastructure"
@@ -10,16 +11,19 @@ import (
 
 // CharacterSpec defines a character using simple text patterns
 type CharacterSpec struct {
-	Name   string      `json:"name"`
-	Width  int         `json:"width"`
-	Height int         `json:"height"`
-	Frames []FrameSpec `json:"frames"`
+	Name    string      `json:"name"`
+	Width   int         `json:"width"`
+	Height  int         `json:"height"`
+	Palette []string    `json:"palette,omitempty"` // Colors for frame color layers: body, eyes, accent
+	Frames  []FrameSpec `json:"frames"`
 }
 
 // FrameSpec defines a single frame using text patterns
 type FrameSpec struct {
 	Name     string   `json:"name"`
 	Patterns []string `json:"patterns"`
+	FG       []string `json:"fg,omitempty"` // Optional foreground color layer (palette slot digits)
+	BG       []string `json:"bg,omitempty"` // Optional background color layer (palette slot digits)
 }
 
 // NewCharacterSpec creates a new character specification
@@ -41,6 +45,26 @@ func (cs *CharacterSpec) AddFrame(name string, patterns []string) *CharacterSpec
 	return cs
 }
 
+// AddLayeredFrame adds a frame with foreground and background color layers.
+// Each layer line is parallel to a pattern line; digits select palette slots,
+// any other rune keeps the default color. Either layer may be nil.
+//
+// Example (white eyes under the top-half blocks of an 8x2 avatar):
+//
+//	spec.AddLayeredFrame("idle",
+//	    []string{"RFFFFFFL", "RTFTTFTL"},
+//	    nil,
+//	    []string{"________", "__1__1__"})
+func (cs *CharacterSpec) AddLayeredFrame(name string, patterns, fg, bg []string) *CharacterSpec {
+	cs.Frames = append(cs.Frames, FrameSpec{
+		Name:     name,
+		Patterns: patterns,
+		FG:       fg,
+		BG:       bg,
+	})
+	return cs
+}
+
 // AddFrameFromString adds a frame from a single string pattern
 func (cs *CharacterSpec) AddFrameFromString(name, pattern string) *CharacterSpec {
 	// Split by newlines to get individual line patterns
@@ -74,16 +98,22 @@ func (cs *CharacterSpec) Build() (*domain.Character, error) {
 		frames[i] = domain.Frame{
 			Name:  frame.Name,
 			Lines: lines,
+			FG:    frame.FG,
+			BG:    frame.BG,
 		}
 	}
 
 	// Create domain character
 	character := &domain.Character{
-		Name:   cs.Name,
-		Width:  cs.Width,
-		Height: cs.Height,
-		Frames: frames,
-		States: make(map[string]domain.State),
+		Name:    cs.Name,
+		Width:   cs.Width,
+		Height:  cs.Height,
+		Palette: cs.Palette,
+		Frames:  frames,
+		States:  make(map[string]domain.State),
+	}
+	if len(cs.Palette) > 0 {
+		character.Color = cs.Palette[domain.PaletteBody]
 	}
 
 	return character, nil
@@ -133,6 +163,26 @@ func (cs *CharacterSpec) Validate() error {
 		if len(frame.Patterns) != cs.Height {
 			return fmt.Errorf("frame %d has %d patterns, expected %d", i+1, len(frame.Patterns), cs.Height)
 		}
+
+		// Color layers, when present, must cover the same cells as the patterns
+		layers := []struct {
+			name  string
+			lines []string
+		}{{"fg", frame.FG}, {"bg", frame.BG}}
+		for _, l := range layers {
+			layerName, layer := l.name, l.lines
+			if len(layer) == 0 {
+				continue
+			}
+			if len(layer) != len(frame.Patterns) {
+				return fmt.Errorf("frame %d %s layer has %d lines, expected %d", i+1, layerName, len(layer), len(frame.Patterns))
+			}
+			for j, row := range layer {
+				if utf8.RuneCountInString(row) != utf8.RuneCountInString(frame.Patterns[j]) {
+					return fmt.Errorf("frame %d %s layer line %d width does not match pattern", i+1, layerName, j+1)
+				}
+			}
+		}
 	}
 
 	return nil
@@ -323,3 +323,68 @@ func TestValidate_PatternHeightMismatch(t *testing.T) {
 		t.Errorf("Error should mention pattern count mismatch, got: %v", err)
 	}
 }
+
+func TestAddLayeredFrame(t *testing.T) {
+	spec := NewCharacterSpec("robot", 4, 2)
+	spec.AddLayeredFrame("idle", []string{"TTTT", "BBBB"}, []string{"0110", "____"}, []string{"2222", "____"})
+
+	if err := spec.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	char, err := spec.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	frame := char.Frames[0]
+	if len(frame.FG) != 2 || frame.FG[0] != "0110" {
+		t.Errorf("FG layer = %v, want copied from spec", frame.FG)
+	}
+	if len(frame.BG) != 2 || frame.BG[0] != "2222" {
+		t.Errorf("BG layer = %v, want copied from spec", frame.BG)
+	}
+}
+
+func TestValidate_LayerMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		fg   []string
+		bg   []string
+		want string
+	}{
+		{"fg line count", []string{"0000"}, nil, "fg layer has 1 lines, expected 2"},
+		{"bg width", nil, []string{"000", "0000"}, "bg layer line 1 width"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := NewCharacterSpec("robot", 4, 2)
+			spec.AddLayeredFrame("idle", []string{"TTTT", "BBBB"}, tt.fg, tt.bg)
+
+			err := spec.Validate()
+			if err == nil {
+				t.Fatal("Validate() should return error for layer mismatch")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Validate() error = %v, want mention of %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuild_Palette(t *testing.T) {
+	spec := NewCharacterSpec("robot", 2, 1)
+	spec.Palette = []string{"#FF0000", "#FFFFFF"}
+	spec.AddLayeredFrame("idle", []string{"TT"}, []string{"01"}, nil)
+
+	char, err := spec.Build()
+	if err != nil {
+		t.Fatalf("Build() error = %v", err)
+	}
+	if char.Color != "#FF0000" {
+		t.Errorf("Color = %q, want body color from palette", char.Color)
+	}
+	if char.SlotColor(1) != "#FFFFFF" {
+		t.Errorf("SlotColor(1) = %q, want %q", char.SlotColor(1), "#FFFFFF")
+	}
+}
@@ -0,0 +1,246 @@
+// Package sprite converts between character states and pixel images.
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+	"github.com/wildreason/tangent/pkg/characters/stateregistry"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// Grid describes how frames are laid out on a sprite sheet
+type Grid struct {
+	Columns int // Frames across
+	Rows    int // Frames down
+
+	// Pixel size of one frame; 0 divides the sheet evenly
+	CellWidth  int
+	CellHeight int
+
+	// Frames to read in reading order; 0 reads every cell, dropping
+	// empty cells at the end
+	Frames int
+}
+
+// Options configures Import.
+type Options struct {
+	// Background is the hex color of empty pixels. When empty, the
+	// top-left pixel of the sheet is used if it is opaque; transparent
+	// pixels are always empty.
+	Background string
+
+	// Tolerance is how far (per RGB channel, 0-255) a pixel may be from
+	// the background and still count as empty
+	Tolerance int
+}
+
+// Sheet is an imported sprite sheet
+type Sheet struct {
+	State stateregistry.StateDefinition
+
+	// Color is the dominant color of the drawn pixels, a suggestion for
+	// the character's theme color
+	Color string
+}
+
+// Import reads a PNG sprite sheet and converts each grid cell to a frame
+// of a state named name. Each 2x2 block of pixels becomes one cell, the
+// pattern code whose quadrants match the drawn pixels.
+func Import(r io.Reader, name string, grid Grid, opts Options) (*Sheet, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PNG: %w", err)
+	}
+	return ImportImage(img, name, grid, opts)
+}
+
+// ImportImage converts an image as Import does
+func ImportImage(img image.Image, name string, grid Grid, opts Options) (*Sheet, error) {
+	if grid.Columns <= 0 || grid.Rows <= 0 {
+		return nil, fmt.Errorf("invalid grid %dx%d", grid.Columns, grid.Rows)
+	}
+	bounds := img.Bounds()
+	if grid.CellWidth <= 0 {
+		grid.CellWidth = bounds.Dx() / grid.Columns
+	}
+	if grid.CellHeight <= 0 {
+		grid.CellHeight = bounds.Dy() / grid.Rows
+	}
+	if grid.CellWidth == 0 || grid.CellHeight == 0 ||
+		grid.CellWidth*grid.Columns > bounds.Dx() || grid.CellHeight*grid.Rows > bounds.Dy() {
+		return nil, fmt.Errorf("grid %dx%d of %dx%d cells doesn't fit a %dx%d sheet",
+			grid.Columns, grid.Rows, grid.CellWidth, grid.CellHeight, bounds.Dx(), bounds.Dy())
+	}
+	cells := grid.Columns * grid.Rows
+	if grid.Frames > cells {
+		return nil, fmt.Errorf("%d frames requested, the grid has %d", grid.Frames, cells)
+	}
+
+	bg, err := newBackground(img, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sheet := &Sheet{State: stateregistry.StateDefinition{Name: name}}
+	counts := make(colorCounts)
+	lastDrawn := -1
+	for i := 0; i < cells; i++ {
+		origin := image.Pt(
+			bounds.Min.X+(i%grid.Columns)*grid.CellWidth,
+			bounds.Min.Y+(i/grid.Columns)*grid.CellHeight,
+		)
+		lines, drawn := quantizeCell(img, origin, grid.CellWidth, grid.CellHeight, bg, counts)
+		sheet.State.Frames = append(sheet.State.Frames, stateregistry.StateFrame{Lines: lines})
+		if drawn {
+			lastDrawn = i
+		}
+	}
+
+	switch {
+	case grid.Frames > 0:
+		sheet.State.Frames = sheet.State.Frames[:grid.Frames]
+	case lastDrawn >= 0:
+		sheet.State.Frames = sheet.State.Frames[:lastDrawn+1]
+	default:
+		return nil, fmt.Errorf("sprite sheet has no drawn pixels")
+	}
+	sheet.Color = counts.dominant()
+	return sheet, nil
+}
+
+// background decides which pixels are empty
+type background struct {
+	color     termcolor.RGB
+	opaque    bool // Whether color applies; otherwise only transparency is empty
+	tolerance int
+}
+
+func newBackground(img image.Image, opts Options) (background, error) {
+	bg := background{tolerance: opts.Tolerance}
+	if opts.Background != "" {
+		c, err := termcolor.ParseHex(opts.Background)
+		if err != nil {
+			return bg, err
+		}
+		bg.color, bg.opaque = c, true
+		return bg, nil
+	}
+	corner := img.Bounds().Min
+	if c, alpha := pixel(img, corner.X, corner.Y); alpha {
+		bg.color, bg.opaque = c, true
+	}
+	return bg, nil
+}
+
+// empty reports whether a pixel is background
+func (bg background) empty(c termcolor.RGB, opaque bool) bool {
+	if !opaque {
+		return true
+	}
+	if !bg.opaque {
+		return false
+	}
+	return abs(int(c.R)-int(bg.color.R)) <= bg.tolerance &&
+		abs(int(c.G)-int(bg.color.G)) <= bg.tolerance &&
+		abs(int(c.B)-int(bg.color.B)) <= bg.tolerance
+}
+
+// pixel returns the color of a pixel and whether it is at least half opaque
+func pixel(img image.Image, x, y int) (termcolor.RGB, bool) {
+	r, g, b, a := img.At(x, y).RGBA()
+	if a < 0x8000 {
+		return termcolor.RGB{}, false
+	}
+	// Undo premultiplied alpha
+	return termcolor.RGB{
+		R: uint8(r * 0xff / a),
+		G: uint8(g * 0xff / a),
+		B: uint8(b * 0xff / a),
+	}, true
+}
+
+// quantizeCell converts one frame of the sheet to pattern lines, counting
+// the colors of drawn pixels
+func quantizeCell(img image.Image, origin image.Point, width, height int, bg background, counts colorCounts) ([]string, bool) {
+	bounds := image.Rect(origin.X, origin.Y, origin.X+width, origin.Y+height)
+	drawn := false
+	lines := make([]string, 0, (height+1)/2)
+	for y := 0; y < height; y += 2 {
+		var line strings.Builder
+		for x := 0; x < width; x += 2 {
+			mask := 0
+			for q := 0; q < 4; q++ {
+				p := image.Pt(origin.X+x+q%2, origin.Y+y+q/2)
+				if !p.In(bounds) {
+					continue
+				}
+				c, opaque := pixel(img, p.X, p.Y)
+				if bg.empty(c, opaque) {
+					continue
+				}
+				mask |= 1 << q
+				counts.add(c)
+			}
+			if mask != 0 {
+				drawn = true
+			}
+			line.WriteRune(patterns.QuadrantCode(mask))
+		}
+		lines = append(lines, line.String())
+	}
+	return lines, drawn
+}
+
+// colorCounts tallies colors in buckets of 5 bits per channel
+type colorCounts map[uint16]*colorBucket
+
+type colorBucket struct {
+	count   int
+	r, g, b int
+}
+
+func (cc colorCounts) add(c termcolor.RGB) {
+	key := uint16(c.R>>3)<<10 | uint16(c.G>>3)<<5 | uint16(c.B>>3)
+	bucket, ok := cc[key]
+	if !ok {
+		bucket = &colorBucket{}
+		cc[key] = bucket
+	}
+	bucket.count++
+	bucket.r += int(c.R)
+	bucket.g += int(c.G)
+	bucket.b += int(c.B)
+}
+
+// dominant returns the average color of the largest bucket, or "" when
+// nothing was counted. Ties go to the lowest bucket, so the result is
+// stable.
+func (cc colorCounts) dominant() string {
+	var best *colorBucket
+	var bestKey uint16
+	for key, bucket := range cc {
+		if best == nil || bucket.count > best.count || (bucket.count == best.count && key < bestKey) {
+			best, bestKey = bucket, key
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return termcolor.RGB{
+		R: uint8(best.r / best.count),
+		G: uint8(best.g / best.count),
+		B: uint8(best.b / best.count),
+	}.Hex()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
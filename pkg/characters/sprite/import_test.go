@@ -0,0 +1,165 @@
+package sprite
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// drawSheet paints an image from rows of runes: '.' is the background
+// color, 'x' red, 'o' a slightly different red, 'b' blue and ' '
+// transparent
+func drawSheet(rows ...string) *image.NRGBA {
+	colors := map[rune]color.NRGBA{
+		'.': {R: 0x10, G: 0x10, B: 0x10, A: 0xff},
+		'x': {R: 0xe0, G: 0x40, B: 0x40, A: 0xff},
+		'o': {R: 0xe8, G: 0x44, B: 0x40, A: 0xff},
+		'b': {R: 0x30, G: 0x60, B: 0xf0, A: 0xff},
+		' ': {},
+	}
+	img := image.NewNRGBA(image.Rect(0, 0, len(rows[0]), len(rows)))
+	for y, row := range rows {
+		for x, r := range row {
+			img.SetNRGBA(x, y, colors[r])
+		}
+	}
+	return img
+}
+
+func TestImport(t *testing.T) {
+	// Two 4x4 frames side by side
+	img := drawSheet(
+		"..xx....",
+		"..xx.x..",
+		"xxxx..xx",
+		"xxbb..xx",
+	)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet, err := Import(&buf, "wave", Grid{Columns: 2, Rows: 1}, Options{})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if sheet.State.Name != "wave" {
+		t.Errorf("state name = %q, want wave", sheet.State.Name)
+	}
+	var got [][]string
+	for _, frame := range sheet.State.Frames {
+		got = append(got, frame.Lines)
+	}
+	want := [][]string{{"_f", "ff"}, {"4_", "_f"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("frames = %q, want %q", got, want)
+	}
+	if sheet.Color != "#E04040" {
+		t.Errorf("Color = %s, want #E04040", sheet.Color)
+	}
+}
+
+func TestImportOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+		grid Grid
+		opts Options
+		want [][]string
+	}{
+		{
+			name: "transparent background",
+			img:  drawSheet(" x", "x "),
+			grid: Grid{Columns: 1, Rows: 1},
+			want: [][]string{{"/"}},
+		},
+		{
+			name: "explicit background",
+			img:  drawSheet("xb", "bb"),
+			grid: Grid{Columns: 1, Rows: 1},
+			opts: Options{Background: "#3060F0"},
+			want: [][]string{{"1"}},
+		},
+		{
+			name: "tolerance",
+			img:  drawSheet("xo", "ob"),
+			grid: Grid{Columns: 1, Rows: 1},
+			opts: Options{Tolerance: 10},
+			want: [][]string{{"4"}},
+		},
+		{
+			name: "odd cells are padded",
+			img:  drawSheet("xxx", "xxx", "xxx"),
+			grid: Grid{Columns: 1, Rows: 1},
+			opts: Options{Background: "#000000"},
+			want: [][]string{{"fl", "t1"}},
+		},
+		{
+			name: "trailing empty cells dropped",
+			img:  drawSheet("..xx....", "..xx...."),
+			grid: Grid{Columns: 4, Rows: 1},
+			want: [][]string{{"_"}, {"f"}},
+		},
+		{
+			name: "frame count kept",
+			img:  drawSheet("..xx....", "..xx...."),
+			grid: Grid{Columns: 4, Rows: 1, Frames: 3},
+			want: [][]string{{"_"}, {"f"}, {"_"}},
+		},
+		{
+			name: "cell size and rows",
+			img:  drawSheet(".xx.", ".xx.", "....", "..xx", "..xx", "...."),
+			grid: Grid{Columns: 1, Rows: 2, CellWidth: 4, CellHeight: 3},
+			want: [][]string{{"rl", "__"}, {"_f", "__"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, err := ImportImage(tt.img, "wave", tt.grid, tt.opts)
+			if err != nil {
+				t.Fatalf("ImportImage() error = %v", err)
+			}
+			var got [][]string
+			for _, frame := range sheet.State.Frames {
+				got = append(got, frame.Lines)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("frames = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	img := drawSheet("..", "..")
+	tests := []struct {
+		name    string
+		grid    Grid
+		opts    Options
+		wantErr string
+	}{
+		{"no grid", Grid{}, Options{}, "invalid grid"},
+		{"grid too large", Grid{Columns: 1, Rows: 1, CellWidth: 4}, Options{}, "doesn't fit"},
+		{"too many frames", Grid{Columns: 1, Rows: 1, Frames: 2}, Options{}, "2 frames requested"},
+		{"bad background", Grid{Columns: 1, Rows: 1}, Options{Background: "red"}, "invalid hex color"},
+		{"nothing drawn", Grid{Columns: 1, Rows: 1}, Options{}, "no drawn pixels"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ImportImage(img, "wave", tt.grid, tt.opts)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ImportImage() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := Import(strings.NewReader("not a png"), "wave", Grid{Columns: 1, Rows: 1}, Options{}); err == nil {
+		t.Error("Import() of non-PNG data error = nil, want error")
+	}
+}
@@ -0,0 +1,224 @@
+package sprite
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"io"
+	"sort"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// RasterOptions configures RenderFrame, GIF and PNGSheet.
+type RasterOptions struct {
+	// Pixel size of one cell; 0 uses 8x16, a typical terminal cell
+	CellWidth  int
+	CellHeight int
+
+	// Scale enlarges the image n times; 0 or 1 keeps it as is
+	Scale int
+
+	// Background is a hex color filled behind the character; empty leaves
+	// it transparent
+	Background string
+}
+
+// quadrantSize returns the pixel size of one quadrant of a cell
+func (opts RasterOptions) quadrantSize() (int, int) {
+	w, h := opts.CellWidth, opts.CellHeight
+	if w <= 0 {
+		w = 8
+	}
+	if h <= 0 {
+		h = 16
+	}
+	if opts.Scale > 1 {
+		w, h = w*opts.Scale, h*opts.Scale
+	}
+	return max(w/2, 1), max(h/2, 1)
+}
+
+// RenderFrame draws a frame of a character as pixels. Block glyphs become
+// their filled quadrants and shades translucent fills in the character's
+// theme colors, so no font is needed.
+func RenderFrame(char *domain.Character, frame domain.Frame, opts RasterOptions) (*image.NRGBA, error) {
+	width, height := frameSize(char, frame)
+	return renderFrame(char, frame, width, height, opts)
+}
+
+// renderFrame draws a frame on a width x height cell canvas
+func renderFrame(char *domain.Character, frame domain.Frame, width, height int, opts RasterOptions) (*image.NRGBA, error) {
+	qw, qh := opts.quadrantSize()
+	img := image.NewNRGBA(image.Rect(0, 0, 2*width*qw, 2*height*qh))
+	if opts.Background != "" {
+		bg, err := parseColor(opts.Background)
+		if err != nil {
+			return nil, err
+		}
+		draw.Draw(img, img.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+
+	for _, r := range frameRects(char, frame) {
+		c, err := parseColor(r.color)
+		if err != nil {
+			return nil, fmt.Errorf("character %s: %w", char.Name, err)
+		}
+		bounds := image.Rect(r.x*qw, r.y*qh, (r.x+r.w)*qw, (r.y+r.h)*qh)
+		mask := image.NewUniform(color.Alpha{A: uint8(r.opacity*0xff + 0.5)})
+		draw.DrawMask(img, bounds, image.NewUniform(c), image.Point{}, mask, image.Point{}, draw.Over)
+	}
+	return img, nil
+}
+
+// parseColor converts a hex color to an opaque image color
+func parseColor(hex string) (color.NRGBA, error) {
+	c, err := termcolor.ParseHex(hex)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	return color.NRGBA{R: c.R, G: c.G, B: c.B, A: 0xff}, nil
+}
+
+// GIF writes a state of a character as an animated GIF that loops at the
+// state's frame rate. Pixels less than half opaque are transparent.
+func GIF(w io.Writer, char *domain.Character, stateName string, opts RasterOptions) error {
+	frames, fps, ok := stateFrames(char, stateName)
+	if !ok {
+		return fmt.Errorf("state %q not found for character %s", stateName, char.Name)
+	}
+
+	width, height := 0, 0
+	for _, frame := range frames {
+		fw, fh := frameSize(char, frame)
+		width, height = max(width, fw), max(height, fh)
+	}
+	images := make([]*image.NRGBA, len(frames))
+	for i, frame := range frames {
+		img, err := renderFrame(char, frame, width, height, opts)
+		if err != nil {
+			return err
+		}
+		images[i] = img
+	}
+
+	pal := gifPalette(images)
+	anim := &gif.GIF{}
+	delay := (100 + fps/2) / fps // Hundredths of a second
+	for _, img := range images {
+		anim.Image = append(anim.Image, paletted(img, pal))
+		anim.Delay = append(anim.Delay, delay)
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// gifPalette returns a palette of the colors in images, transparent first.
+// Over 256 colors falls back to the web-safe palette.
+func gifPalette(images []*image.NRGBA) color.Palette {
+	seen := make(map[color.NRGBA]bool)
+	pal := color.Palette{color.NRGBA{}}
+	for _, img := range images {
+		b := img.Bounds()
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c, opaque := pixel(img, x, y)
+				if !opaque {
+					continue
+				}
+				key := color.NRGBA{R: c.R, G: c.G, B: c.B, A: 0xff}
+				if !seen[key] {
+					seen[key] = true
+					pal = append(pal, key)
+				}
+			}
+		}
+	}
+	if len(pal) > 256 {
+		return append(color.Palette{color.NRGBA{}}, palette.WebSafe...)
+	}
+	return pal
+}
+
+// paletted converts an image to pal, with index 0 for transparent pixels
+func paletted(img *image.NRGBA, pal color.Palette) *image.Paletted {
+	out := image.NewPaletted(img.Bounds(), pal)
+	index := make(map[termcolor.RGB]uint8)
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c, opaque := pixel(img, x, y)
+			if !opaque {
+				continue
+			}
+			i, ok := index[c]
+			if !ok {
+				// Skip the transparent entry when matching colors
+				i = uint8(pal[1:].Index(color.NRGBA{R: c.R, G: c.G, B: c.B, A: 0xff}) + 1)
+				index[c] = i
+			}
+			out.SetColorIndex(x, y, i)
+		}
+	}
+	return out
+}
+
+// PNGSheet writes states of a character as a PNG sprite sheet, one state
+// per row and one frame per column, left aligned. Empty states lists
+// "base" and then every state alphabetically; the rows are returned in
+// order so the sheet can be imported again.
+func PNGSheet(w io.Writer, char *domain.Character, states []string, opts RasterOptions) ([]string, error) {
+	if len(states) == 0 {
+		states = []string{"base"}
+		names := make([]string, 0, len(char.States))
+		for name := range char.States {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		states = append(states, names...)
+	}
+
+	rows := make([][]domain.Frame, len(states))
+	width, height, columns := 0, 0, 0
+	for i, name := range states {
+		frames, _, ok := stateFrames(char, name)
+		if !ok {
+			return nil, fmt.Errorf("state %q not found for character %s", name, char.Name)
+		}
+		rows[i] = frames
+		columns = max(columns, len(frames))
+		for _, frame := range frames {
+			fw, fh := frameSize(char, frame)
+			width, height = max(width, fw), max(height, fh)
+		}
+	}
+
+	qw, qh := opts.quadrantSize()
+	cw, ch := 2*width*qw, 2*height*qh
+	sheet := image.NewNRGBA(image.Rect(0, 0, columns*cw, len(rows)*ch))
+	if opts.Background != "" {
+		bg, err := parseColor(opts.Background)
+		if err != nil {
+			return nil, err
+		}
+		draw.Draw(sheet, sheet.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+	for y, frames := range rows {
+		for x, frame := range frames {
+			img, err := renderFrame(char, frame, width, height, opts)
+			if err != nil {
+				return nil, err
+			}
+			draw.Draw(sheet, img.Bounds().Add(image.Pt(x*cw, y*ch)), img, image.Point{}, draw.Src)
+		}
+	}
+	if err := png.Encode(w, sheet); err != nil {
+		return nil, err
+	}
+	return states, nil
+}
@@ -0,0 +1,156 @@
+package sprite
+
+import (
+	"bytes"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"reflect"
+	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+)
+
+func TestRenderFrame(t *testing.T) {
+	char := testCharacter()
+	img, err := RenderFrame(char, char.BaseFrame, RasterOptions{CellWidth: 2, CellHeight: 2})
+	if err != nil {
+		t.Fatalf("RenderFrame() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 4 || got.Y != 2 {
+		t.Fatalf("size = %v, want 4x2", got)
+	}
+
+	red := color.NRGBA{R: 0xff, A: 0xff}
+	// █▀: three quadrants of the second cell's lower row are empty
+	want := [][]color.NRGBA{
+		{red, red, red, red},
+		{red, red, {}, {}},
+	}
+	for y, row := range want {
+		for x, c := range row {
+			if got := img.NRGBAAt(x, y); got != c {
+				t.Errorf("pixel (%d,%d) = %v, want %v", x, y, got, c)
+			}
+		}
+	}
+
+	// Scale and background; the shade is blended over the background
+	frame := char.States["wait"].Frames[1]
+	img, err = RenderFrame(char, frame, RasterOptions{CellWidth: 2, CellHeight: 2, Scale: 2, Background: "#000000"})
+	if err != nil {
+		t.Fatalf("RenderFrame() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 8 || got.Y != 4 {
+		t.Fatalf("scaled size = %v, want 8x4", got)
+	}
+	if got, want := img.NRGBAAt(0, 0), (color.NRGBA{G: 0xff, A: 0xff}); got != want {
+		t.Errorf("background cell = %v, want %v", got, want)
+	}
+	if got := img.NRGBAAt(7, 3); got.R < 0x70 || got.R > 0x90 || got.A != 0xff {
+		t.Errorf("medium shade = %v, want half red over black", got)
+	}
+
+	if _, err := RenderFrame(char, frame, RasterOptions{Background: "black"}); err == nil {
+		t.Error("RenderFrame() with invalid background error = nil, want error")
+	}
+}
+
+func TestGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := GIF(&buf, testCharacter(), "wait", RasterOptions{CellWidth: 2, CellHeight: 2}); err != nil {
+		t.Fatalf("GIF() error = %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() error = %v", err)
+	}
+	if len(anim.Image) != 2 {
+		t.Fatalf("%d frames, want 2", len(anim.Image))
+	}
+	// 4 FPS
+	if !reflect.DeepEqual(anim.Delay, []int{25, 25}) {
+		t.Errorf("Delay = %v, want [25 25]", anim.Delay)
+	}
+	if anim.LoopCount != 0 {
+		t.Errorf("LoopCount = %d, want 0 (forever)", anim.LoopCount)
+	}
+
+	first := anim.Image[0]
+	if got := first.At(0, 0); !sameColor(got, color.NRGBA{R: 0xff, A: 0xff}) {
+		t.Errorf("frame 1 pixel (0,0) = %v, want red", got)
+	}
+	if got := first.At(3, 1); !sameColor(got, color.NRGBA{G: 0xff, A: 0xff}) {
+		t.Errorf("frame 1 pixel (3,1) = %v, want green", got)
+	}
+	// Frame 2 has no drawn pixels in its first cell's foreground, but a
+	// green background
+	if got := anim.Image[1].At(0, 0); !sameColor(got, color.NRGBA{G: 0xff, A: 0xff}) {
+		t.Errorf("frame 2 pixel (0,0) = %v, want green", got)
+	}
+}
+
+func TestGIFTransparent(t *testing.T) {
+	char := testCharacter()
+	var buf bytes.Buffer
+	if err := GIF(&buf, char, "base", RasterOptions{CellWidth: 2, CellHeight: 2}); err != nil {
+		t.Fatalf("GIF() error = %v", err)
+	}
+	anim, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatalf("gif.DecodeAll() error = %v", err)
+	}
+	if _, _, _, a := anim.Image[0].At(3, 1).RGBA(); a != 0 {
+		t.Errorf("empty pixel alpha = %d, want transparent", a)
+	}
+}
+
+func TestPNGSheet(t *testing.T) {
+	char := testCharacter()
+	char.States["blink"] = domain.State{
+		Name:   "blink",
+		Frames: []domain.Frame{{Lines: []string{"ft"}}, {Lines: []string{"_t"}}},
+	}
+
+	var buf bytes.Buffer
+	opts := RasterOptions{CellWidth: 2, CellHeight: 2, Background: "#000000"}
+	rows, err := PNGSheet(&buf, char, nil, opts)
+	if err != nil {
+		t.Fatalf("PNGSheet() error = %v", err)
+	}
+	if want := []string{"base", "blink", "wait"}; !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %v, want %v", rows, want)
+	}
+	img, err := png.Decode(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("png.Decode() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 8 || got.Y != 6 {
+		t.Errorf("sheet size = %v, want 8x6 (2 frames x 3 states)", got)
+	}
+
+	// One pixel per quadrant imports back to the same patterns
+	sheet, err := ImportImage(img, "blink", Grid{Columns: 2, Rows: 3, Frames: 4}, Options{Background: "#000000"})
+	if err != nil {
+		t.Fatalf("ImportImage() error = %v", err)
+	}
+	var got []string
+	for _, frame := range sheet.State.Frames {
+		got = append(got, frame.Lines[0])
+	}
+	want := []string{"ft", "__", "ft", "_t"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("reimported = %q, want %q", got, want)
+	}
+
+	if _, err := PNGSheet(&buf, char, []string{"dance"}, RasterOptions{}); err == nil {
+		t.Error("PNGSheet() of unknown state error = nil, want error")
+	}
+}
+
+// sameColor compares colors by their RGBA values
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
@@ -0,0 +1,150 @@
+package sprite
+
+import (
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/library"
+	"github.com/wildreason/tangent/pkg/characters/patterns"
+)
+
+// paint is the fill of one quadrant pixel
+type paint struct {
+	color   string
+	opacity float64
+}
+
+// rect is a run of equally painted quadrant pixels, in quadrants (half
+// cells) from the top left
+type rect struct {
+	x, y, w, h int
+	paint
+}
+
+// glyphCodes maps default Unicode block elements to pattern codes, so
+// frames that were already compiled render too
+var glyphCodes = func() map[rune]rune {
+	codes := make(map[rune]rune)
+	for code, glyph := range patterns.DefaultPatternCodes().Mapping() {
+		if code < 'a' || code > 'z' {
+			codes[glyph] = code
+		}
+	}
+	return codes
+}()
+
+// shadeOpacity is how much of a cell the shade codes and noise cover
+var shadeOpacity = map[rune]float64{'.': 0.25, ':': 0.5, '#': 0.75, '$': 0.5}
+
+// cellShape returns the quadrants a pattern code fills and at what opacity
+func cellShape(code rune) (mask int, opacity float64) {
+	if c, ok := glyphCodes[code]; ok {
+		code = c
+	}
+	if o, ok := shadeOpacity[code]; ok {
+		return patterns.QuadAll, o
+	}
+	if mask, ok := patterns.QuadrantMask(code); ok {
+		return mask, 1
+	}
+	return 0, 0
+}
+
+// layerColor returns the palette color a color layer selects at a cell,
+// or "" for the default
+func layerColor(char *domain.Character, layer []string, x, y int) string {
+	if y >= len(layer) {
+		return ""
+	}
+	runes := []rune(layer[y])
+	if x >= len(runes) {
+		return ""
+	}
+	if slot := domain.ColorSlot(runes[x]); slot >= 0 {
+		return char.SlotColor(slot)
+	}
+	return ""
+}
+
+// frameRects converts a frame to background then foreground rects,
+// merging runs of equal pixels along each row
+func frameRects(char *domain.Character, frame domain.Frame) []rect {
+	width, height := frameSize(char, frame)
+	bg := make([]paint, 4*width*height)
+	fg := make([]paint, 4*width*height)
+	set := func(layer []paint, x, y, q int, p paint) {
+		layer[(2*y+q/2)*2*width+2*x+q%2] = p
+	}
+
+	for y, line := range frame.Lines {
+		for x, code := range []rune(line) {
+			if color := layerColor(char, frame.BG, x, y); color != "" {
+				for q := 0; q < 4; q++ {
+					set(bg, x, y, q, paint{color, 1})
+				}
+			}
+			mask, opacity := cellShape(code)
+			if mask == 0 {
+				continue
+			}
+			color := layerColor(char, frame.FG, x, y)
+			if color == "" {
+				color = char.Color
+			}
+			for q := 0; q < 4; q++ {
+				if mask&(1<<q) != 0 {
+					set(fg, x, y, q, paint{color, opacity})
+				}
+			}
+		}
+	}
+
+	var rects []rect
+	for _, layer := range [][]paint{bg, fg} {
+		for py := 0; py < 2*height; py++ {
+			row := layer[py*2*width : (py+1)*2*width]
+			for px := 0; px < len(row); {
+				if row[px].opacity == 0 {
+					px++
+					continue
+				}
+				end := px + 1
+				for end < len(row) && row[end] == row[px] {
+					end++
+				}
+				rects = append(rects, rect{x: px, y: py, w: end - px, h: 1, paint: row[px]})
+				px = end
+			}
+		}
+	}
+	return rects
+}
+
+// frameSize returns the cell size of a frame, at least the character's
+func frameSize(char *domain.Character, frame domain.Frame) (width, height int) {
+	width, height = char.Width, char.Height
+	if len(frame.Lines) > height {
+		height = len(frame.Lines)
+	}
+	for _, line := range frame.Lines {
+		if n := len([]rune(line)); n > width {
+			width = n
+		}
+	}
+	return width, height
+}
+
+// stateFrames returns the frames and frame rate of a state; "base" is the
+// base frame
+func stateFrames(char *domain.Character, stateName string) ([]domain.Frame, int, bool) {
+	if stateName == "base" {
+		return []domain.Frame{char.BaseFrame}, library.DefaultFPS, true
+	}
+	state, ok := char.States[stateName]
+	if !ok || len(state.Frames) == 0 {
+		return nil, 0, false
+	}
+	fps := state.AnimationFPS
+	if fps <= 0 {
+		fps = library.DefaultFPS
+	}
+	return state.Frames, fps, true
+}
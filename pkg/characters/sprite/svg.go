@@ -0,0 +1,107 @@
+package sprite
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
+)
+
+// SVGOptions configures SVG.
+type SVGOptions struct {
+	// Pixel size of one cell; 0 uses 8x16, a typical terminal cell
+	CellWidth  int
+	CellHeight int
+
+	// Background is a hex color filled behind the character; empty leaves
+	// it transparent
+	Background string
+}
+
+// SVG writes a state of a character as a self-contained animated SVG.
+// Block glyphs are drawn as rectangles in the character's theme colors,
+// so no font is needed, and frames are cycled at the state's frame rate
+// with CSS keyframes. A single frame (or the "base" state) is static.
+func SVG(w io.Writer, char *domain.Character, stateName string, opts SVGOptions) error {
+	frames, fps, ok := stateFrames(char, stateName)
+	if !ok {
+		return fmt.Errorf("state %q not found for character %s", stateName, char.Name)
+	}
+	if opts.CellWidth <= 0 {
+		opts.CellWidth = 8
+	}
+	if opts.CellHeight <= 0 {
+		opts.CellHeight = 16
+	}
+	background := ""
+	if opts.Background != "" {
+		bg, err := termcolor.ParseHex(opts.Background)
+		if err != nil {
+			return err
+		}
+		background = bg.Hex()
+	}
+
+	width, height := 0, 0
+	for _, frame := range frames {
+		fw, fh := frameSize(char, frame)
+		width, height = max(width, fw), max(height, fh)
+	}
+	qw, qh := float64(opts.CellWidth)/2, float64(opts.CellHeight)/2
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		width*opts.CellWidth, height*opts.CellHeight, width*opts.CellWidth, height*opts.CellHeight)
+
+	animated := len(frames) > 1
+	if animated {
+		// Every frame runs the same animation, visible for the first 1/n of
+		// the cycle, offset by its position
+		n := len(frames)
+		fmt.Fprintf(bw, "<style>\n.f{opacity:0;animation:frame %ss step-end infinite}\n", seconds(n, fps))
+		fmt.Fprintf(bw, "@keyframes frame{0%%{opacity:1}%s%%{opacity:0}}\n", formatFloat(100/float64(n)))
+		for i := range frames {
+			fmt.Fprintf(bw, ".f%d{animation-delay:%ss}\n", i, seconds(i, fps))
+		}
+		fmt.Fprintln(bw, "</style>")
+	}
+
+	if background != "" {
+		fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", background)
+	}
+
+	for i, frame := range frames {
+		if animated {
+			fmt.Fprintf(bw, `<g class="f f%d">`+"\n", i)
+		} else {
+			fmt.Fprintln(bw, "<g>")
+		}
+		for _, r := range frameRects(char, frame) {
+			fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"`,
+				formatFloat(float64(r.x)*qw), formatFloat(float64(r.y)*qh),
+				formatFloat(float64(r.w)*qw), formatFloat(float64(r.h)*qh), r.color)
+			if r.opacity < 1 {
+				fmt.Fprintf(bw, ` fill-opacity="%s"`, formatFloat(r.opacity))
+			}
+			fmt.Fprintln(bw, "/>")
+		}
+		fmt.Fprintln(bw, "</g>")
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// seconds formats the duration of n frames at fps
+func seconds(n, fps int) string {
+	return formatFloat(float64(n) / float64(fps))
+}
+
+// formatFloat formats f to at most four decimals
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f*1e4)/1e4, 'f', -1, 64)
+}
@@ -0,0 +1,190 @@
+package sprite
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/domain"
+)
+
+// svgRect is the parsed form of a <rect> element
+type svgRect struct {
+	X, Y, Width, Height, Fill, Opacity string
+}
+
+// parseSVG checks an SVG is well formed and returns its rects by frame
+// group
+func parseSVG(t *testing.T, data []byte) [][]svgRect {
+	t.Helper()
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var groups [][]svgRect
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return groups
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v\n%s", err, data)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "g":
+			groups = append(groups, nil)
+		case "rect":
+			if len(groups) == 0 {
+				continue // Background
+			}
+			var r svgRect
+			for _, attr := range start.Attr {
+				switch attr.Name.Local {
+				case "x":
+					r.X = attr.Value
+				case "y":
+					r.Y = attr.Value
+				case "width":
+					r.Width = attr.Value
+				case "height":
+					r.Height = attr.Value
+				case "fill":
+					r.Fill = attr.Value
+				case "fill-opacity":
+					r.Opacity = attr.Value
+				}
+			}
+			groups[len(groups)-1] = append(groups[len(groups)-1], r)
+		}
+	}
+}
+
+func testCharacter() *domain.Character {
+	return &domain.Character{
+		Name:    "bolt",
+		Color:   "#FF0000",
+		Palette: []string{"#FF0000", "#00FF00"},
+		Width:   2,
+		Height:  1,
+		BaseFrame: domain.Frame{
+			Name:  "base",
+			Lines: []string{"█▀"},
+		},
+		States: map[string]domain.State{
+			"wait": {
+				Name:         "wait",
+				AnimationFPS: 4,
+				Frames: []domain.Frame{
+					{Lines: []string{"ff"}, FG: []string{"01"}},
+					{Lines: []string{"_:"}, BG: []string{"1_"}},
+				},
+			},
+		},
+	}
+}
+
+func TestSVGStatic(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SVG(&buf, testCharacter(), "base", SVGOptions{}); err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+	out := buf.String()
+	if !strings.Contains(out, `width="16" height="16"`) {
+		t.Errorf("SVG() size wrong:\n%s", out)
+	}
+	if strings.Contains(out, "<style>") {
+		t.Error("single frame SVG is animated")
+	}
+
+	groups := parseSVG(t, buf.Bytes())
+	// Compiled glyphs render too: the full block's top row merges with the
+	// upper half block
+	want := []svgRect{
+		{X: "0", Y: "0", Width: "16", Height: "8", Fill: "#FF0000"},
+		{X: "0", Y: "8", Width: "8", Height: "8", Fill: "#FF0000"},
+	}
+	if len(groups) != 1 || len(groups[0]) != len(want) {
+		t.Fatalf("rects = %+v, want %+v", groups, want)
+	}
+	for i, r := range groups[0] {
+		if r != want[i] {
+			t.Errorf("rect %d = %+v, want %+v", i, r, want[i])
+		}
+	}
+}
+
+func TestSVGAnimated(t *testing.T) {
+	var buf bytes.Buffer
+	opts := SVGOptions{CellWidth: 10, CellHeight: 20, Background: "#000000"}
+	if err := SVG(&buf, testCharacter(), "wait", opts); err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`width="20" height="20"`,
+		"animation:frame 0.5s step-end infinite",
+		"@keyframes frame{0%{opacity:1}50%{opacity:0}}",
+		".f1{animation-delay:0.25s}",
+		`<rect width="100%" height="100%" fill="#000000"/>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("SVG() missing %q:\n%s", want, out)
+		}
+	}
+
+	groups := parseSVG(t, buf.Bytes())
+	if len(groups) != 2 {
+		t.Fatalf("%d frame groups, want 2", len(groups))
+	}
+	// Frame 1: palette colors per cell, one rect per color per row
+	if got := len(groups[0]); got != 4 {
+		t.Errorf("frame 1 has %d rects, want 4", got)
+	}
+	if groups[0][1].Fill != "#00FF00" {
+		t.Errorf("frame 1 second cell fill = %s, want #00FF00", groups[0][1].Fill)
+	}
+	// Frame 2: background rows first, then the translucent shade
+	want := []svgRect{
+		{X: "0", Y: "0", Width: "10", Height: "10", Fill: "#00FF00"},
+		{X: "0", Y: "10", Width: "10", Height: "10", Fill: "#00FF00"},
+		{X: "10", Y: "0", Width: "10", Height: "10", Fill: "#FF0000", Opacity: "0.5"},
+		{X: "10", Y: "10", Width: "10", Height: "10", Fill: "#FF0000", Opacity: "0.5"},
+	}
+	if len(groups[1]) != len(want) {
+		t.Fatalf("frame 2 rects = %+v, want %+v", groups[1], want)
+	}
+	for i, r := range groups[1] {
+		if r != want[i] {
+			t.Errorf("frame 2 rect %d = %+v, want %+v", i, r, want[i])
+		}
+	}
+}
+
+func TestSVGUnknownState(t *testing.T) {
+	if err := SVG(io.Discard, testCharacter(), "dance", SVGOptions{}); err == nil {
+		t.Error("SVG() of unknown state error = nil, want error")
+	}
+}
+
+func TestSVGBackground(t *testing.T) {
+	var buf bytes.Buffer
+	if err := SVG(&buf, testCharacter(), "base", SVGOptions{Background: "1a2b3c"}); err != nil {
+		t.Fatalf("SVG() error = %v", err)
+	}
+	if want := `<rect width="100%" height="100%" fill="#1A2B3C"/>`; !strings.Contains(buf.String(), want) {
+		t.Errorf("SVG() missing %q:\n%s", want, buf.String())
+	}
+
+	for _, bg := range []string{"black", `#000000"/><script>alert(1)</script><rect fill="`} {
+		buf.Reset()
+		if err := SVG(&buf, testCharacter(), "base", SVGOptions{Background: bg}); err == nil {
+			t.Errorf("SVG() with background %q error = nil, want error", bg)
+		}
+		if buf.Len() != 0 {
+			t.Errorf("SVG() with background %q wrote %d bytes", bg, buf.Len())
+		}
+	}
+}
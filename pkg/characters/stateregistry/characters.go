@@ -0,0 +1,149 @@
+package stateregistry
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+)
+
+// StateSource tells where a character's state comes from
+type StateSource string
+
+// State sources
+const (
+	SourceInherited  StateSource = "inherited"  // The shared state
+	SourceOverridden StateSource = "overridden" // The character's own version of a shared state
+	SourceAdded      StateSource = "added"      // A state only the character has
+)
+
+// StateListing names a state of a character and where it comes from
+type StateListing struct {
+	Name   string
+	Source StateSource
+}
+
+// characterStates holds per-character state overrides by character name
+var characterStates = make(map[string]*Registry)
+
+// RegisterCharacterState adds a state of one character, replacing the
+// shared state of the same name for that character only.
+func RegisterCharacterState(character string, state StateDefinition) error {
+	if character == "" {
+		return fmt.Errorf("character name is required")
+	}
+	if err := validateState(state); err != nil {
+		return fmt.Errorf("character %q: %w", character, err)
+	}
+	if characterStates[character] == nil {
+		characterStates[character] = NewRegistry()
+	}
+	characterStates[character].Register(state)
+	return nil
+}
+
+// LoadCharacters registers the states in each subdirectory of dir as the
+// overrides of the character named after it. A missing dir loads nothing.
+func LoadCharacters(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		registry, err := LoadFS(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return fmt.Errorf("character %q: %w", entry.Name(), err)
+		}
+		for _, state := range registry.All() {
+			if err := RegisterCharacterState(entry.Name(), state); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ForCharacter returns the states of a character: the shared states with
+// the character's overrides and additions applied
+func ForCharacter(character string) map[string]StateDefinition {
+	overrides, ok := characterStates[character]
+	if !ok {
+		return All()
+	}
+
+	states := make(map[string]StateDefinition, len(DefaultRegistry.states)+len(overrides.states))
+	for name, state := range All() {
+		states[name] = state
+	}
+	for name, state := range overrides.states {
+		states[name] = state
+	}
+	return states
+}
+
+// ListStates returns the states of a character in alphabetical order with
+// where each one comes from
+func ListStates(character string) []StateListing {
+	overrides := characterStates[character]
+	if overrides == nil {
+		overrides = NewRegistry()
+	}
+	listings := make([]StateListing, 0, len(DefaultRegistry.states))
+	for name := range ForCharacter(character) {
+		source := SourceInherited
+		if _, own := overrides.Get(name); own {
+			source = SourceOverridden
+			if _, shared := Get(name); !shared {
+				source = SourceAdded
+			}
+		}
+		listings = append(listings, StateListing{Name: name, Source: source})
+	}
+	sort.Slice(listings, func(i, j int) bool { return listings[i].Name < listings[j].Name })
+	return listings
+}
+
+// Characters returns the names of characters with state overrides in
+// alphabetical order
+func Characters() []string {
+	names := make([]string, 0, len(characterStates))
+	for name := range characterStates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateState checks a state has frames of one size
+func validateState(state StateDefinition) error {
+	if state.Name == "" {
+		return fmt.Errorf("state name is required")
+	}
+	if len(state.Frames) == 0 {
+		return fmt.Errorf("state %q has no frames", state.Name)
+	}
+	first := state.Frames[0].Lines
+	if len(first) == 0 {
+		return fmt.Errorf("state %q frame 1 has no lines", state.Name)
+	}
+	width := len([]rune(first[0]))
+	for i, frame := range state.Frames {
+		if len(frame.Lines) != len(first) {
+			return fmt.Errorf("state %q frame %d: %d lines, want %d", state.Name, i+1, len(frame.Lines), len(first))
+		}
+		for j, line := range frame.Lines {
+			if n := len([]rune(line)); n != width {
+				return fmt.Errorf("state %q frame %d: line %d is %d wide, want %d", state.Name, i+1, j+1, n, width)
+			}
+		}
+	}
+	return nil
+}
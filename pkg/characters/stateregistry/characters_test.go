@@ -0,0 +1,118 @@
+package stateregistry
+
+import (
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// keepCharacters restores the character overrides after a test
+func keepCharacters(t *testing.T) {
+	saved := make(map[string]*Registry, len(characterStates))
+	for name, registry := range characterStates {
+		saved[name] = registry
+	}
+	t.Cleanup(func() { characterStates = saved })
+}
+
+// frame returns an 11x4 frame with fill as its middle rows
+func frame(fill string) StateFrame {
+	return StateFrame{Lines: []string{"___________", fill, fill, "___________"}}
+}
+
+func TestForCharacter(t *testing.T) {
+	keepCharacters(t)
+
+	for _, state := range []StateDefinition{
+		{Name: "wait", FPS: 2, Frames: []StateFrame{frame("_rf7fff7fl_")}},
+		{Name: "spark", Frames: []StateFrame{frame("_r7fffff7l_"), frame("_rfffffffl_")}},
+	} {
+		if err := RegisterCharacterState("bolt", state); err != nil {
+			t.Fatalf("RegisterCharacterState(%s) error = %v", state.Name, err)
+		}
+	}
+
+	states := ForCharacter("bolt")
+	if states["wait"].FPS != 2 {
+		t.Errorf("wait = %+v, want the override", states["wait"])
+	}
+	if _, ok := states["spark"]; !ok {
+		t.Error("added state spark missing")
+	}
+	if len(states) != len(All())+1 {
+		t.Errorf("%d states, want %d", len(states), len(All())+1)
+	}
+	if shared, _ := Get("wait"); shared.FPS == 2 {
+		t.Error("override changed the shared state")
+	}
+	if !reflect.DeepEqual(ForCharacter("nobody"), All()) {
+		t.Error("ForCharacter(nobody) differs from the shared states")
+	}
+
+	sources := make(map[string]StateSource)
+	for _, listing := range ListStates("bolt") {
+		sources[listing.Name] = listing.Source
+	}
+	want := map[string]StateSource{
+		"wait":  SourceOverridden,
+		"spark": SourceAdded,
+		"read":  SourceInherited,
+	}
+	for name, source := range want {
+		if sources[name] != source {
+			t.Errorf("ListStates(bolt) %s = %q, want %q", name, sources[name], source)
+		}
+	}
+	for _, listing := range ListStates("nobody") {
+		if listing.Source != SourceInherited {
+			t.Errorf("ListStates(nobody) %s = %q, want inherited", listing.Name, listing.Source)
+		}
+	}
+}
+
+func TestRegisterCharacterStateErrors(t *testing.T) {
+	keepCharacters(t)
+
+	tests := []struct {
+		name      string
+		character string
+		state     StateDefinition
+	}{
+		{"no character", "", StateDefinition{Name: "wait", Frames: []StateFrame{frame("_rfffffffl_")}}},
+		{"no state name", "bolt", StateDefinition{Frames: []StateFrame{frame("_rfffffffl_")}}},
+		{"no frames", "bolt", StateDefinition{Name: "wait"}},
+		{"ragged frames", "bolt", StateDefinition{Name: "wait", Frames: []StateFrame{frame("_rfffffffl_"), frame("_rffl_")}}},
+		{"short frame", "bolt", StateDefinition{Name: "wait", Frames: []StateFrame{frame("_rfffffffl_"), {Lines: []string{"___________"}}}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterCharacterState(tt.character, tt.state); err == nil {
+				t.Error("RegisterCharacterState() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoadCharacters(t *testing.T) {
+	keepCharacters(t)
+
+	fsys := fstest.MapFS{
+		"chars/bolt/wait.json": {Data: []byte(`{"name": "wait", "fps": 3, "frames": [{"lines": ["___________", "_rf7fff7fl_", "_rfffffffl_", "___________"]}]}`)},
+		"chars/notes.txt":      {Data: []byte("ignored")},
+	}
+	if err := LoadCharacters(fsys, "chars"); err != nil {
+		t.Fatalf("LoadCharacters() error = %v", err)
+	}
+	if got := ForCharacter("bolt")["wait"].FPS; got != 3 {
+		t.Errorf("bolt wait FPS = %d, want 3", got)
+	}
+	if err := LoadCharacters(fsys, "missing"); err != nil {
+		t.Errorf("LoadCharacters(missing) error = %v", err)
+	}
+
+	fsys["chars/nut/wait.json"] = &fstest.MapFile{Data: []byte(`{"name": "wait", "frames": []}`)}
+	if err := LoadCharacters(fsys, "chars"); err == nil {
+		t.Error("LoadCharacters() with a bad state error = nil, want error")
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"embed"
 	"encoding/json"
 	"fmt"
-	"path/filepath"
+	"io/fs"
+	"path"
 	"strings"
 )
 
@@ -20,14 +21,22 @@ func init() {
 	if err != nil {
 		panic(fmt.Sprintf("Failed to load state registry: %v", err))
 	}
+	if err := LoadCharacters(statesFS, "states/characters"); err != nil {
+		panic(fmt.Sprintf("Failed to load character states: %v", err))
+	}
 }
 
 // LoadEmbedded loads all states from embedded JSON files
 func LoadEmbedded() (*Registry, error) {
+	return LoadFS(statesFS, "states")
+}
+
+// LoadFS loads all *.json state files in dir of fsys
+func LoadFS(fsys fs.FS, dir string) (*Registry, error) {
 	registry := NewRegistry()
 
-	// Read all JSON files from embedded filesystem
-	entries, err := statesFS.ReadDir("states")
+	// Read all JSON files from the filesystem
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read states directory: %w", err)
 	}
@@ -38,8 +47,7 @@ func LoadEmbedded() (*Registry, error) {
 		}
 
 		// Read file
-		path := filepath.Join("states", entry.Name())
-		data, err := statesFS.ReadFile(path)
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
 		if err != nil {
 			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
 		}
@@ -2,6 +2,8 @@ package stateregistry
 
 import (
 	"testing"
+
+	"github.com/wildreason/tangent/pkg/characters/effects"
 )
 
 func TestLoadEmbedded(t *testing.T) {
@@ -46,3 +48,34 @@ func TestDefaultRegistry(t *testing.T) {
 
 	t.Logf("DefaultRegistry has %d states", len(states))
 }
+
+func TestStateEffects(t *testing.T) {
+	for _, name := range []string{"think", "read", "write", "search", "arise", "approval"} {
+		state, ok := Get(name)
+		if !ok {
+			t.Fatalf("Get(%q) failed", name)
+		}
+		if len(state.Effects) == 0 || state.Effects[0].Type != "gradient" {
+			t.Errorf("state %q effects = %+v, want a gradient", name, state.Effects)
+		}
+	}
+}
+
+func TestStateKeyframes(t *testing.T) {
+	for _, name := range []string{"error", "approval", "arise"} {
+		state, ok := Get(name)
+		if !ok {
+			t.Fatalf("Get(%q) failed", name)
+		}
+		found := false
+		for _, spec := range state.Effects {
+			found = found || spec.Type == "keyframes"
+		}
+		if !found {
+			t.Errorf("state %q effects = %+v, want keyframes", name, state.Effects)
+		}
+		if _, err := effects.Build(state.Effects); err != nil {
+			t.Errorf("state %q: %v", name, err)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package stateregistry
 
+import "github.com/wildreason/tangent/pkg/characters/domain"
+
 // StateFrame represents a single frame in a state animation
 type StateFrame struct {
 	Lines []string `json:"lines"`
+	FG    []string `json:"fg,omitempty"` // Optional foreground color layer (palette slot digits)
+	BG    []string `json:"bg,omitempty"` // Optional background color layer (palette slot digits)
 }
 
 // StateDefinition represents a complete state with all its frames
@@ -10,6 +14,13 @@ type StateDefinition struct {
 	Name   string       `json:"name"`
 	Frames []StateFrame `json:"frames"`
 	FPS    int          `json:"fps,omitempty"` // Optional FPS override (default: 5)
+
+	// Optional render-time noise for "$" cells
+	NoisePool string `json:"noise_pool,omitempty"` // Pattern codes to pick from (default: all quadrants, shades, diagonals)
+	NoiseRate int    `json:"noise_rate,omitempty"` // Changes per second (default: 12)
+
+	// Optional render effects, applied in order (see package effects)
+	Effects []domain.EffectSpec `json:"effects,omitempty"`
 }
 
 // Registry holds all available states
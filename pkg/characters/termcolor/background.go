@@ -0,0 +1,174 @@
+package termcolor
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// DefaultBackgroundTimeout bounds how long DetectBackground waits for the
+// terminal to answer the background color query.
+const DefaultBackgroundTimeout = 150 * time.Millisecond
+
+// Fallback backgrounds for hints that only say "light" or "dark"
+var (
+	DarkBackground  = RGB{0, 0, 0}
+	LightBackground = RGB{255, 255, 255}
+)
+
+// DetectBackground returns the terminal's background color, and false when
+// it can't be determined.
+//
+// Sources, in priority order:
+//   - TANGENT_BACKGROUND set to "dark", "light" or a hex color
+//   - the OSC 11 query, answered by the terminal within timeout
+//   - COLORFGBG (set by rxvt, Konsole and some others), e.g. "15;0"
+func DetectBackground(timeout time.Duration) (RGB, bool) {
+	if bg, ok := backgroundOverride(os.Getenv); ok {
+		return bg, true
+	}
+	if os.Getenv("TERM") != "dumb" {
+		if bg, err := QueryBackground(timeout); err == nil {
+			return bg, true
+		}
+	}
+	return BackgroundFromEnv(os.Getenv)
+}
+
+// BackgroundFromEnv is the environment-only part of DetectBackground,
+// with an injectable lookup.
+func BackgroundFromEnv(getenv func(string) string) (RGB, bool) {
+	if bg, ok := backgroundOverride(getenv); ok {
+		return bg, true
+	}
+
+	// COLORFGBG is "fg;bg" or "fg;default;bg" with ANSI color indices
+	if v := getenv("COLORFGBG"); v != "" {
+		fields := strings.Split(v, ";")
+		idx, err := strconv.Atoi(fields[len(fields)-1])
+		if err == nil && idx >= 0 && idx < len(ansi16Palette) {
+			return ansi16Palette[idx], true
+		}
+	}
+	return RGB{}, false
+}
+
+// backgroundOverride reads TANGENT_BACKGROUND
+func backgroundOverride(getenv func(string) string) (RGB, bool) {
+	switch v := strings.ToLower(strings.TrimSpace(getenv("TANGENT_BACKGROUND"))); v {
+	case "":
+		return RGB{}, false
+	case "dark":
+		return DarkBackground, true
+	case "light":
+		return LightBackground, true
+	default:
+		bg, err := ParseHex(v)
+		return bg, err == nil
+	}
+}
+
+// QueryBackground asks the terminal for its background color with the
+// OSC 11 escape sequence. A device attributes query is sent right after,
+// so terminals that ignore OSC 11 answer at once instead of running into
+// the timeout. Call it before a TUI takes over the terminal input.
+func QueryBackground(timeout time.Duration) (RGB, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return RGB{}, err
+	}
+	defer tty.Close()
+
+	// Fd() would switch the file to blocking mode and disable deadlines
+	conn, err := tty.SyscallConn()
+	if err != nil {
+		return RGB{}, err
+	}
+	var state *term.State
+	var rawErr error
+	if err := conn.Control(func(fd uintptr) {
+		if !term.IsTerminal(fd) {
+			rawErr = fmt.Errorf("not a terminal")
+			return
+		}
+		state, rawErr = term.MakeRaw(fd)
+	}); err != nil {
+		return RGB{}, err
+	}
+	if rawErr != nil {
+		return RGB{}, rawErr
+	}
+	defer conn.Control(func(fd uintptr) { term.Restore(fd, state) })
+
+	if err := tty.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return RGB{}, err
+	}
+	if _, err := tty.WriteString("\x1b]11;?\x07\x1b[c"); err != nil {
+		return RGB{}, err
+	}
+
+	// Read until the device attributes reply so no response is left in
+	// the input for the application to see as keystrokes
+	var reply []byte
+	buf := make([]byte, 64)
+	for !isDeviceAttributes(reply) {
+		n, err := tty.Read(buf)
+		reply = append(reply, buf[:n]...)
+		if err != nil {
+			break
+		}
+	}
+	return ParseOSC11(string(reply))
+}
+
+// isDeviceAttributes reports whether reply ends with a complete DA1
+// response ("ESC [ ? ... c")
+func isDeviceAttributes(reply []byte) bool {
+	s := string(reply)
+	i := strings.LastIndex(s, "\x1b[?")
+	return i >= 0 && strings.HasSuffix(s[i:], "c")
+}
+
+// ParseOSC11 extracts the color from an OSC 11 reply such as
+// "ESC ] 11 ; rgb:1e1e/1e1e/2e2e BEL". Channels may have 1 to 4 hex
+// digits; the reply may end with BEL or ST (ESC \).
+func ParseOSC11(reply string) (RGB, error) {
+	start := strings.Index(reply, "\x1b]11;")
+	if start < 0 {
+		return RGB{}, fmt.Errorf("no OSC 11 reply in %q", reply)
+	}
+	body := reply[start+len("\x1b]11;"):]
+	end := strings.IndexAny(body, "\x07\x1b")
+	if end < 0 {
+		return RGB{}, fmt.Errorf("unterminated OSC 11 reply %q", reply)
+	}
+	body = body[:end]
+
+	spec, ok := strings.CutPrefix(body, "rgb:")
+	if !ok {
+		spec, ok = strings.CutPrefix(body, "rgba:")
+	}
+	if !ok {
+		return RGB{}, fmt.Errorf("unsupported OSC 11 color %q", body)
+	}
+	parts := strings.Split(spec, "/")
+	if len(parts) < 3 {
+		return RGB{}, fmt.Errorf("malformed OSC 11 color %q", body)
+	}
+
+	var channels [3]uint8
+	for i := range channels {
+		p := parts[i]
+		v, err := strconv.ParseUint(p, 16, 16)
+		if err != nil || len(p) == 0 || len(p) > 4 {
+			return RGB{}, fmt.Errorf("malformed OSC 11 color %q", body)
+		}
+		maxV := float64(uint64(1)<<(4*len(p)) - 1)
+		channels[i] = to8(float64(v) / maxV)
+	}
+	return RGB{channels[0], channels[1], channels[2]}, nil
+}
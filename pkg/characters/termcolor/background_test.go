@@ -0,0 +1,120 @@
+package termcolor
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseOSC11(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    RGB
+		wantErr bool
+	}{
+		{"four digits BEL", "\x1b]11;rgb:1e1e/1e1e/2e2e\x07", RGB{30, 30, 46}, false},
+		{"four digits ST", "\x1b]11;rgb:ffff/ffff/ffff\x1b\\", RGB{255, 255, 255}, false},
+		{"two digits", "\x1b]11;rgb:fd/f6/e3\x07", RGB{253, 246, 227}, false},
+		{"one digit", "\x1b]11;rgb:f/8/0\x07", RGB{255, 136, 0}, false},
+		{"followed by DA1", "\x1b]11;rgb:0000/0000/0000\x07\x1b[?62;22c", RGB{0, 0, 0}, false},
+		{"rgba", "\x1b]11;rgba:ffff/0000/0000/ffff\x07", RGB{255, 0, 0}, false},
+		{"only DA1", "\x1b[?1;2c", RGB{}, true},
+		{"unterminated", "\x1b]11;rgb:ffff/ff", RGB{}, true},
+		{"named color", "\x1b]11;black\x07", RGB{}, true},
+		{"bad digits", "\x1b]11;rgb:zz/00/00\x07", RGB{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOSC11(tt.reply)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseOSC11() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseOSC11() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackgroundFromEnv(t *testing.T) {
+	tests := []struct {
+		name   string
+		env    map[string]string
+		want   RGB
+		wantOK bool
+	}{
+		{"nothing", map[string]string{}, RGB{}, false},
+		{"override dark", map[string]string{"TANGENT_BACKGROUND": "dark"}, DarkBackground, true},
+		{"override light", map[string]string{"TANGENT_BACKGROUND": "Light"}, LightBackground, true},
+		{"override hex", map[string]string{"TANGENT_BACKGROUND": "#1E1E2E"}, RGB{30, 30, 46}, true},
+		{"override wins", map[string]string{"TANGENT_BACKGROUND": "light", "COLORFGBG": "15;0"}, LightBackground, true},
+		{"colorfgbg dark", map[string]string{"COLORFGBG": "15;0"}, RGB{0, 0, 0}, true},
+		{"colorfgbg light", map[string]string{"COLORFGBG": "0;15"}, RGB{255, 255, 255}, true},
+		{"colorfgbg three fields", map[string]string{"COLORFGBG": "0;default;7"}, RGB{229, 229, 229}, true},
+		{"colorfgbg garbage", map[string]string{"COLORFGBG": "default;default"}, RGB{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := BackgroundFromEnv(func(k string) string { return tt.env[k] })
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("BackgroundFromEnv() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	white, black := RGB{255, 255, 255}, RGB{}
+	tests := []struct {
+		a, b RGB
+		want float64
+	}{
+		{black, white, 21},
+		{white, black, 21},
+		{white, white, 1},
+		{RGB{118, 118, 118}, white, 4.54},
+	}
+	for _, tt := range tests {
+		if got := ContrastRatio(tt.a, tt.b); math.Abs(got-tt.want) > 0.01 {
+			t.Errorf("ContrastRatio(%v, %v) = %.2f, want %.2f", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	if !black.IsDark() || white.IsDark() {
+		t.Error("IsDark() should hold for black and not for white")
+	}
+}
+
+func TestEnsureContrast(t *testing.T) {
+	white, black := RGB{255, 255, 255}, RGB{}
+	tests := []struct {
+		name string
+		fg   RGB
+		bg   RGB
+	}{
+		{"pastel on white", RGB{229, 200, 144}, white},
+		{"yellow on white", RGB{255, 215, 0}, white},
+		{"navy on black", RGB{0, 0, 128}, black},
+		{"ink on black", RGB{43, 45, 58}, black},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EnsureContrast(tt.fg, tt.bg, 3)
+			if ratio := ContrastRatio(got, tt.bg); ratio < 3 {
+				t.Errorf("EnsureContrast() = %v with ratio %.2f, want >= 3", got, ratio)
+			}
+			_, c1, h1 := tt.fg.OKLCH()
+			_, _, h2 := got.OKLCH()
+			if c1 > 0.05 && math.Abs(h1-h2) > 10 {
+				t.Errorf("EnsureContrast() hue %.0f -> %.0f, want preserved", h1, h2)
+			}
+		})
+	}
+
+	// Colors that already contrast are untouched
+	if got := EnsureContrast(black, white, 3); got != black {
+		t.Errorf("EnsureContrast(black, white) = %v, want unchanged", got)
+	}
+}
@@ -0,0 +1,156 @@
+package termcolor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Cell is a single terminal cell with optional foreground and background
+// colors. With the half blocks ▀/▄ a cell shows two independently colored
+// pixels: the glyph half in FG and the other half in BG.
+type Cell struct {
+	Rune  rune
+	FG    RGB
+	BG    RGB
+	HasFG bool
+	HasBG bool
+}
+
+// Background returns the escape sequence that sets the background to c,
+// quantized to the renderer's profile. Returns "" for NoColor.
+func (r *Renderer) Background(c RGB) string {
+	switch r.profile {
+	case TrueColor:
+		return "\x1b[48;2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B)) + "m"
+	case ANSI256:
+		return "\x1b[48;5;" + strconv.Itoa(Nearest256(c)) + "m"
+	case ANSI16:
+		idx := Nearest16(c)
+		if idx < 8 {
+			return "\x1b[" + strconv.Itoa(40+idx) + "m"
+		}
+		return "\x1b[" + strconv.Itoa(100+idx-8) + "m"
+	default:
+		return ""
+	}
+}
+
+// RenderCells renders a row of cells, emitting escapes only where the
+// colors change and a single reset at the end.
+func (r *Renderer) RenderCells(cells []Cell) string {
+	var sb strings.Builder
+	var prev Cell
+	colored := false
+
+	for i, cell := range cells {
+		if i == 0 || !sameColors(cell, prev) {
+			if colored && (!cell.HasFG || !cell.HasBG) {
+				// Dropping a color requires a reset before re-applying the rest
+				sb.WriteString(Reset)
+				colored = false
+			}
+			if cell.HasFG {
+				if seq := r.Foreground(cell.FG); seq != "" {
+					sb.WriteString(seq)
+					colored = true
+				}
+			}
+			if cell.HasBG {
+				if seq := r.Background(cell.BG); seq != "" {
+					sb.WriteString(seq)
+					colored = true
+				}
+			}
+		}
+		sb.WriteRune(cell.Rune)
+		prev = cell
+	}
+
+	if colored {
+		sb.WriteString(Reset)
+	}
+	return sb.String()
+}
+
+func sameColors(a, b Cell) bool {
+	return a.HasFG == b.HasFG && a.HasBG == b.HasBG &&
+		(!a.HasFG || a.FG == b.FG) && (!a.HasBG || a.BG == b.BG)
+}
+
+// ParseCells splits a rendered line back into cells, tracking foreground
+// and background colors set by SGR escapes of any profile.
+func ParseCells(s string) []Cell {
+	cells := make([]Cell, 0, len(s))
+	var cur Cell
+
+	for i := 0; i < len(s); {
+		if s[i] == '\x1b' {
+			if loc := sgrPrefixRegex.FindStringIndex(s[i:]); loc != nil {
+				applySGR(&cur, s[i+2:i+loc[1]-1])
+				i += loc[1]
+				continue
+			}
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		cell := cur
+		cell.Rune = r
+		cells = append(cells, cell)
+		i += size
+	}
+	return cells
+}
+
+// sgrPrefixRegex matches an SGR sequence at the start of a string
+var sgrPrefixRegex = regexp.MustCompile(`^\x1b\[[0-9;]*m`)
+
+// applySGR updates the cell's color state from the parameters of one SGR sequence
+func applySGR(cur *Cell, paramStr string) {
+	if paramStr == "" {
+		*cur = Cell{}
+		return
+	}
+	params := strings.Split(paramStr, ";")
+	for i := 0; i < len(params); i++ {
+		n, err := strconv.Atoi(params[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 0:
+			*cur = Cell{}
+		case n == 38 || n == 48:
+			var c RGB
+			ok := false
+			if i+4 < len(params) && params[i+1] == "2" {
+				r, _ := strconv.Atoi(params[i+2])
+				g, _ := strconv.Atoi(params[i+3])
+				b, _ := strconv.Atoi(params[i+4])
+				c, ok = RGB{uint8(r), uint8(g), uint8(b)}, true
+				i += 4
+			} else if i+2 < len(params) && params[i+1] == "5" {
+				idx, _ := strconv.Atoi(params[i+2])
+				c, ok = Palette256(idx), true
+				i += 2
+			}
+			if ok && n == 38 {
+				cur.FG, cur.HasFG = c, true
+			} else if ok {
+				cur.BG, cur.HasBG = c, true
+			}
+		case n == 39:
+			cur.HasFG = false
+		case n == 49:
+			cur.HasBG = false
+		case n >= 30 && n <= 37:
+			cur.FG, cur.HasFG = ansi16Palette[n-30], true
+		case n >= 90 && n <= 97:
+			cur.FG, cur.HasFG = ansi16Palette[n-90+8], true
+		case n >= 40 && n <= 47:
+			cur.BG, cur.HasBG = ansi16Palette[n-40], true
+		case n >= 100 && n <= 107:
+			cur.BG, cur.HasBG = ansi16Palette[n-100+8], true
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package termcolor
+
+import "testing"
+
+func TestRendererBackground(t *testing.T) {
+	orange := RGB{255, 107, 53}
+
+	tests := []struct {
+		profile Profile
+		want    string
+	}{
+		{TrueColor, "\x1b[48;2;255;107;53m"},
+		{ANSI256, "\x1b[48;5;203m"},
+		{ANSI16, "\x1b[101m"},
+		{NoColor, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile.String(), func(t *testing.T) {
+			if got := NewRenderer(tt.profile).Background(orange); got != tt.want {
+				t.Errorf("Background() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderCells(t *testing.T) {
+	red := RGB{255, 0, 0}
+	blue := RGB{0, 0, 255}
+	r := NewRenderer(TrueColor)
+
+	tests := []struct {
+		name  string
+		cells []Cell
+		want  string
+	}{
+		{
+			name:  "uncolored",
+			cells: []Cell{{Rune: 'a'}, {Rune: 'b'}},
+			want:  "ab",
+		},
+		{
+			name:  "run shares one escape",
+			cells: []Cell{{Rune: '▀', FG: red, HasFG: true}, {Rune: '▀', FG: red, HasFG: true}},
+			want:  "\x1b[38;2;255;0;0m▀▀\x1b[0m",
+		},
+		{
+			name: "foreground and background",
+			cells: []Cell{
+				{Rune: '▀', FG: red, HasFG: true, BG: blue, HasBG: true},
+				{Rune: '▀', FG: red, HasFG: true},
+			},
+			want: "\x1b[38;2;255;0;0m\x1b[48;2;0;0;255m▀\x1b[0m\x1b[38;2;255;0;0m▀\x1b[0m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.RenderCells(tt.cells); got != tt.want {
+				t.Errorf("RenderCells() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCellsRoundTrip(t *testing.T) {
+	cells := []Cell{
+		{Rune: '▀', FG: RGB{255, 0, 0}, HasFG: true, BG: RGB{0, 0, 255}, HasBG: true},
+		{Rune: '▄', FG: RGB{255, 0, 0}, HasFG: true},
+		{Rune: ' '},
+	}
+
+	got := ParseCells(NewRenderer(TrueColor).RenderCells(cells))
+	if len(got) != len(cells) {
+		t.Fatalf("ParseCells returned %d cells, want %d", len(got), len(cells))
+	}
+	for i := range cells {
+		if got[i] != cells[i] {
+			t.Errorf("cell %d = %+v, want %+v", i, got[i], cells[i])
+		}
+	}
+}
+
+func TestParseCellsProfiles(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		fg   RGB
+		bg   RGB
+	}{
+		{"ansi256", "\x1b[38;5;196;48;5;21mx", RGB{255, 0, 0}, RGB{0, 0, 255}},
+		{"ansi16", "\x1b[91m\x1b[44mx", RGB{255, 0, 0}, RGB{0, 0, 238}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cells := ParseCells(tt.in)
+			if len(cells) != 1 {
+				t.Fatalf("ParseCells returned %d cells, want 1", len(cells))
+			}
+			c := cells[0]
+			if !c.HasFG || c.FG != tt.fg {
+				t.Errorf("FG = %v (set %v), want %v", c.FG, c.HasFG, tt.fg)
+			}
+			if !c.HasBG || c.BG != tt.bg {
+				t.Errorf("BG = %v (set %v), want %v", c.BG, c.HasBG, tt.bg)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package termcolor
+
+// Luminance returns the WCAG relative luminance of c, from 0 (black) to 1
+// (white).
+func (c RGB) Luminance() float64 {
+	return 0.2126*toLinear(c.R) + 0.7152*toLinear(c.G) + 0.0722*toLinear(c.B)
+}
+
+// IsDark reports whether c is a dark background: black text would contrast
+// with it less than white text does.
+func (c RGB) IsDark() bool {
+	return ContrastRatio(c, RGB{}) < ContrastRatio(c, RGB{255, 255, 255})
+}
+
+// ContrastRatio returns the WCAG contrast ratio of two colors, from 1 (no
+// contrast) to 21 (black on white). The order of the colors doesn't matter.
+func ContrastRatio(a, b RGB) float64 {
+	la, lb := a.Luminance(), b.Luminance()
+	if la < lb {
+		la, lb = lb, la
+	}
+	return (la + 0.05) / (lb + 0.05)
+}
+
+// EnsureContrast returns fg adjusted to reach at least the min contrast
+// ratio against bg. Only OKLCH lightness changes (darker on light
+// backgrounds, lighter on dark ones), so the hue survives; colors that
+// already contrast enough are returned unchanged.
+func EnsureContrast(fg, bg RGB, min float64) RGB {
+	if ContrastRatio(fg, bg) >= min {
+		return fg
+	}
+	l, chroma, h := fg.OKLCH()
+
+	// Bisect the smallest lightness change that meets the ratio
+	target := 1.0
+	if !bg.IsDark() {
+		target = 0
+	}
+	if ContrastRatio(FromOKLCH(target, chroma, h), bg) < min {
+		return FromOKLCH(target, chroma, h)
+	}
+	lo, hi := l, target
+	for i := 0; i < 24; i++ {
+		mid := (lo + hi) / 2
+		if ContrastRatio(FromOKLCH(mid, chroma, h), bg) >= min {
+			hi = mid
+		} else {
+			lo = mid
+		}
+	}
+	return FromOKLCH(hi, chroma, h)
+}
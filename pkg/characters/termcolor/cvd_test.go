@@ -0,0 +1,55 @@
+package termcolor
+
+import "testing"
+
+func TestSimulateKeepsGrays(t *testing.T) {
+	grays := []RGB{{0, 0, 0}, {128, 128, 128}, {255, 255, 255}}
+	for _, d := range Deficiencies {
+		for _, c := range grays {
+			if got := c.Simulate(d); Distance(got, c) > 0.01 {
+				t.Errorf("%v.Simulate(%v) = %v, want about the same gray", c, d, got)
+			}
+		}
+	}
+}
+
+func TestSimulateConfusions(t *testing.T) {
+	// Red and olive differ mostly along the red-green axis
+	red, olive := RGB{220, 50, 50}, RGB{120, 120, 40}
+	blue, green := RGB{60, 90, 220}, RGB{50, 160, 50}
+	tests := []struct {
+		d    Deficiency
+		a, b RGB
+	}{
+		{Protanopia, red, olive},
+		{Deuteranopia, red, olive},
+		{Tritanopia, blue, green},
+	}
+	for _, tt := range tests {
+		normal := Distance(tt.a, tt.b)
+		simulated := Distance(tt.a.Simulate(tt.d), tt.b.Simulate(tt.d))
+		if simulated >= normal/2 {
+			t.Errorf("%v: distance %v-%v = %.3f, want well below normal %.3f", tt.d, tt.a, tt.b, simulated, normal)
+		}
+	}
+
+	// Tritanopia keeps red and green apart
+	if Distance(red.Simulate(Tritanopia), green.Simulate(Tritanopia)) < 0.1 {
+		t.Error("tritanopia should keep red and green distinguishable")
+	}
+}
+
+func TestParseDeficiency(t *testing.T) {
+	for _, d := range Deficiencies {
+		got, err := ParseDeficiency(d.String())
+		if err != nil || got != d {
+			t.Errorf("ParseDeficiency(%q) = %v, %v, want %v", d.String(), got, err, d)
+		}
+	}
+	if got, err := ParseDeficiency("deutan"); err != nil || got != Deuteranopia {
+		t.Errorf("ParseDeficiency(\"deutan\") = %v, %v", got, err)
+	}
+	if _, err := ParseDeficiency("achromatopsia"); err == nil {
+		t.Error("ParseDeficiency() should reject unknown names")
+	}
+}
@@ -0,0 +1,74 @@
+package termcolor
+
+import "math"
+
+// HSL returns the hue in degrees [0, 360) and the saturation and lightness
+// in [0, 1].
+func (c RGB) HSL() (h, s, l float64) {
+	r, g, b := float64(c.R)/255, float64(c.G)/255, float64(c.B)/255
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	l = (maxC + minC) / 2
+
+	delta := maxC - minC
+	if delta == 0 {
+		return 0, 0, l
+	}
+	s = delta / (1 - math.Abs(2*l-1))
+	return hueDegrees(c), math.Min(s, 1), l
+}
+
+// FromHSL converts hue (degrees, any range), saturation and lightness
+// (clamped to [0, 1]) to RGB.
+func FromHSL(h, s, l float64) RGB {
+	h = math.Mod(h, 360)
+	if h < 0 {
+		h += 360
+	}
+	s = clamp01(s)
+	l = clamp01(l)
+
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return RGB{to8(r + m), to8(g + m), to8(b + m)}
+}
+
+// Scale multiplies each channel by f, clamped to 0-255.
+func (c RGB) Scale(f float64) RGB {
+	return RGB{to8(float64(c.R) * f / 255), to8(float64(c.G) * f / 255), to8(float64(c.B) * f / 255)}
+}
+
+// Lerp interpolates between c and d; t=0 gives c and t=1 gives d.
+func (c RGB) Lerp(d RGB, t float64) RGB {
+	t = clamp01(t)
+	mix := func(a, b uint8) uint8 {
+		return to8((float64(a) + (float64(b)-float64(a))*t) / 255)
+	}
+	return RGB{mix(c.R, d.R), mix(c.G, d.G), mix(c.B, d.B)}
+}
+
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// to8 converts a [0, 1] channel to 0-255, rounding and clamping
+func to8(v float64) uint8 {
+	return uint8(math.Round(clamp01(v) * 255))
+}
@@ -0,0 +1,99 @@
+package termcolor
+
+import "math"
+
+// OKLCH returns the color in OKLCH: perceptual lightness L in [0, 1],
+// chroma C (0 for grays, about 0.32 at most for sRGB) and hue h in degrees.
+//
+// Unlike HSL, equal steps in L look like equal steps in brightness across
+// hues, so gradients built on it neither clip saturated colors nor leave
+// dark colors unchanged.
+func (c RGB) OKLCH() (l, chroma, h float64) {
+	l, a, b := c.OKLab()
+	chroma = math.Hypot(a, b)
+	h = math.Atan2(b, a) * 180 / math.Pi
+	if h < 0 {
+		h += 360
+	}
+	return l, chroma, h
+}
+
+// OKLab returns the color in the OKLab space (L in [0, 1], a and b roughly
+// in [-0.4, 0.4]).
+func (c RGB) OKLab() (l, a, b float64) {
+	r, g, bl := toLinear(c.R), toLinear(c.G), toLinear(c.B)
+
+	lm := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*bl)
+	mm := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*bl)
+	sm := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*bl)
+
+	l = 0.2104542553*lm + 0.7936177850*mm - 0.0040720468*sm
+	a = 1.9779984951*lm - 2.4285922050*mm + 0.4505937099*sm
+	b = 0.0259040371*lm + 0.7827717662*mm - 0.8086757660*sm
+	return l, a, b
+}
+
+// FromOKLCH converts OKLCH to RGB. Colors outside the sRGB gamut keep their
+// lightness and hue and lose chroma until they fit, instead of being
+// clipped per channel (which shifts hue).
+func FromOKLCH(l, chroma, h float64) RGB {
+	l = clamp01(l)
+	if chroma < 0 {
+		chroma = 0
+	}
+
+	if r, g, b, ok := oklchToLinear(l, chroma, h); ok {
+		return RGB{fromLinear(r), fromLinear(g), fromLinear(b)}
+	}
+
+	// Bisect the largest in-gamut chroma
+	lo, hi := 0.0, chroma
+	for i := 0; i < 24; i++ {
+		mid := (lo + hi) / 2
+		if _, _, _, ok := oklchToLinear(l, mid, h); ok {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	r, g, b, _ := oklchToLinear(l, lo, h)
+	return RGB{fromLinear(r), fromLinear(g), fromLinear(b)}
+}
+
+// oklchToLinear converts OKLCH to linear sRGB, reporting whether the
+// result lies inside the gamut
+func oklchToLinear(l, chroma, h float64) (r, g, b float64, ok bool) {
+	rad := h * math.Pi / 180
+	a, bb := chroma*math.Cos(rad), chroma*math.Sin(rad)
+
+	lm := l + 0.3963377774*a + 0.2158037573*bb
+	mm := l - 0.1055613458*a - 0.0638541728*bb
+	sm := l - 0.0894841775*a - 1.2914855480*bb
+	lm, mm, sm = lm*lm*lm, mm*mm*mm, sm*sm*sm
+
+	r = 4.0767416621*lm - 3.3077115913*mm + 0.2309699292*sm
+	g = -1.2684380046*lm + 2.6097574011*mm - 0.3413193965*sm
+	b = -0.0041960863*lm - 0.7034186147*mm + 1.7076147010*sm
+
+	const eps = 1e-4
+	ok = r >= -eps && r <= 1+eps && g >= -eps && g <= 1+eps && b >= -eps && b <= 1+eps
+	return r, g, b, ok
+}
+
+// toLinear converts an sRGB channel to linear light in [0, 1]
+func toLinear(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+// fromLinear converts linear light to an sRGB channel
+func fromLinear(c float64) uint8 {
+	c = clamp01(c)
+	if c <= 0.0031308 {
+		return to8(12.92 * c)
+	}
+	return to8(1.055*math.Pow(c, 1/2.4) - 0.055)
+}
@@ -0,0 +1,207 @@
+package termcolor
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RGB is a 24-bit color.
+type RGB struct {
+	R, G, B uint8
+}
+
+// ParseHex parses "#RRGGBB" or "RRGGBB".
+func ParseHex(hex string) (RGB, error) {
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) != 6 {
+		return RGB{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	val, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return RGB{}, fmt.Errorf("invalid hex color %q", hex)
+	}
+	return RGB{R: uint8(val >> 16), G: uint8(val >> 8), B: uint8(val)}, nil
+}
+
+// Hex returns the color as "#RRGGBB".
+func (c RGB) Hex() string {
+	return fmt.Sprintf("#%02X%02X%02X", c.R, c.G, c.B)
+}
+
+// ansi16Palette holds the xterm defaults for the 16 standard colors.
+// Index 0-7 map to SGR 30-37, index 8-15 to SGR 90-97.
+var ansi16Palette = [16]RGB{
+	{0, 0, 0},       // black
+	{205, 0, 0},     // red
+	{0, 205, 0},     // green
+	{205, 205, 0},   // yellow
+	{0, 0, 238},     // blue
+	{205, 0, 205},   // magenta
+	{0, 205, 205},   // cyan
+	{229, 229, 229}, // white
+	{127, 127, 127}, // bright black
+	{255, 0, 0},     // bright red
+	{0, 255, 0},     // bright green
+	{255, 255, 0},   // bright yellow
+	{92, 92, 255},   // bright blue
+	{255, 0, 255},   // bright magenta
+	{0, 255, 255},   // bright cyan
+	{255, 255, 255}, // bright white
+}
+
+// cubeLevels are the channel values of the 6x6x6 color cube (indices 16-231).
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// Nearest256 returns the xterm 256-color index closest to c.
+// Only the color cube and grayscale ramp (16-255) are considered, because
+// the 16 system colors are commonly redefined by terminal themes.
+func Nearest256(c RGB) int {
+	ri, gi, bi := nearestCubeLevel(c.R), nearestCubeLevel(c.G), nearestCubeLevel(c.B)
+	cube := RGB{cubeLevels[ri], cubeLevels[gi], cubeLevels[bi]}
+	cubeIdx := 16 + 36*ri + 6*gi + bi
+
+	// Grayscale ramp: 232-255 = 8, 18, ..., 238
+	avg := (int(c.R) + int(c.G) + int(c.B)) / 3
+	grayStep := (avg - 8 + 5) / 10
+	if grayStep < 0 {
+		grayStep = 0
+	}
+	if grayStep > 23 {
+		grayStep = 23
+	}
+	grayVal := uint8(8 + grayStep*10)
+	gray := RGB{grayVal, grayVal, grayVal}
+
+	if distance(c, gray) < distance(c, cube) {
+		return 232 + grayStep
+	}
+	return cubeIdx
+}
+
+// Nearest16 returns the index (0-15) of the standard color closest to c.
+//
+// Plain RGB distance maps most pastel theme colors to gray, so chromatic
+// colors are matched by hue family first and by brightness second. Only
+// near-neutral colors fall back to the gray entries.
+func Nearest16(c RGB) int {
+	maxC := max(c.R, c.G, c.B)
+	minC := min(c.R, c.G, c.B)
+
+	// Near-black or near-neutral: pick from black, gray, white, bright white
+	if maxC < 48 || int(maxC-minC) < int(maxC)/5 {
+		switch avg := (int(c.R) + int(c.G) + int(c.B)) / 3; {
+		case avg < 64:
+			return 0
+		case avg < 160:
+			return 8
+		case avg < 224:
+			return 7
+		default:
+			return 15
+		}
+	}
+
+	// Hue families in SGR order: red, green, yellow, blue, magenta, cyan
+	// expressed as the (R, G, B) bits of the ANSI color number.
+	hue := hueDegrees(c)
+	var idx int
+	switch {
+	case hue < 30 || hue >= 330:
+		idx = 1 // red
+	case hue < 90:
+		idx = 3 // yellow
+	case hue < 150:
+		idx = 2 // green
+	case hue < 210:
+		idx = 6 // cyan
+	case hue < 270:
+		idx = 4 // blue
+	default:
+		idx = 5 // magenta
+	}
+
+	if maxC >= 200 {
+		idx += 8
+	}
+	return idx
+}
+
+// hueDegrees returns the HSV hue of c in [0, 360).
+func hueDegrees(c RGB) float64 {
+	r, g, b := float64(c.R), float64(c.G), float64(c.B)
+	maxC := math.Max(r, math.Max(g, b))
+	minC := math.Min(r, math.Min(g, b))
+	delta := maxC - minC
+	if delta == 0 {
+		return 0
+	}
+
+	var h float64
+	switch maxC {
+	case r:
+		h = math.Mod((g-b)/delta, 6)
+	case g:
+		h = (b-r)/delta + 2
+	default:
+		h = (r-g)/delta + 4
+	}
+	h *= 60
+	if h < 0 {
+		h += 360
+	}
+	return h
+}
+
+// Palette256 returns the RGB value of an xterm 256-color index.
+func Palette256(idx int) RGB {
+	switch {
+	case idx < 0:
+		return RGB{}
+	case idx < 16:
+		return ansi16Palette[idx]
+	case idx < 232:
+		idx -= 16
+		return RGB{cubeLevels[idx/36], cubeLevels[(idx/6)%6], cubeLevels[idx%6]}
+	case idx < 256:
+		v := uint8(8 + (idx-232)*10)
+		return RGB{v, v, v}
+	default:
+		return RGB{255, 255, 255}
+	}
+}
+
+// Quantize returns the color the terminal will actually display for c under
+// profile p. NoColor returns c unchanged.
+func Quantize(c RGB, p Profile) RGB {
+	switch p {
+	case ANSI256:
+		return Palette256(Nearest256(c))
+	case ANSI16:
+		return ansi16Palette[Nearest16(c)]
+	default:
+		return c
+	}
+}
+
+func nearestCubeLevel(v uint8) int {
+	if v < 48 {
+		return 0
+	}
+	if v < 115 {
+		return 1
+	}
+	return int((v - 35) / 40)
+}
+
+// distance is the "redmean" weighted RGB distance (squared, scaled by 256).
+// It is cheap and tracks perceived difference far better than plain RGB,
+// which matters most when snapping to the coarse 16-color palette.
+func distance(a, b RGB) int {
+	rmean := (int(a.R) + int(b.R)) / 2
+	dr := int(a.R) - int(b.R)
+	dg := int(a.G) - int(b.G)
+	db := int(a.B) - int(b.B)
+	return (512+rmean)*dr*dr + 1024*dg*dg + (767-rmean)*db*db
+}
@@ -0,0 +1,125 @@
+package termcolor
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseHex(t *testing.T) {
+	c, err := ParseHex("#FF8800")
+	if err != nil {
+		t.Fatalf("ParseHex error = %v", err)
+	}
+	if c != (RGB{255, 136, 0}) {
+		t.Errorf("ParseHex(#FF8800) = %v", c)
+	}
+	if c.Hex() != "#FF8800" {
+		t.Errorf("Hex() = %q, want #FF8800", c.Hex())
+	}
+
+	for _, bad := range []string{"", "FFF", "#GGGGGG", "#FFFFFFF"} {
+		if _, err := ParseHex(bad); err == nil {
+			t.Errorf("ParseHex(%q) should fail", bad)
+		}
+	}
+}
+
+func TestNearest256(t *testing.T) {
+	tests := []struct {
+		name string
+		c    RGB
+		want int
+	}{
+		{"pure red", RGB{255, 0, 0}, 196},
+		{"pure green", RGB{0, 255, 0}, 46},
+		{"pure blue", RGB{0, 0, 255}, 21},
+		{"white", RGB{255, 255, 255}, 231},
+		{"black", RGB{0, 0, 0}, 16},
+		{"mid gray uses ramp", RGB{128, 128, 128}, 244},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Nearest256(tt.c); got != tt.want {
+				t.Errorf("Nearest256(%v) = %d, want %d", tt.c, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearest16(t *testing.T) {
+	tests := []struct {
+		c    RGB
+		want int
+	}{
+		{RGB{255, 0, 0}, 9},
+		{RGB{150, 10, 10}, 1},
+		{RGB{0, 0, 0}, 0},
+		{RGB{250, 250, 250}, 15},
+		{RGB{128, 128, 128}, 8},
+		{RGB{0, 90, 255}, 12},    // blue
+		{RGB{231, 130, 132}, 9},  // latte coral stays red, not gray
+		{RGB{166, 209, 137}, 10}, // latte sage
+		{RGB{143, 179, 120}, 2},  // garden moss
+		{RGB{202, 158, 230}, 13}, // latte lavender
+	}
+
+	for _, tt := range tests {
+		if got := Nearest16(tt.c); got != tt.want {
+			t.Errorf("Nearest16(%v) = %d, want %d", tt.c, got, tt.want)
+		}
+	}
+}
+
+func TestPalette256RoundTrip(t *testing.T) {
+	for idx := 16; idx < 256; idx++ {
+		if got := Nearest256(Palette256(idx)); Palette256(got) != Palette256(idx) {
+			t.Errorf("Nearest256(Palette256(%d)) = %d, colors differ", idx, got)
+		}
+	}
+}
+
+func TestHSLRoundTrip(t *testing.T) {
+	colors := []RGB{{255, 0, 0}, {231, 130, 132}, {0, 136, 255}, {128, 128, 128}, {0, 0, 0}}
+	for _, c := range colors {
+		h, s, l := c.HSL()
+		if got := FromHSL(h, s, l); got != c {
+			t.Errorf("FromHSL(%v.HSL()) = %v", c, got)
+		}
+	}
+}
+
+func TestOKLCHRoundTrip(t *testing.T) {
+	colors := []RGB{{255, 0, 0}, {231, 130, 132}, {0, 136, 255}, {128, 128, 128}, {0, 0, 0}, {255, 255, 255}}
+	for _, c := range colors {
+		l, chroma, h := c.OKLCH()
+		if got := FromOKLCH(l, chroma, h); got != c {
+			t.Errorf("FromOKLCH(%v.OKLCH()) = %v", c, got)
+		}
+	}
+}
+
+func TestOKLCHKnownValues(t *testing.T) {
+	// Reference values from the OKLab paper's sRGB conversion
+	l, chroma, h := RGB{255, 0, 0}.OKLCH()
+	if math.Abs(l-0.628) > 0.001 || math.Abs(chroma-0.2577) > 0.001 || math.Abs(h-29.23) > 0.1 {
+		t.Errorf("red OKLCH = (%.4f, %.4f, %.2f), want (0.628, 0.2577, 29.23)", l, chroma, h)
+	}
+	if l, _, _ := (RGB{255, 255, 255}).OKLCH(); math.Abs(l-1) > 0.001 {
+		t.Errorf("white L = %.4f, want 1", l)
+	}
+}
+
+func TestFromOKLCHGamutMapping(t *testing.T) {
+	// Full red chroma at high lightness is outside sRGB: the result must
+	// keep the requested lightness and stay reddish instead of clipping
+	_, chroma, h := RGB{255, 0, 0}.OKLCH()
+	got := FromOKLCH(0.9, chroma, h)
+	l, _, gotH := got.OKLCH()
+	if math.Abs(l-0.9) > 0.01 {
+		t.Errorf("FromOKLCH(0.9, ...) L = %.3f, want 0.9", l)
+	}
+	if math.Abs(gotH-h) > 5 {
+		t.Errorf("FromOKLCH(0.9, ...) hue = %.1f, want about %.1f", gotH, h)
+	}
+}
@@ -0,0 +1,106 @@
+// Package termcolor renders RGB colors as terminal escape sequences, degrading
+// gracefully on terminals that cannot display 24-bit color.
+package termcolor
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Profile describes how many colors a terminal can display.
+type Profile int
+
+const (
+	// TrueColor emits 24-bit "38;2;R;G;B" escapes.
+	TrueColor Profile = iota
+	// ANSI256 emits xterm 256-color "38;5;N" escapes.
+	ANSI256
+	// ANSI16 emits the 16 standard colors (30-37, 90-97).
+	ANSI16
+	// NoColor emits no escapes at all.
+	NoColor
+)
+
+// String returns the profile name as accepted by ParseProfile.
+func (p Profile) String() string {
+	switch p {
+	case TrueColor:
+		return "truecolor"
+	case ANSI256:
+		return "ansi256"
+	case ANSI16:
+		return "ansi16"
+	case NoColor:
+		return "none"
+	default:
+		return fmt.Sprintf("Profile(%d)", int(p))
+	}
+}
+
+// ParseProfile converts a profile name (e.g. from a CLI flag) to a Profile.
+// Accepts "truecolor", "24bit", "ansi256", "256", "ansi16", "16", "none" and "off".
+func ParseProfile(name string) (Profile, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "truecolor", "24bit":
+		return TrueColor, nil
+	case "ansi256", "256":
+		return ANSI256, nil
+	case "ansi16", "16", "ansi":
+		return ANSI16, nil
+	case "none", "off", "nocolor":
+		return NoColor, nil
+	default:
+		return NoColor, fmt.Errorf("unknown color profile %q", name)
+	}
+}
+
+// Detect returns the color profile of the current terminal, based on the
+// NO_COLOR, COLORTERM and TERM environment variables.
+func Detect() Profile {
+	return DetectFromEnv(os.Getenv)
+}
+
+// DetectFromEnv is Detect with an injectable environment lookup.
+//
+// Rules, in priority order:
+//   - NO_COLOR set to any non-empty value disables color (https://no-color.org)
+//   - COLORTERM=truecolor or 24bit selects TrueColor
+//   - TERM=dumb disables color
+//   - TERM containing "truecolor", "24bit" or "direct" selects TrueColor
+//   - TERM containing "256color" selects ANSI256
+//   - Windows Terminal (WT_SESSION) selects TrueColor
+//   - any other COLORTERM value selects ANSI256
+//   - any other non-empty TERM selects ANSI16 (linux console, screen, tmux, vt100)
+//   - no TERM at all (pipes, most CI runners) disables color
+func DetectFromEnv(getenv func(string) string) Profile {
+	if getenv("NO_COLOR") != "" {
+		return NoColor
+	}
+
+	colorterm := strings.ToLower(getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		return TrueColor
+	}
+
+	term := strings.ToLower(getenv("TERM"))
+	switch {
+	case term == "dumb":
+		return NoColor
+	case strings.Contains(term, "truecolor"), strings.Contains(term, "24bit"), strings.Contains(term, "direct"):
+		return TrueColor
+	case strings.Contains(term, "256color"):
+		return ANSI256
+	}
+
+	if getenv("WT_SESSION") != "" {
+		return TrueColor
+	}
+	if colorterm != "" {
+		return ANSI256
+	}
+	if term != "" {
+		return ANSI16
+	}
+	return NoColor
+}
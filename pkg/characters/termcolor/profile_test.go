@@ -0,0 +1,49 @@
+package termcolor
+
+import "testing"
+
+func TestDetectFromEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want Profile
+	}{
+		{"no color wins", map[string]string{"NO_COLOR": "1", "COLORTERM": "truecolor"}, NoColor},
+		{"colorterm truecolor", map[string]string{"COLORTERM": "truecolor", "TERM": "xterm"}, TrueColor},
+		{"colorterm 24bit", map[string]string{"COLORTERM": "24bit"}, TrueColor},
+		{"dumb terminal", map[string]string{"TERM": "dumb"}, NoColor},
+		{"direct color term", map[string]string{"TERM": "xterm-direct"}, TrueColor},
+		{"tmux 256", map[string]string{"TERM": "tmux-256color"}, ANSI256},
+		{"screen 256", map[string]string{"TERM": "screen-256color"}, ANSI256},
+		{"windows terminal", map[string]string{"WT_SESSION": "abc"}, TrueColor},
+		{"other colorterm", map[string]string{"COLORTERM": "yes", "TERM": "xterm"}, ANSI256},
+		{"linux console", map[string]string{"TERM": "linux"}, ANSI16},
+		{"plain xterm", map[string]string{"TERM": "xterm"}, ANSI16},
+		{"no term", map[string]string{}, NoColor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectFromEnv(func(key string) string { return tt.env[key] })
+			if got != tt.want {
+				t.Errorf("DetectFromEnv(%v) = %v, want %v", tt.env, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	for _, p := range []Profile{TrueColor, ANSI256, ANSI16, NoColor} {
+		got, err := ParseProfile(p.String())
+		if err != nil {
+			t.Fatalf("ParseProfile(%q) error = %v", p.String(), err)
+		}
+		if got != p {
+			t.Errorf("ParseProfile(%q) = %v, want %v", p.String(), got, p)
+		}
+	}
+
+	if _, err := ParseProfile("rainbow"); err == nil {
+		t.Error("ParseProfile(rainbow) should fail")
+	}
+}
@@ -0,0 +1,155 @@
+package termcolor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Reset is the SGR sequence that clears all attributes.
+const Reset = "\x1b[0m"
+
+// Renderer converts colors to escape sequences for a fixed Profile.
+type Renderer struct {
+	profile Profile
+}
+
+// NewRenderer creates a renderer for the given profile.
+func NewRenderer(p Profile) *Renderer {
+	return &Renderer{profile: p}
+}
+
+// Profile returns the renderer's color profile.
+func (r *Renderer) Profile() Profile {
+	return r.profile
+}
+
+// Foreground returns the escape sequence that sets the foreground to c,
+// quantized to the renderer's profile. Returns "" for NoColor.
+func (r *Renderer) Foreground(c RGB) string {
+	switch r.profile {
+	case TrueColor:
+		return "\x1b[38;2;" + strconv.Itoa(int(c.R)) + ";" + strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B)) + "m"
+	case ANSI256:
+		return "\x1b[38;5;" + strconv.Itoa(Nearest256(c)) + "m"
+	case ANSI16:
+		idx := Nearest16(c)
+		if idx < 8 {
+			return "\x1b[" + strconv.Itoa(30+idx) + "m"
+		}
+		return "\x1b[" + strconv.Itoa(90+idx-8) + "m"
+	default:
+		return ""
+	}
+}
+
+// Colorize wraps text in c and a trailing reset. NoColor returns text unchanged.
+func (r *Renderer) Colorize(text string, c RGB) string {
+	seq := r.Foreground(c)
+	if seq == "" {
+		return text
+	}
+	return seq + text + Reset
+}
+
+// ColorizeHex is Colorize for a "#RRGGBB" color.
+// Returns text unchanged if hex is empty or invalid.
+func (r *Renderer) ColorizeHex(text, hex string) string {
+	if hex == "" {
+		return text
+	}
+	c, err := ParseHex(hex)
+	if err != nil {
+		return text
+	}
+	return r.Colorize(text, c)
+}
+
+// Default renderer shared by the characters packages.
+var (
+	defaultMu       sync.RWMutex
+	defaultRenderer *Renderer
+)
+
+// Default returns the process-wide renderer. On first use the profile is
+// detected from the environment; call SetProfile to override it.
+func Default() *Renderer {
+	defaultMu.RLock()
+	r := defaultRenderer
+	defaultMu.RUnlock()
+	if r != nil {
+		return r
+	}
+
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if defaultRenderer == nil {
+		defaultRenderer = NewRenderer(Detect())
+	}
+	return defaultRenderer
+}
+
+// SetProfile replaces the process-wide renderer's profile.
+func SetProfile(p Profile) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultRenderer = NewRenderer(p)
+}
+
+// CurrentProfile returns the process-wide renderer's profile.
+func CurrentProfile() Profile {
+	return Default().Profile()
+}
+
+// sgrRegex matches any SGR (Select Graphic Rendition) escape sequence.
+var sgrRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Strip removes all SGR escape sequences from s.
+func Strip(s string) string {
+	return sgrRegex.ReplaceAllString(s, "")
+}
+
+// ExtractForeground returns the first foreground color set in s, whatever
+// profile it was rendered with. 256 and 16-color indices are mapped back to
+// their nominal RGB values.
+func ExtractForeground(s string) (RGB, bool) {
+	for _, seq := range sgrRegex.FindAllString(s, -1) {
+		params := strings.Split(strings.TrimSuffix(strings.TrimPrefix(seq, "\x1b["), "m"), ";")
+		if c, ok := foregroundFromParams(params); ok {
+			return c, true
+		}
+	}
+	return RGB{}, false
+}
+
+func foregroundFromParams(params []string) (RGB, bool) {
+	for i := 0; i < len(params); i++ {
+		n, err := strconv.Atoi(params[i])
+		if err != nil {
+			continue
+		}
+		switch {
+		case n == 38 && i+4 < len(params) && params[i+1] == "2":
+			r, _ := strconv.Atoi(params[i+2])
+			g, _ := strconv.Atoi(params[i+3])
+			b, _ := strconv.Atoi(params[i+4])
+			return RGB{uint8(r), uint8(g), uint8(b)}, true
+		case n == 38 && i+2 < len(params) && params[i+1] == "5":
+			idx, _ := strconv.Atoi(params[i+2])
+			return Palette256(idx), true
+		case n == 48:
+			// Skip background color arguments
+			if i+1 < len(params) && params[i+1] == "2" {
+				i += 4
+			} else {
+				i += 2
+			}
+		case n >= 30 && n <= 37:
+			return ansi16Palette[n-30], true
+		case n >= 90 && n <= 97:
+			return ansi16Palette[n-90+8], true
+		}
+	}
+	return RGB{}, false
+}
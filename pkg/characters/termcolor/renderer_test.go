@@ -0,0 +1,79 @@
+package termcolor
+
+import "testing"
+
+func TestRendererForeground(t *testing.T) {
+	orange := RGB{255, 107, 53}
+
+	tests := []struct {
+		profile Profile
+		want    string
+	}{
+		{TrueColor, "\x1b[38;2;255;107;53m"},
+		{ANSI256, "\x1b[38;5;203m"},
+		{ANSI16, "\x1b[91m"},
+		{NoColor, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.profile.String(), func(t *testing.T) {
+			if got := NewRenderer(tt.profile).Foreground(orange); got != tt.want {
+				t.Errorf("Foreground() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRendererColorizeHex(t *testing.T) {
+	r := NewRenderer(TrueColor)
+	if got := r.ColorizeHex("x", "#C0C0C0"); got != "\x1b[38;2;192;192;192mx\x1b[0m" {
+		t.Errorf("ColorizeHex = %q", got)
+	}
+	if got := r.ColorizeHex("x", ""); got != "x" {
+		t.Errorf("ColorizeHex with empty color = %q, want unchanged", got)
+	}
+	if got := NewRenderer(NoColor).ColorizeHex("x", "#C0C0C0"); got != "x" {
+		t.Errorf("NoColor ColorizeHex = %q, want unchanged", got)
+	}
+}
+
+func TestExtractForeground(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want RGB
+		ok   bool
+	}{
+		{"truecolor", "\x1b[38;2;10;20;30mx\x1b[0m", RGB{10, 20, 30}, true},
+		{"256 cube", "\x1b[38;5;196mx\x1b[0m", RGB{255, 0, 0}, true},
+		{"16 bright", "\x1b[91mx\x1b[0m", RGB{255, 0, 0}, true},
+		{"16 normal", "\x1b[1;32mx\x1b[0m", RGB{0, 205, 0}, true},
+		{"background skipped", "\x1b[48;5;21;38;5;46mx", RGB{0, 255, 0}, true},
+		{"plain", "x", RGB{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ExtractForeground(tt.s)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ExtractForeground(%q) = %v, %v; want %v, %v", tt.s, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestStrip(t *testing.T) {
+	if got := Strip("\x1b[38;5;196m▐█\x1b[0m▌"); got != "▐█▌" {
+		t.Errorf("Strip = %q", got)
+	}
+}
+
+func TestSetProfile(t *testing.T) {
+	orig := CurrentProfile()
+	defer SetProfile(orig)
+
+	SetProfile(ANSI16)
+	if CurrentProfile() != ANSI16 {
+		t.Errorf("CurrentProfile() = %v, want ansi16", CurrentProfile())
+	}
+}
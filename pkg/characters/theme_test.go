@@ -3,7 +3,9 @@ package characters
 import (
 	"testing"
 
+	"github.com/wildreason/tangent/pkg/characters/domain"
 	"github.com/wildreason/tangent/pkg/characters/library"
+	"github.com/wildreason/tangent/pkg/characters/termcolor"
 )
 
 func TestSetTheme(t *testing.T) {
@@ -134,6 +136,32 @@ func TestLibraryAgentUsesTheme(t *testing.T) {
 	}
 }
 
+func TestLibraryAgentUsesThemePalette(t *testing.T) {
+	originalTheme := GetCurrentTheme()
+	defer func() {
+		SetTheme(originalTheme)
+	}()
+
+	SetTheme("garden")
+	for _, load := range []func(string) (*AgentCharacter, error){LibraryAgent, LibraryAgentMicro} {
+		agent, err := load("sam")
+		if err != nil {
+			t.Fatalf("load error = %v", err)
+		}
+
+		char := agent.GetCharacter()
+		if got := char.SlotColor(domain.PaletteBody); got != library.Theme3ColorSa {
+			t.Errorf("%s body = %v, want %v", char.Name, got, library.Theme3ColorSa)
+		}
+		if got := char.SlotColor(domain.PaletteEyes); got != library.Theme3Eyes {
+			t.Errorf("%s eyes = %v, want %v", char.Name, got, library.Theme3Eyes)
+		}
+		if got := char.SlotColor(domain.PaletteAccent); got != library.Theme3Accent {
+			t.Errorf("%s accent = %v, want %v", char.Name, got, library.Theme3Accent)
+		}
+	}
+}
+
 func TestLibraryAgentThemeColors(t *testing.T) {
 	// Save original theme
 	originalTheme := GetCurrentTheme()
@@ -219,3 +247,85 @@ func TestThemeAPIMinimal(t *testing.T) {
 		t.Errorf("Expected latte theme color %v, got %v", library.Theme2ColorSa, char.Color)
 	}
 }
+
+func TestAutoThemeFor(t *testing.T) {
+	originalTheme := GetCurrentTheme()
+	defer func() {
+		SetTheme(originalTheme)
+	}()
+
+	// The built-in themes are all dark: on light backgrounds the best
+	// contrasting theme is picked and its colors adjusted
+	tests := []struct {
+		name     string
+		bg       termcolor.RGB
+		affinity string // "" when no theme matches
+		minRaw   float64
+	}{
+		{"black", termcolor.RGB{}, library.AffinityDark, MinThemeContrast},
+		{"dark gray", termcolor.RGB{R: 30, G: 30, B: 46}, library.AffinityDark, MinThemeContrast},
+		{"white", termcolor.RGB{R: 255, G: 255, B: 255}, "", 0},
+		{"solarized light", termcolor.RGB{R: 253, G: 246, B: 227}, "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := AutoThemeFor(tt.bg)
+			if name != GetCurrentTheme() {
+				t.Errorf("AutoThemeFor() = %q, current theme = %q", name, GetCurrentTheme())
+			}
+			theme, _ := library.GetTheme(name)
+			if tt.affinity != "" && theme.Affinity != tt.affinity {
+				t.Errorf("AutoThemeFor() = %q with affinity %q, want %q", name, theme.Affinity, tt.affinity)
+			}
+
+			// The theme's own colors, before adjustment, contrast best
+			raw := themeContrast(theme, tt.bg)
+			if raw < tt.minRaw {
+				t.Errorf("AutoThemeFor() = %q with unadjusted contrast %.2f, want >= %.1f", name, raw, tt.minRaw)
+			}
+			for _, other := range library.ListThemes() {
+				candidate, _ := library.GetTheme(other)
+				if tt.affinity != "" && candidate.Affinity != tt.affinity {
+					continue
+				}
+				if c := themeContrast(candidate, tt.bg); c > raw {
+					t.Errorf("AutoThemeFor() = %q (%.2f), but %q contrasts better (%.2f)", name, raw, other, c)
+				}
+			}
+
+			// Every body color meets the minimum contrast
+			for _, charName := range library.AllCharacterNames() {
+				agent, err := LibraryAgent(charName)
+				if err != nil {
+					t.Fatalf("LibraryAgent(%q) error = %v", charName, err)
+				}
+				char := agent.GetCharacter()
+				c, _ := termcolor.ParseHex(char.Color)
+				if ratio := termcolor.ContrastRatio(c, tt.bg); ratio < MinThemeContrast-0.01 {
+					t.Errorf("%s color %s contrast = %.2f, want >= %.1f", charName, char.Color, ratio, MinThemeContrast)
+				}
+				if char.SlotColor(domain.PaletteBody) != char.Color {
+					t.Errorf("%s body slot = %s, want %s", charName, char.SlotColor(domain.PaletteBody), char.Color)
+				}
+			}
+		})
+	}
+}
+
+func TestSetThemeClearsAutoAdjustment(t *testing.T) {
+	originalTheme := GetCurrentTheme()
+	defer func() {
+		SetTheme(originalTheme)
+	}()
+
+	AutoThemeFor(termcolor.RGB{R: 255, G: 255, B: 255})
+	SetTheme("latte")
+	agent, err := LibraryAgent("sam")
+	if err != nil {
+		t.Fatalf("LibraryAgent() error = %v", err)
+	}
+	if got := agent.GetCharacter().Color; got != library.Theme2ColorSa {
+		t.Errorf("color after SetTheme = %v, want %v", got, library.Theme2ColorSa)
+	}
+}